@@ -0,0 +1,490 @@
+// Command views packages views folders into signed bundles and moves them
+// to and from the remote source the engine loads them from, it also writes
+// the Go file embedding a views folder for go:generate, checks the
+// includes of the views and what needs attention when migrating them from
+// gofiber/template/html, and formats them.
+//
+//	views bundle -dir ./views -out views.tar.gz [-hmac-key env:VIEWS_KEY | -ed25519-key key.pem]
+//	views push -url https://templates.example.com/views.tar.gz [-token ...] views.tar.gz
+//	views pull -url https://templates.example.com/views.tar.gz [-token ...] [-hmac-key env:VIEWS_KEY | -ed25519-pub pub.pem | -unsigned] -dir ./views
+//	views check -dir ./views [-layout layouts/main]
+//	views migrate -dir ./views [-embed embed]
+//	views fmt [-l] [-w] [-ext .html] ./views
+//	views generate -dir views [-layout layouts/main] [-configure setupViews] [-out views_gen.go]
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/znbang/gofiber-layout/html"
+)
+
+// stdout receives the output of the commands
+var stdout io.Writer = os.Stdout
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "bundle":
+		err = bundle(os.Args[2:])
+	case "push":
+		err = push(os.Args[2:])
+	case "pull":
+		err = pull(os.Args[2:])
+	case "generate":
+		err = generate(os.Args[2:])
+	case "check":
+		err = check(os.Args[2:])
+	case "migrate":
+		err = migrate(os.Args[2:])
+	case "fmt":
+		err = format(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "views %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: views bundle|push|pull|generate|check|migrate|fmt [flags]")
+	os.Exit(2)
+}
+
+// bundle packages a views folder with its manifest and signature.
+func bundle(args []string) error {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	dir := flags.String("dir", "./views", "views folder")
+	out := flags.String("out", "views.tar.gz", "bundle file")
+	hmacKey := flags.String("hmac-key", "", "HMAC key, env:NAME reads it from the environment")
+	edKey := flags.String("ed25519-key", "", "PEM file of the PKCS #8 ed25519 private key")
+	signature := flags.String("signature", html.SignatureFile, "signature file, as passed to VerifySignature")
+	flags.Parse(args)
+
+	sign, err := signer(*hmacKey, *edKey)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	manifest, err := html.WriteBundle(&buf, os.DirFS(*dir), *signature, sign)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: %d files, version %s\n", *out, len(manifest.Files), manifest.Version)
+	return nil
+}
+
+// signer returns the func signing the bundle digest, nil without key.
+func signer(hmacKey, edKey string) (func(digest []byte) ([]byte, error), error) {
+	switch {
+	case hmacKey != "" && edKey != "":
+		return nil, fmt.Errorf("-hmac-key and -ed25519-key are exclusive")
+	case hmacKey != "":
+		key := secret(hmacKey)
+		return func(digest []byte) ([]byte, error) {
+			return html.SignHMAC(key, digest), nil
+		}, nil
+	case edKey != "":
+		block, err := readPEM(edKey)
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		priv, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s: not an ed25519 key", edKey)
+		}
+		return func(digest []byte) ([]byte, error) {
+			return ed25519.Sign(priv, digest), nil
+		}, nil
+	}
+	return nil, nil
+}
+
+// verifier returns the verifier of the bundle signature, nil without key.
+func verifier(hmacKey, edPub string) (html.Verifier, error) {
+	switch {
+	case hmacKey != "" && edPub != "":
+		return nil, fmt.Errorf("-hmac-key and -ed25519-pub are exclusive")
+	case hmacKey != "":
+		return html.HMACVerifier(secret(hmacKey)), nil
+	case edPub != "":
+		block, err := readPEM(edPub)
+		if err != nil {
+			return nil, err
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%s: not an ed25519 key", edPub)
+		}
+		return html.Ed25519Verifier(pub), nil
+	}
+	return nil, nil
+}
+
+// secret returns the HMAC key, env:NAME reads it from the environment.
+func secret(key string) []byte {
+	if name := strings.TrimPrefix(key, "env:"); name != key {
+		key = os.Getenv(name)
+	}
+	return []byte(key)
+}
+
+// readPEM returns the first PEM block of the file.
+func readPEM(file string) (*pem.Block, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block", file)
+	}
+	return block, nil
+}
+
+// remoteFlags are the flags of push and pull
+func remoteFlags(name string) (*flag.FlagSet, *string, *string) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	url := flags.String("url", os.Getenv("VIEWS_URL"), "bundle URL, defaults to $VIEWS_URL")
+	token := flags.String("token", os.Getenv("VIEWS_TOKEN"), "bearer token, defaults to $VIEWS_TOKEN")
+	return flags, url, token
+}
+
+// push uploads a bundle with a PUT request.
+func push(args []string) error {
+	flags, url, token := remoteFlags("push")
+	flags.Parse(args)
+	if *url == "" || flags.NArg() != 1 {
+		return fmt.Errorf("usage: views push -url URL bundle")
+	}
+	buf, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	// Refuse to push a broken bundle
+	if _, err := html.ReadBundle(bytes.NewReader(buf)); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, *url, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType(buf))
+	resp, err := do(req, *token)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	fmt.Fprintf(stdout, "pushed %s to %s\n", flags.Arg(0), *url)
+	return nil
+}
+
+// contentType returns the media type of the bundle format.
+func contentType(bundle []byte) string {
+	switch {
+	case bytes.HasPrefix(bundle, []byte("PK\x03\x04")):
+		return "application/zip"
+	case bytes.HasPrefix(bundle, []byte{0x1f, 0x8b}):
+		return "application/gzip"
+	default:
+		return "application/x-tar"
+	}
+}
+
+// pull downloads a bundle, verifies its signature and unpacks it into a
+// folder, removing the files of the folder the bundle no longer has.
+func pull(args []string) error {
+	flags, url, token := remoteFlags("pull")
+	dir := flags.String("dir", "./views", "folder the bundle is unpacked into")
+	hmacKey := flags.String("hmac-key", "", "HMAC key, env:NAME reads it from the environment")
+	edPub := flags.String("ed25519-pub", "", "PEM file of the PKIX ed25519 public key")
+	signature := flags.String("signature", html.SignatureFile, "signature file, as passed to VerifySignature")
+	unsigned := flags.Bool("unsigned", false, "unpack the bundle without verifying its signature")
+	flags.Parse(args)
+	if *url == "" {
+		return fmt.Errorf("usage: views pull -url URL [-dir folder]")
+	}
+	v, err := verifier(*hmacKey, *edPub)
+	if err != nil {
+		return err
+	}
+	if v == nil && !*unsigned {
+		return fmt.Errorf("-hmac-key or -ed25519-pub is required, -unsigned skips the verification")
+	}
+	req, err := http.NewRequest(http.MethodGet, *url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := do(req, *token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bundle, err := html.ReadBundle(resp.Body)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		if err := verify(bundle, v, *signature); err != nil {
+			return err
+		}
+	}
+	files := make(map[string]bool)
+	err = fs.WalkDir(bundle, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		buf, err := fs.ReadFile(bundle, path)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(*dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		files[file] = true
+		return os.WriteFile(file, buf, 0o644)
+	})
+	if err != nil {
+		return err
+	}
+	removed, err := removeStale(*dir, files)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "pulled %d files into %s, removed %d\n", len(files), *dir, removed)
+	return nil
+}
+
+// verify checks the signature of the bundle, read from its file signature.
+func verify(bundle fs.FS, v html.Verifier, signature string) error {
+	encoded, err := fs.ReadFile(bundle, signature)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("signature: %s: %v", signature, err)
+	}
+	digest, err := html.BundleDigest(bundle, signature)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	return v.Verify(digest, sig)
+}
+
+// removeStale removes the files of dir not in files, then the folders left
+// empty, and returns the number of files removed.
+func removeStale(dir string, files map[string]bool) (int, error) {
+	removed := 0
+	var folders []string
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if file != dir {
+				folders = append(folders, file)
+			}
+			return nil
+		}
+		if files[file] {
+			return nil
+		}
+		removed++
+		return os.Remove(file)
+	})
+	if err != nil {
+		return removed, err
+	}
+	// Deepest first, removing a folder that isn't empty fails
+	for i := len(folders) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(folders[i]); err == nil && len(entries) == 0 {
+			os.Remove(folders[i])
+		}
+	}
+	return removed, nil
+}
+
+// do sends req with the bearer token and fails on error statuses.
+func do(req *http.Request, token string) (*http.Response, error) {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s %s", req.Method, req.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// generate writes the Go file embedding a views folder of the package in
+// the current folder, $GOPACKAGE is set by go generate.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	pkg := flags.String("pkg", os.Getenv("GOPACKAGE"), "package name, defaults to $GOPACKAGE")
+	dir := flags.String("dir", "views", "views folder relative to the package")
+	ext := flags.String("ext", ".html", "views extension")
+	layout := flags.String("layout", "", "layout name without extension")
+	name := flags.String("var", "Views", "name of the engine variable")
+	configure := flags.String("configure", "", "func(*html.Engine) of the package called with the engine")
+	out := flags.String("out", "views_gen.go", "generated file")
+	flags.Parse(args)
+
+	var buf bytes.Buffer
+	err := html.Generate(&buf, os.DirFS("."), html.GenerateConfig{
+		Package:   *pkg,
+		Dir:       *dir,
+		Extension: *ext,
+		Layout:    *layout,
+		Var:       *name,
+		Configure: *configure,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, buf.Bytes(), 0o644)
+}
+
+// check reports the missing and unreferenced templates of a views folder
+// and fails if any include is missing.
+func check(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	dir := flags.String("dir", "./views", "views folder")
+	ext := flags.String("ext", ".html", "views extension")
+	layout := flags.String("layout", "", "layout name without extension")
+	left := flags.String("left", "{{", "left action delimiter")
+	right := flags.String("right", "}}", "right action delimiter")
+	partials := flags.String("partials", "", "folder of the partials parsed into every view")
+	flags.Parse(args)
+
+	analysis, err := html.Analyze(os.DirFS(*dir), html.AnalyzeConfig{
+		Extension: *ext,
+		Layout:    *layout,
+		Delims:    [2]string{*left, *right},
+		Partials:  *partials,
+	})
+	if err != nil {
+		return err
+	}
+	for _, issue := range analysis.Unreferenced {
+		fmt.Fprintf(stdout, "%s: template %s is never included\n", issue.Location, issue.Name)
+	}
+	for _, issue := range analysis.Missing {
+		fmt.Fprintf(stdout, "%s: template %s does not exist\n", issue.Location, issue.Name)
+	}
+	if len(analysis.Missing) > 0 {
+		return fmt.Errorf("%d missing templates", len(analysis.Missing))
+	}
+	return nil
+}
+
+// migrate lists what needs attention in views written for
+// gofiber/template/html.
+func migrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := flags.String("dir", "./views", "views folder")
+	ext := flags.String("ext", ".html", "views extension")
+	embed := flags.String("embed", "embed", "name of the func including the page, the upstream Layout setting")
+	left := flags.String("left", "{{", "left action delimiter")
+	right := flags.String("right", "}}", "right action delimiter")
+	flags.Parse(args)
+
+	notes, err := html.Migrate(os.DirFS(*dir), html.AnalyzeConfig{
+		Extension: *ext,
+		Layout:    *embed,
+		Delims:    [2]string{*left, *right},
+	})
+	if err != nil {
+		return err
+	}
+	for _, note := range notes {
+		fmt.Fprintln(stdout, note)
+	}
+	return nil
+}
+
+// format formats the views of the folders and files given, printing them
+// unless -w rewrites them or -l lists the ones not formatted.
+func format(args []string) error {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	list := flags.Bool("l", false, "list the files not formatted and fail if any")
+	write := flags.Bool("w", false, "rewrite the files")
+	ext := flags.String("ext", ".html", "views extension")
+	left := flags.String("left", "{{", "left action delimiter")
+	right := flags.String("right", "}}", "right action delimiter")
+	width := flags.Int("width", 0, "wrap the attributes of longer start tags, 0 means 100")
+	flags.Parse(args)
+
+	opts := html.FormatOptions{Delims: [2]string{*left, *right}, Width: *width}
+	unformatted := 0
+	for _, root := range flags.Args() {
+		err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(file) != *ext && file != root {
+				return err
+			}
+			src, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			formatted, err := html.Format(src, opts)
+			if err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			switch {
+			case *list:
+				if !bytes.Equal(src, formatted) {
+					fmt.Fprintln(stdout, file)
+					unformatted++
+				}
+			case *write:
+				if !bytes.Equal(src, formatted) {
+					return os.WriteFile(file, formatted, 0o644)
+				}
+			default:
+				stdout.Write(formatted)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if unformatted > 0 {
+		return fmt.Errorf("%d files not formatted", unformatted)
+	}
+	return nil
+}
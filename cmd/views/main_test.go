@@ -0,0 +1,362 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/znbang/gofiber-layout/html"
+)
+
+// remote is the bundle store push and pull talk to in the tests
+type remote struct {
+	mutex       sync.Mutex
+	bundle      []byte
+	contentType string
+}
+
+func (r *remote) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if req.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch req.Method {
+	case http.MethodPut:
+		r.bundle, _ = io.ReadAll(req.Body)
+		r.contentType = req.Header.Get("Content-Type")
+	case http.MethodGet:
+		w.Write(r.bundle)
+	}
+}
+
+// writeFiles writes the files by path under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("mkdir: %v\n", err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+}
+
+// signedBundle returns a gzipped tar bundle of files signed with key.
+func signedBundle(t *testing.T, files map[string]string, key string) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	var buf bytes.Buffer
+	if _, err := html.WriteBundle(&buf, os.DirFS(dir), "", func(digest []byte) ([]byte, error) {
+		return html.SignHMAC([]byte(key), digest), nil
+	}); err != nil {
+		t.Fatalf("bundle: %v\n", err)
+	}
+	return buf.Bytes()
+}
+
+// zipBundle returns an unsigned zip bundle of files.
+func zipBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip: %v\n", err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// exists reports whether the file exists.
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
+
+func Test_Commands(t *testing.T) {
+	store := &remote{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	views := map[string]string{"index.html": `<h1>{{.Title}}</h1>`}
+
+	tests := []struct {
+		name string
+		cmd  func(args []string) error
+		// setup prepares dir and returns the args of cmd
+		setup func(t *testing.T, dir string) []string
+		// output printed and error returned, empty for none
+		output string
+		err    string
+		check  func(t *testing.T, dir string)
+	}{
+		{
+			name: "bundle",
+			cmd:  bundle,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views/index.html": views["index.html"]})
+				return []string{"-dir", filepath.Join(dir, "views"), "-out", filepath.Join(dir, "views.tar.gz"), "-hmac-key", "secret"}
+			},
+			output: "1 files, version",
+			check: func(t *testing.T, dir string) {
+				file, err := os.Open(filepath.Join(dir, "views.tar.gz"))
+				if err != nil {
+					t.Fatalf("open: %v\n", err)
+				}
+				defer file.Close()
+				bundle, err := html.ReadBundle(file)
+				if err != nil {
+					t.Fatalf("read: %v\n", err)
+				}
+				engine := html.NewWithOptions(html.WithFS(bundle)).VerifySignature(html.HMACVerifier([]byte("secret")), "")
+				if err := engine.Load(); err != nil {
+					t.Fatalf("load: %v\n", err)
+				}
+			},
+		},
+		{
+			name: "bundle ed25519 signature",
+			cmd:  bundle,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views/index.html": views["index.html"]})
+				der, err := x509.MarshalPKCS8PrivateKey(priv)
+				if err != nil {
+					t.Fatalf("key: %v\n", err)
+				}
+				writeFiles(t, dir, map[string]string{"key.pem": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))})
+				return []string{"-dir", filepath.Join(dir, "views"), "-out", filepath.Join(dir, "views.tar.gz"), "-ed25519-key", filepath.Join(dir, "key.pem"), "-signature", "release.sig"}
+			},
+			output: "1 files, version",
+			check: func(t *testing.T, dir string) {
+				file, err := os.Open(filepath.Join(dir, "views.tar.gz"))
+				if err != nil {
+					t.Fatalf("open: %v\n", err)
+				}
+				defer file.Close()
+				bundle, err := html.ReadBundle(file)
+				if err != nil {
+					t.Fatalf("read: %v\n", err)
+				}
+				engine := html.NewWithOptions(html.WithFS(bundle)).VerifySignature(html.Ed25519Verifier(pub), "release.sig")
+				if err := engine.Load(); err != nil {
+					t.Fatalf("load: %v\n", err)
+				}
+			},
+		},
+		{
+			name: "bundle exclusive keys",
+			cmd:  bundle,
+			setup: func(t *testing.T, dir string) []string {
+				return []string{"-dir", dir, "-hmac-key", "secret", "-ed25519-key", "key.pem"}
+			},
+			err: "exclusive",
+		},
+		{
+			name: "push gzipped tar",
+			cmd:  push,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views.tar.gz": string(signedBundle(t, views, "secret"))})
+				return []string{"-url", server.URL, "-token", "token", filepath.Join(dir, "views.tar.gz")}
+			},
+			output: "pushed",
+			check: func(t *testing.T, dir string) {
+				if store.contentType != "application/gzip" {
+					t.Fatalf("Expected:\n%s\nResult:\n%s\n", "application/gzip", store.contentType)
+				}
+			},
+		},
+		{
+			name: "push zip",
+			cmd:  push,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views.zip": string(zipBundle(t, views))})
+				return []string{"-url", server.URL, "-token", "token", filepath.Join(dir, "views.zip")}
+			},
+			output: "pushed",
+			check: func(t *testing.T, dir string) {
+				if store.contentType != "application/zip" {
+					t.Fatalf("Expected:\n%s\nResult:\n%s\n", "application/zip", store.contentType)
+				}
+			},
+		},
+		{
+			name: "push broken bundle",
+			cmd:  push,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views.tar.gz": "\x1f\x8bbroken"})
+				return []string{"-url", server.URL, "-token", "token", filepath.Join(dir, "views.tar.gz")}
+			},
+			err: "bundle",
+		},
+		{
+			name: "push unauthorized",
+			cmd:  push,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views.tar.gz": string(signedBundle(t, views, "secret"))})
+				return []string{"-url", server.URL, filepath.Join(dir, "views.tar.gz")}
+			},
+			err: "401",
+		},
+		{
+			name: "pull",
+			cmd:  pull,
+			setup: func(t *testing.T, dir string) []string {
+				store.bundle = signedBundle(t, views, "secret")
+				writeFiles(t, dir, map[string]string{"index.html": "old", "stale.html": "stale", "old/page.html": "stale"})
+				return []string{"-url", server.URL, "-token", "token", "-hmac-key", "secret", "-dir", dir}
+			},
+			output: "removed 2",
+			check: func(t *testing.T, dir string) {
+				buf, err := os.ReadFile(filepath.Join(dir, "index.html"))
+				if err != nil || string(buf) != views["index.html"] {
+					t.Fatalf("Expected:\n%s\nResult:\n%s\n", views["index.html"], buf)
+				}
+				if exists(filepath.Join(dir, "stale.html")) || exists(filepath.Join(dir, "old")) {
+					t.Fatalf("Expected the stale files to be removed\n")
+				}
+				if !exists(filepath.Join(dir, html.SignatureFile)) {
+					t.Fatalf("Expected the signature to be unpacked\n")
+				}
+			},
+		},
+		{
+			name: "pull bad signature",
+			cmd:  pull,
+			setup: func(t *testing.T, dir string) []string {
+				store.bundle = signedBundle(t, views, "other")
+				writeFiles(t, dir, map[string]string{"index.html": "old"})
+				return []string{"-url", server.URL, "-token", "token", "-hmac-key", "secret", "-dir", dir}
+			},
+			err: "mismatch",
+			check: func(t *testing.T, dir string) {
+				if buf, _ := os.ReadFile(filepath.Join(dir, "index.html")); string(buf) != "old" {
+					t.Fatalf("Expected the folder to be left as it was\n")
+				}
+			},
+		},
+		{
+			name: "pull without key",
+			cmd:  pull,
+			setup: func(t *testing.T, dir string) []string {
+				return []string{"-url", server.URL, "-token", "token", "-dir", dir}
+			},
+			err: "-unsigned",
+		},
+		{
+			name: "pull unsigned",
+			cmd:  pull,
+			setup: func(t *testing.T, dir string) []string {
+				store.bundle = zipBundle(t, views)
+				return []string{"-url", server.URL, "-token", "token", "-unsigned", "-dir", dir}
+			},
+			output: "pulled 1 files",
+		},
+		{
+			name: "generate",
+			cmd:  generate,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"views/index.html": views["index.html"]})
+				wd, err := os.Getwd()
+				if err != nil {
+					t.Fatalf("getwd: %v\n", err)
+				}
+				t.Cleanup(func() { os.Chdir(wd) })
+				if err := os.Chdir(dir); err != nil {
+					t.Fatalf("chdir: %v\n", err)
+				}
+				return []string{"-pkg", "app", "-dir", "views"}
+			},
+			check: func(t *testing.T, dir string) {
+				buf, err := os.ReadFile(filepath.Join(dir, "views_gen.go"))
+				if err != nil {
+					t.Fatalf("read: %v\n", err)
+				}
+				if !strings.Contains(string(buf), "package app") || !strings.Contains(string(buf), "go:embed") {
+					t.Fatalf("Unexpected generated file:\n%s\n", buf)
+				}
+			},
+		},
+		{
+			name: "check",
+			cmd:  check,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"index.html": `{{template "missing" .}}`})
+				return []string{"-dir", dir}
+			},
+			output: "template missing does not exist",
+			err:    "1 missing templates",
+		},
+		{
+			name: "migrate",
+			cmd:  migrate,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"layouts/main.html": `<main>{{embed}}</main>`})
+				return []string{"-dir", dir}
+			},
+			output: "layout layouts/main includes the page with {{embed}}",
+		},
+		{
+			name: "fmt",
+			cmd:  format,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"index.html": "<div>\n<p>{{ .Title }}</p>\n</div>\n", "notes.txt": "<div>"})
+				return []string{"-l", dir}
+			},
+			output: "index.html",
+			err:    "1 files not formatted",
+		},
+		{
+			name: "fmt write",
+			cmd:  format,
+			setup: func(t *testing.T, dir string) []string {
+				writeFiles(t, dir, map[string]string{"index.html": "<div>\n<p>{{ .Title }}</p>\n</div>\n"})
+				return []string{"-w", dir}
+			},
+			check: func(t *testing.T, dir string) {
+				src, _ := os.ReadFile(filepath.Join(dir, "index.html"))
+				formatted, err := html.Format(src, html.FormatOptions{})
+				if err != nil || !bytes.Equal(src, formatted) {
+					t.Fatalf("Expected the file to be formatted:\n%s\n", src)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			args := test.setup(t, dir)
+			var out bytes.Buffer
+			stdout = &out
+			defer func() { stdout = os.Stdout }()
+			err := test.cmd(args)
+			if test.err == "" && err != nil || test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("Expected:\n%s\nResult:\n%v\n", test.err, err)
+			}
+			if !strings.Contains(out.String(), test.output) {
+				t.Fatalf("Expected:\n%s\nResult:\n%s\n", test.output, out.String())
+			}
+			if test.check != nil {
+				test.check(t, dir)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// pathKey is the context key of the request path
+type pathKey struct{}
+
+// WithPath returns a copy of ctx carrying the request path compared by the
+// active funcs, Respond sets it from the Fiber context.
+func WithPath(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, pathKey{}, path)
+}
+
+// Path returns the request path of ctx, empty if none was set.
+func Path(ctx context.Context) string {
+	path, _ := ctx.Value(pathKey{}).(string)
+	return path
+}
+
+// ActiveFuncs registers {{active "/admin" "is-active"}}, which returns the
+// class when the request path is /admin or below it, and
+// {{activeRoute "users.show" "is-active"}}, which returns the class when
+// the request path matches the pattern of the named route, such as
+// /users/:id. FiberRoutes returns the named routes of an app.
+func (e *Engine) ActiveFuncs(routes map[string]string) *Engine {
+	e.AddContextFunc("active", func(ctx context.Context, prefix, class string) string {
+		if ActivePath(Path(ctx), prefix) {
+			return class
+		}
+		return ""
+	})
+	return e.AddContextFunc("activeRoute", func(ctx context.Context, name, class string) (string, error) {
+		pattern, ok := routes[name]
+		if !ok {
+			return "", fmt.Errorf("activeRoute: route %s does not exist", name)
+		}
+		if MatchRoute(pattern, Path(ctx)) {
+			return class, nil
+		}
+		return "", nil
+	})
+}
+
+// ActivePath reports whether path is prefix or below it, "/" only matches
+// itself.
+func ActivePath(path, prefix string) bool {
+	path, prefix = trimSlash(path), trimSlash(prefix)
+	if path == prefix {
+		return true
+	}
+	return prefix != "/" && strings.HasPrefix(path, prefix+"/")
+}
+
+// MatchRoute reports whether path matches the route pattern, whose :param
+// segments match any segment and whose * matches the rest of the path.
+func MatchRoute(pattern, path string) bool {
+	patterns := strings.Split(trimSlash(pattern), "/")
+	segments := strings.Split(trimSlash(path), "/")
+	for i, p := range patterns {
+		if p == "*" || strings.HasPrefix(p, "+") {
+			return true
+		}
+		optional := strings.HasPrefix(p, ":") && strings.HasSuffix(p, "?")
+		if i >= len(segments) {
+			return optional && i == len(patterns)-1
+		}
+		if !strings.HasPrefix(p, ":") && p != segments[i] {
+			return false
+		}
+	}
+	return len(segments) == len(patterns)
+}
+
+// trimSlash returns path without its trailing slash.
+func trimSlash(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,18 @@
+//go:build !noos
+
+package html
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// FiberRoutes returns the paths of the named routes of app by name.
+func FiberRoutes(app *fiber.App) map[string]string {
+	routes := make(map[string]string)
+	for _, route := range app.GetRoutes() {
+		if route.Name != "" {
+			routes[route.Name] = route.Path
+		}
+	}
+	return routes
+}
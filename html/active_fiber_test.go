@@ -0,0 +1,37 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Active(t *testing.T) {
+	engine := New("./testdata/active", ".html")
+	app := fiber.New()
+	handler := func(c *fiber.Ctx) error {
+		return engine.Respond(c, "nav", nil)
+	}
+	app.Get("/admin/users", handler)
+	app.Get("/users/:id", handler).Name("users.show")
+	engine.ActiveFuncs(FiberRoutes(app))
+
+	tests := map[string]string{
+		"/admin/users": `<a class="is-active" href="/admin">Admin</a><a class="" href="/">Home</a><a class="" href="/users">User</a>`,
+		"/users/42":    `<a class="" href="/admin">Admin</a><a class="" href="/">Home</a><a class="is-active" href="/users">User</a>`,
+	}
+	for path, expect := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if result := trim(string(body)); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package html
+
+import "testing"
+
+func Test_MatchRoute(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"/users/:id", "/users/42", true},
+		{"/users/:id", "/users/42/edit", false},
+		{"/users/:id?", "/users", true},
+		{"/files/*", "/files/a/b", true},
+		{"/admin", "/admin/", true},
+		{"/admin", "/administrator", false},
+	}
+	for _, test := range tests {
+		if MatchRoute(test.pattern, test.path) != test.match {
+			t.Fatalf("Expected MatchRoute(%q, %q) to be %v\n", test.pattern, test.path, test.match)
+		}
+	}
+	if ActivePath("/administrator", "/admin") || !ActivePath("/admin/users", "/admin/") {
+		t.Fatalf("Unexpected ActivePath result\n")
+	}
+}
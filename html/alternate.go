@@ -0,0 +1,31 @@
+package html
+
+import (
+	"context"
+	"html/template"
+)
+
+type alternateKey struct{}
+
+// WithAlternate returns a copy of ctx selecting the alternate version of
+// the pages rendered with it, such as amp: index renders index.amp.html if
+// it exists and index.html otherwise, with the same binding and funcs.
+// Locale variants of the alternate version are named index.amp.fr.html.
+func WithAlternate(ctx context.Context, alternate string) context.Context {
+	return context.WithValue(ctx, alternateKey{}, alternate)
+}
+
+// Alternate returns the alternate version selected by ctx.
+func Alternate(ctx context.Context) string {
+	alternate, _ := ctx.Value(alternateKey{}).(string)
+	return alternate
+}
+
+// alternate returns the name of the alternate version of the template name
+// selected by ctx if it exists, or name itself.
+func alternate(ctx context.Context, templates map[string]*template.Template, name string) string {
+	if alt := Alternate(ctx); alt != "" && templates[name+"."+alt] != nil {
+		return name + "." + alt
+	}
+	return name
+}
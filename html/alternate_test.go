@@ -0,0 +1,32 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_Alternate(t *testing.T) {
+	engine := New("./testdata/amp", ".html")
+	binding := map[string]interface{}{"Title": "News"}
+	cases := []struct {
+		ctx    context.Context
+		name   string
+		expect string
+	}{
+		{context.Background(), "index", `<p>News</p>`},
+		{WithAlternate(context.Background(), "amp"), "index", `<amp-img>News</amp-img>`},
+		{WithLocale(WithAlternate(context.Background(), "amp"), "fr"), "index", `<amp-img lang="fr">News</amp-img>`},
+		// Falls back to the canonical page
+		{WithAlternate(context.Background(), "amp"), "about", `<p>about</p>`},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if err := engine.RenderContext(c.ctx, &buf, c.name, binding); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		if result := trim(buf.String()); result != c.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", c.expect, result)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package html
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// AnalyzeConfig describes the views checked by Analyze
+type AnalyzeConfig struct {
+	// views extension including the dot, defaults to .html
+	Extension string
+	// layout name without extension, optional
+	Layout string
+	// left and right action delimiters, defaults to {{ and }}
+	Delims [2]string
+	// folder of the partials parsed into every view, optional
+	Partials string
+}
+
+// Issue is a problem found by Analyze
+type Issue struct {
+	// view the issue was found in
+	Template string
+	// name of the included or defined template
+	Name string
+	// file:line:col of the include or definition
+	Location string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Location, i.Name)
+}
+
+// Analysis lists the broken and dead includes of the views
+type Analysis struct {
+	// includes of templates defined neither by the view nor the layout
+	Missing []Issue
+	// templates defined but never included
+	Unreferenced []Issue
+}
+
+// Analyze parses the views of fsys without executing them and reports the
+// includes of templates that do not exist, which fail at render time, and
+// the defined templates no view includes. Funcs are not checked, so the
+// views can be analyzed without the app, e.g. in CI.
+func Analyze(fsys fs.FS, cfg AnalyzeConfig) (*Analysis, error) {
+	if cfg.Extension == "" {
+		cfg.Extension = ".html"
+	}
+	if cfg.Delims[0] == "" {
+		cfg.Delims = [2]string{"{{", "}}"}
+	}
+	layout := map[string]*parse.Tree{}
+	if cfg.Layout != "" {
+		buf, err := fs.ReadFile(fsys, cfg.Layout+cfg.Extension)
+		if err != nil {
+			return nil, err
+		}
+		if layout, err = analyzeParse(cfg.Layout, string(buf), cfg.Delims); err != nil {
+			return nil, err
+		}
+	}
+	partials, err := analyzePartials(fsys, cfg)
+	if err != nil {
+		return nil, err
+	}
+	analysis := &Analysis{}
+	// layout definitions included by at least one view
+	layoutUsed := make(map[string]bool)
+	err = fs.WalkDir(fsys, ".", func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(file) != cfg.Extension {
+			return err
+		}
+		name := strings.TrimSuffix(file, cfg.Extension)
+		if cfg.Layout != "" && name == cfg.Layout {
+			return nil
+		}
+		buf, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		page, err := analyzeParse(name, string(buf), cfg.Delims)
+		if err != nil {
+			return err
+		}
+		// The view overrides the blocks of the layout
+		set := make(map[string]*parse.Tree, len(layout)+len(partials)+len(page))
+		for k, v := range layout {
+			set[k] = v
+		}
+		for k, v := range partials {
+			set[k] = v
+		}
+		for k, v := range page {
+			set[k] = v
+		}
+		used := make(map[string]bool)
+		for _, tree := range set {
+			includes(tree, tree.Root, func(n *parse.TemplateNode) {
+				used[n.Name] = true
+				if set[n.Name] == nil {
+					analysis.Missing = append(analysis.Missing, Issue{Template: name, Name: n.Name, Location: location(tree, n)})
+				}
+			})
+		}
+		for defined, tree := range page {
+			if defined != name && !used[defined] {
+				analysis.Unreferenced = append(analysis.Unreferenced, Issue{Template: name, Name: defined, Location: location(tree, tree.Root)})
+			}
+		}
+		for defined := range layout {
+			if used[defined] {
+				layoutUsed[defined] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for defined, tree := range layout {
+		if defined != cfg.Layout && !layoutUsed[defined] {
+			analysis.Unreferenced = append(analysis.Unreferenced, Issue{Template: cfg.Layout, Name: defined, Location: location(tree, tree.Root)})
+		}
+	}
+	sortIssues(analysis.Missing)
+	sortIssues(analysis.Unreferenced)
+	return analysis, nil
+}
+
+// Analyze reports the broken and dead includes of the views of the engine.
+func (e *Engine) Analyze() (*Analysis, error) {
+	src, err := e.source()
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(src, AnalyzeConfig{
+		Extension: e.extension,
+		Layout:    e.layout,
+		Delims:    [2]string{e.left, e.right},
+		Partials:  e.partials,
+	})
+}
+
+// analyzePartials parses the shared partials of cfg by template name.
+func analyzePartials(fsys fs.FS, cfg AnalyzeConfig) (map[string]*parse.Tree, error) {
+	partials := make(map[string]*parse.Tree)
+	if cfg.Partials == "" {
+		return partials, nil
+	}
+	dir := strings.Trim(cfg.Partials, "/")
+	err := fs.WalkDir(fsys, dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(file) != cfg.Extension {
+			return err
+		}
+		buf, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(file, dir+"/"), cfg.Extension)
+		trees, err := analyzeParse(name, string(buf), cfg.Delims)
+		if err != nil {
+			return err
+		}
+		for k, v := range trees {
+			partials[k] = v
+		}
+		return nil
+	})
+	return partials, err
+}
+
+// analyzeParse parses text without checking the funcs it calls.
+func analyzeParse(name, text string, delims [2]string) (map[string]*parse.Tree, error) {
+	trees := make(map[string]*parse.Tree)
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(text, delims[0], delims[1], trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+// includes calls fn with the template actions of list and the lists
+// nested in it.
+func includes(tree *parse.Tree, list *parse.ListNode, fn func(n *parse.TemplateNode)) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TemplateNode:
+			fn(n)
+		case *parse.ListNode:
+			includes(tree, n, fn)
+		case *parse.IfNode:
+			includes(tree, n.List, fn)
+			includes(tree, n.ElseList, fn)
+		case *parse.RangeNode:
+			includes(tree, n.List, fn)
+			includes(tree, n.ElseList, fn)
+		case *parse.WithNode:
+			includes(tree, n.List, fn)
+			includes(tree, n.ElseList, fn)
+		}
+	}
+}
+
+func sortIssues(issues []Issue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Template != issues[j].Template {
+			return issues[i].Template < issues[j].Template
+		}
+		return issues[i].Location < issues[j].Location
+	})
+}
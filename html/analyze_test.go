@@ -0,0 +1,20 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_Analyze(t *testing.T) {
+	engine := New("./testdata/analyze", ".html")
+	engine.Layout("layouts/main")
+	analysis, err := engine.Analyze()
+	if err != nil {
+		t.Fatalf("analyze: %v\n", err)
+	}
+	expect := `missing [page:1:66: missing] unreferenced [layouts/main:1:60: footer page:3:17: dead]`
+	result := fmt.Sprintf("missing %v unreferenced %v", analysis.Missing, analysis.Unreferenced)
+	if result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
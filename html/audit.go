@@ -0,0 +1,92 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strconv"
+	"sync"
+	"text/template/parse"
+)
+
+// auditFunc is the func called at the start of each instrumented template
+const auditFunc = "_htmlAudit"
+
+// Audit if set to true records the templates executed by each render in
+// the render context, see WithAudit. The templates are instrumented with
+// an extra context func, so it is meant for development.
+func (e *Engine) Audit(enabled bool) *Engine {
+	e.audit = enabled
+	return e
+}
+
+type auditKey struct{}
+
+// auditTrail collects the templates executed by a render
+type auditTrail struct {
+	mutex sync.Mutex
+	names []string
+	seen  map[string]bool
+}
+
+// auditRecord records file in the audit trail of ctx.
+func auditRecord(ctx context.Context, file string) bool {
+	if trail, ok := ctx.Value(auditKey{}).(*auditTrail); ok {
+		trail.record(file)
+	}
+	return false
+}
+
+func (a *auditTrail) record(name string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.seen[name] {
+		a.seen[name] = true
+		a.names = append(a.names, name)
+	}
+}
+
+// WithAudit returns a copy of ctx recording the templates executed by the
+// renders using it, which requires the engine to be in audit mode.
+func WithAudit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, auditKey{}, &auditTrail{seen: make(map[string]bool)})
+}
+
+// AuditTrail returns the names of the templates and layouts executed by the
+// renders using ctx, in order of first execution.
+func AuditTrail(ctx context.Context) []string {
+	trail, ok := ctx.Value(auditKey{}).(*auditTrail)
+	if !ok {
+		return nil
+	}
+	trail.mutex.Lock()
+	defer trail.mutex.Unlock()
+	return append([]string(nil), trail.names...)
+}
+
+// trees returns the parse trees of the templates associated with tmpl.
+func trees(tmpl *template.Template) map[string]*parse.Tree {
+	result := make(map[string]*parse.Tree)
+	for _, t := range tmpl.Templates() {
+		result[t.Name()] = t.Tree
+	}
+	return result
+}
+
+// instrument prepends an audit call recording file to the templates of
+// tmpl whose tree is not in before, i.e. the ones parsed from file.
+func instrument(tmpl *template.Template, before map[string]*parse.Tree, file string) error {
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil || before[t.Name()] == t.Tree {
+			continue
+		}
+		// {{if audit "file"}}{{end}} leaves no output in any context
+		src := fmt.Sprintf("{{if %s %s}}{{end}}", auditFunc, strconv.Quote(file))
+		parsed, err := parse.Parse("audit", src, "{{", "}}", map[string]interface{}{auditFunc: fmt.Sprint})
+		if err != nil {
+			return err
+		}
+		t.Tree.Root.Nodes = append([]parse.Node{parsed["audit"].Root.Nodes[0]}, t.Tree.Root.Nodes...)
+	}
+	return nil
+}
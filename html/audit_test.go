@@ -0,0 +1,36 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func Test_Audit(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.Audit(true)
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	for i := 0; i < 2; i++ {
+		ctx := WithAudit(context.Background())
+		var buf bytes.Buffer
+		if err := engine.RenderContext(ctx, &buf, "index", map[string]interface{}{
+			"Title": "Hello, World!",
+		}); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+		result := trim(buf.String())
+		if expect != result {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+		trail := AuditTrail(ctx)
+		if !reflect.DeepEqual(trail, []string{"layouts/main", "index"}) {
+			t.Fatalf("Expected:\n[layouts/main index]\nResult:\n%v\n", trail)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package html
+
+import (
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
+	"text/template/parse"
+)
+
+// FieldIssue is a field reference CheckBindings cannot resolve
+type FieldIssue struct {
+	// view the reference was found in
+	Template string
+	// file:line:col of the reference
+	Location string
+	// reference as written, such as .User.Name
+	Field string
+	// type the missing field was looked up in
+	Type string
+	// the field exists but is unexported
+	Unexported bool
+}
+
+func (i FieldIssue) String() string {
+	if i.Unexported {
+		return fmt.Sprintf("%s: %s: field of %s is unexported", i.Location, i.Field, i.Type)
+	}
+	return fmt.Sprintf("%s: %s: no field or method in %s", i.Location, i.Field, i.Type)
+}
+
+// CheckBindings follows the field references of the views of fsys, from
+// the layout if any, through range, with and template actions, against the
+// types of the bindings given by view name, e.g. {"index": IndexPage{}},
+// and reports the fields that do not exist or are unexported, which render
+// as <no value> or fail at render time. References through interface and
+// variable values other than $ are not checked.
+func CheckBindings(fsys fs.FS, cfg AnalyzeConfig, bindings map[string]interface{}) ([]FieldIssue, error) {
+	if cfg.Extension == "" {
+		cfg.Extension = ".html"
+	}
+	if cfg.Delims[0] == "" {
+		cfg.Delims = [2]string{"{{", "}}"}
+	}
+	layout := map[string]*parse.Tree{}
+	if cfg.Layout != "" {
+		buf, err := fs.ReadFile(fsys, cfg.Layout+cfg.Extension)
+		if err != nil {
+			return nil, err
+		}
+		if layout, err = analyzeParse(cfg.Layout, string(buf), cfg.Delims); err != nil {
+			return nil, err
+		}
+	}
+	partials, err := analyzePartials(fsys, cfg)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var issues []FieldIssue
+	for _, name := range names {
+		buf, err := fs.ReadFile(fsys, name+cfg.Extension)
+		if err != nil {
+			return nil, err
+		}
+		page, err := analyzeParse(name, string(buf), cfg.Delims)
+		if err != nil {
+			return nil, err
+		}
+		set := make(map[string]*parse.Tree, len(layout)+len(partials)+len(page))
+		for k, v := range layout {
+			set[k] = v
+		}
+		for k, v := range partials {
+			set[k] = v
+		}
+		for k, v := range page {
+			set[k] = v
+		}
+		root := name
+		if cfg.Layout != "" {
+			root = cfg.Layout
+		}
+		t := reflect.TypeOf(bindings[name])
+		c := &bindingChecker{view: name, set: set, root: t, visited: make(map[string]bool)}
+		c.template(root, t)
+		issues = append(issues, c.issues...)
+	}
+	return issues, nil
+}
+
+// CheckBindings checks the views of the engine against the binding types
+// given by view name, see the CheckBindings func.
+func (e *Engine) CheckBindings(bindings map[string]interface{}) ([]FieldIssue, error) {
+	src, err := e.source()
+	if err != nil {
+		return nil, err
+	}
+	return CheckBindings(src, AnalyzeConfig{
+		Extension: e.extension,
+		Layout:    e.layout,
+		Delims:    [2]string{e.left, e.right},
+		Partials:  e.partials,
+	}, bindings)
+}
+
+// bindingChecker follows the references of a view, nil types are unknown
+type bindingChecker struct {
+	view string
+	set  map[string]*parse.Tree
+	// type of $
+	root reflect.Type
+	// templates checked by dot type
+	visited map[string]bool
+	issues  []FieldIssue
+	tree    *parse.Tree
+}
+
+// template checks the template name executed with dot.
+func (c *bindingChecker) template(name string, dot reflect.Type) {
+	tree := c.set[name]
+	key := fmt.Sprintf("%s %v", name, dot)
+	if tree == nil || c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+	parent := c.tree
+	c.tree = tree
+	c.list(tree.Root, dot)
+	c.tree = parent
+}
+
+func (c *bindingChecker) list(list *parse.ListNode, dot reflect.Type) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			c.pipe(n.Pipe, dot)
+		case *parse.IfNode:
+			c.pipe(n.Pipe, dot)
+			c.list(n.List, dot)
+			c.list(n.ElseList, dot)
+		case *parse.WithNode:
+			c.list(n.List, c.pipe(n.Pipe, dot))
+			c.list(n.ElseList, dot)
+		case *parse.RangeNode:
+			c.list(n.List, elemType(c.pipe(n.Pipe, dot)))
+			c.list(n.ElseList, dot)
+		case *parse.TemplateNode:
+			c.template(n.Name, c.pipe(n.Pipe, dot))
+		case *parse.ListNode:
+			c.list(n, dot)
+		}
+	}
+}
+
+// pipe checks the references of pipe and returns its type.
+func (c *bindingChecker) pipe(pipe *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if pipe == nil {
+		return dot
+	}
+	var result reflect.Type
+	for _, cmd := range pipe.Cmds {
+		result = nil
+		for _, arg := range cmd.Args {
+			t := c.arg(arg, dot)
+			if len(cmd.Args) == 1 {
+				result = t
+			}
+		}
+	}
+	return result
+}
+
+// arg checks the references of arg and returns its type.
+func (c *bindingChecker) arg(arg parse.Node, dot reflect.Type) reflect.Type {
+	switch n := arg.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return c.fields(n, n.String(), dot, n.Ident)
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			return c.fields(n, n.String(), c.root, n.Ident[1:])
+		}
+	case *parse.ChainNode:
+		if p, ok := n.Node.(*parse.PipeNode); ok {
+			return c.fields(n, n.String(), c.pipe(p, dot), n.Field)
+		}
+	case *parse.PipeNode:
+		return c.pipe(n, dot)
+	}
+	return nil
+}
+
+// fields resolves the field chain idents from t, reporting the first one
+// that does not resolve.
+func (c *bindingChecker) fields(node parse.Node, field string, t reflect.Type, idents []string) reflect.Type {
+	for _, ident := range idents {
+		if t == nil {
+			return nil
+		}
+		next, unexported, ok := fieldType(t, ident)
+		if !ok {
+			c.issues = append(c.issues, FieldIssue{
+				Template:   c.view,
+				Location:   location(c.tree, node),
+				Field:      field,
+				Type:       t.String(),
+				Unexported: unexported,
+			})
+			return nil
+		}
+		t = next
+	}
+	return t
+}
+
+// fieldType returns the type of the field or method name of t, nil if it
+// cannot be known.
+func fieldType(t reflect.Type, name string) (result reflect.Type, unexported, ok bool) {
+	if m, ok := t.MethodByName(name); ok {
+		return methodResult(m.Type), false, true
+	}
+	if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface {
+		if m, ok := reflect.PointerTo(t).MethodByName(name); ok {
+			return methodResult(m.Type), false, true
+		}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return nil, false, true
+	case reflect.Map:
+		if t.Key().Kind() == reflect.String {
+			return unknownInterface(t.Elem()), false, true
+		}
+	case reflect.Struct:
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return nil, false, false
+		}
+		if !f.IsExported() {
+			return nil, true, false
+		}
+		return unknownInterface(f.Type), false, true
+	}
+	return nil, false, false
+}
+
+// methodResult returns the first result of the method type m.
+func methodResult(m reflect.Type) reflect.Type {
+	if m.NumOut() == 0 {
+		return nil
+	}
+	return unknownInterface(m.Out(0))
+}
+
+// unknownInterface returns nil for interface types, whose values are
+// only known at render time.
+func unknownInterface(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	return t
+}
+
+// elemType returns the type of the elements ranged over in t.
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return unknownInterface(t.Elem())
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+)
+
+type bindingItem struct {
+	Name  string
+	price int
+}
+
+type bindingUser struct {
+	Email string
+}
+
+func (u *bindingUser) DisplayName() string {
+	return u.Email
+}
+
+type bindingPage struct {
+	Title   string
+	Items   []bindingItem
+	User    *bindingUser
+	Extra   interface{}
+	Heading string
+}
+
+func Test_CheckBindings(t *testing.T) {
+	engine := New("./testdata/bindings", ".html")
+	engine.Layout("layout")
+	issues, err := engine.CheckBindings(map[string]interface{}{
+		"index": bindingPage{},
+	})
+	if err != nil {
+		t.Fatalf("check bindings: %v\n", err)
+	}
+	expect := `[index:3:32: .price: field of html.bindingItem is unexported index:7:22: .Nick: no field or method in *html.bindingUser]`
+	result := fmt.Sprintf("%v", issues)
+	if result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,312 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Blade adds a pre-processor translating the Blade shorthand of Laravel
+// views into actions, for teams moving templates from PHP:
+//   - @include("partials.nav") is {{template "partials/nav" .}}, or
+//     {{template "nav" .}} when partials is the Partials folder, a second
+//     argument is the pipeline passed instead of the dot:
+//     @include("partials.user", .User)
+//   - @yield("title") is {{block "title" .}}{{end}}, with a default content
+//     as second argument: @yield("title", "Home")
+//   - @section("content") ... @endsection (or @stop) is
+//     {{define "content"}} ... {{end}}, @section("title", "Home") defines
+//     it inline, and @section("sidebar") ... @show in a layout is a block
+//   - @extends("layouts.main") is removed, it must name the layout of the
+//     engine or one of its Layouts, selected as usual
+//
+// Dots of the names are folder separators and @@ escapes an @. The actions
+// of the template are left as is and the delimiters in quoted contents are
+// printed as text.
+func (e *Engine) Blade() *Engine {
+	return e.PreProcess(e.blade)
+}
+
+// blade translates the Blade directives of the template name.
+func (e *Engine) blade(name string, src []byte) ([]byte, error) {
+	if !bytes.Contains(src, []byte("@")) {
+		return src, nil
+	}
+	t := bladeTranspiler{
+		src:      src,
+		left:     e.left,
+		right:    e.right,
+		layouts:  append([]string{e.layout}, e.layouts...),
+		partials: e.partials,
+	}
+	// Empty delimiters are the default ones, as for html/template
+	if t.left == "" {
+		t.left = "{{"
+	}
+	if t.right == "" {
+		t.right = "}}"
+	}
+	out, err := t.run()
+	if err != nil {
+		return nil, fmt.Errorf("blade: %s:%w", name, err)
+	}
+	return out, nil
+}
+
+// bladeTranspiler translates a template source
+type bladeTranspiler struct {
+	src         []byte
+	left, right string
+	layouts     []string
+	// folder of the shared partials, named relative to it
+	partials string
+	out      bytes.Buffer
+	// open @section directives
+	sections []bladeSection
+}
+
+// bladeSection is an open @section
+type bladeSection struct {
+	name string
+	line int
+	// offset of its content in the output
+	offset int
+}
+
+// run returns the translated source.
+func (t *bladeTranspiler) run() ([]byte, error) {
+	src := t.src
+	for i := 0; i < len(src); i++ {
+		if bytes.HasPrefix(src[i:], []byte(t.left)) {
+			end := t.actionEnd(i)
+			t.out.Write(src[i:end])
+			i = end - 1
+			continue
+		}
+		c := src[i]
+		if c != '@' || i > 0 && isWordByte(src[i-1]) {
+			t.out.WriteByte(c)
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == '@' {
+			t.out.WriteByte('@')
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(src) && isWordByte(src[j]) {
+			j++
+		}
+		directive := string(src[i+1 : j])
+		if !bladeDirectives[directive] {
+			t.out.Write(src[i:j])
+			i = j - 1
+			continue
+		}
+		line := bytes.Count(src[:i], []byte("\n")) + 1
+		var args []string
+		end := j
+		if directive != "endsection" && directive != "stop" && directive != "show" {
+			var err error
+			if args, end, err = bladeArgs(src, j); err != nil {
+				return nil, fmt.Errorf("%d: @%s: %v", line, directive, err)
+			}
+		}
+		if err := t.directive(directive, args, line); err != nil {
+			return nil, fmt.Errorf("%d: @%s: %v", line, directive, err)
+		}
+		i = end - 1
+	}
+	if len(t.sections) > 0 {
+		return nil, fmt.Errorf("%d: @section without @endsection", t.sections[len(t.sections)-1].line)
+	}
+	return t.out.Bytes(), nil
+}
+
+// actionEnd returns the offset past the action starting at src[i], its
+// comments and quoted strings may hold the right delimiter. It is the end
+// of src if the action is not closed, left to the template parser.
+func (t *bladeTranspiler) actionEnd(i int) int {
+	src := t.src
+	j := i + len(t.left)
+	if rest := bytes.TrimLeft(bytes.TrimPrefix(src[j:], []byte("-")), " \t\r\n"); bytes.HasPrefix(rest, []byte("/*")) {
+		if k := bytes.Index(rest, []byte("*/")); k >= 0 {
+			j = len(src) - len(rest) + k + 2
+		}
+	}
+	for ; j < len(src); j++ {
+		if bytes.HasPrefix(src[j:], []byte(t.right)) {
+			return j + len(t.right)
+		}
+		switch c := src[j]; c {
+		case '"', '\'', '`':
+			k := j + 1
+			for ; k < len(src) && src[k] != c; k++ {
+				if src[k] == '\\' && c != '`' {
+					k++
+				}
+			}
+			j = k
+		}
+	}
+	return len(src)
+}
+
+// bladeDirectives are the directives translated by Blade
+var bladeDirectives = map[string]bool{
+	"include": true, "extends": true, "yield": true,
+	"section": true, "endsection": true, "stop": true, "show": true,
+}
+
+// directive writes the translation of the directive with args.
+func (t *bladeTranspiler) directive(directive string, args []string, line int) error {
+	name := func() (string, error) {
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing name")
+		}
+		s, ok := bladeUnquote(args[0])
+		if !ok {
+			return "", fmt.Errorf("name %s is not a quoted string", args[0])
+		}
+		return strings.ReplaceAll(s, ".", "/"), nil
+	}
+	switch directive {
+	case "include":
+		tmpl, err := name()
+		if err != nil {
+			return err
+		}
+		if t.partials != "" {
+			tmpl = strings.TrimPrefix(tmpl, t.partials+"/")
+		}
+		pipeline := "."
+		if len(args) > 1 {
+			pipeline = args[1]
+		}
+		t.action(fmt.Sprintf("template %q %s", tmpl, pipeline))
+	case "extends":
+		layout, err := name()
+		if err != nil {
+			return err
+		}
+		for _, l := range t.layouts {
+			if l == layout {
+				return nil
+			}
+		}
+		return fmt.Errorf("layout %s is not a layout of the engine", layout)
+	case "yield":
+		block, err := name()
+		if err != nil {
+			return err
+		}
+		t.action(fmt.Sprintf("block %q .", block))
+		if len(args) > 1 {
+			t.content(args[1])
+		}
+		t.action("end")
+	case "section":
+		section, err := name()
+		if err != nil {
+			return err
+		}
+		if len(args) > 1 {
+			t.action(fmt.Sprintf("define %q", section))
+			t.content(args[1])
+			t.action("end")
+			return nil
+		}
+		// @show makes it a block, known at the end of the section
+		t.sections = append(t.sections, bladeSection{name: section, line: line, offset: t.out.Len()})
+	case "endsection", "stop", "show":
+		if len(t.sections) == 0 {
+			return fmt.Errorf("no open @section")
+		}
+		section := t.sections[len(t.sections)-1]
+		t.sections = t.sections[:len(t.sections)-1]
+		body := append([]byte(nil), t.out.Bytes()[section.offset:]...)
+		t.out.Truncate(section.offset)
+		if directive == "show" {
+			t.action(fmt.Sprintf("block %q .", section.name))
+		} else {
+			t.action(fmt.Sprintf("define %q", section.name))
+		}
+		t.out.Write(body)
+		t.action("end")
+	}
+	return nil
+}
+
+// action writes the action of the template delimiters.
+func (t *bladeTranspiler) action(s string) {
+	t.out.WriteString(t.left + s + t.right)
+}
+
+// content writes the quoted string arg as text, or prints the pipeline.
+func (t *bladeTranspiler) content(arg string) {
+	if s, ok := bladeUnquote(arg); ok {
+		quote := func(delim string) string {
+			return t.left + strconv.Quote(delim) + t.right
+		}
+		strings.NewReplacer(t.left, quote(t.left), t.right, quote(t.right)).WriteString(&t.out, s)
+		return
+	}
+	t.action(arg)
+}
+
+// bladeArgs returns the comma separated arguments of the parenthesis
+// opening at src[i], and the offset past it.
+func bladeArgs(src []byte, i int) ([]string, int, error) {
+	if i >= len(src) || src[i] != '(' {
+		return nil, i, fmt.Errorf("missing arguments")
+	}
+	var args []string
+	depth, start := 0, i+1
+	for j := i; j < len(src); j++ {
+		switch c := src[j]; c {
+		case '"', '\'', '`':
+			k := j + 1
+			for ; k < len(src) && src[k] != c; k++ {
+				if src[k] == '\\' && c != '`' {
+					k++
+				}
+			}
+			if k >= len(src) {
+				return nil, j, fmt.Errorf("unterminated string")
+			}
+			j = k
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				if arg := strings.TrimSpace(string(src[start:j])); arg != "" || len(args) > 0 {
+					args = append(args, arg)
+				}
+				return args, j + 1, nil
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, strings.TrimSpace(string(src[start:j])))
+				start = j + 1
+			}
+		}
+	}
+	return nil, len(src), fmt.Errorf("missing )")
+}
+
+// bladeUnquote returns the content of the Go string literal s, or of s in
+// single quotes as written in Blade.
+func bladeUnquote(s string) (string, bool) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), true
+	}
+	unquoted, err := strconv.Unquote(s)
+	return unquoted, err == nil
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore.
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,69 @@
+package html
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Blade(t *testing.T) {
+	engine := New("./testdata/blade", ".html")
+	engine.Layout("layouts/main").Partials("partials").Blade()
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", map[string]string{"Name": "Ann"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<html><title>Home</title><nav>menu</nav><aside>Links</aside><main><p>Mail ann@example.com about @include</p><b>Ann</b></main></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	for src, expect := range map[string]string{
+		`{{"@include(\"x\")"}}`:          `{{"@include(\"x\")"}}`,
+		"{{/* @yield(\"x\") }} */}}@@x":  "{{/* @yield(\"x\") }} */}}@x",
+		`@yield("title", "{{.Secret}}")`: `{{block "title" .}}{{"{{"}}.Secret{{"}}"}}{{end}}`,
+	} {
+		result, err := engine.blade("page", []byte(src))
+		if err != nil {
+			t.Fatalf("blade: %v\n", err)
+		}
+		if string(result) != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+
+	for src, expectErr := range map[string]string{
+		"<p>\n@section(\"content\")<p>open\n": "blade: page:2: @section without @endsection",
+		"@extends('layouts.admin')":           "blade: page:1: @extends: layout layouts/admin is not a layout of the engine",
+		"@include(partials.nav)":              "blade: page:1: @include: name partials.nav is not a quoted string",
+		"@include(\"partials.nav\", (.User)":  "blade: page:1: @include: missing )",
+	} {
+		_, err := engine.blade("page", []byte(src))
+		if err == nil || !strings.HasPrefix(err.Error(), expectErr) {
+			t.Fatalf("Expected:\n%s\nResult:\n%v\n", expectErr, err)
+		}
+	}
+}
+
+func Test_BladeDefaultDelims(t *testing.T) {
+	engine := New("./testdata/blade", ".html")
+	engine.Layout("layouts/main").Partials("partials").Delims("", "").Blade()
+	done := make(chan error, 1)
+	var buf bytes.Buffer
+	go func() {
+		done <- engine.Render(&buf, "index", map[string]string{"Name": "Ann"})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Blade did not finish with empty delimiters\n")
+	}
+	expect := `<html><title>Home</title><nav>menu</nav><aside>Links</aside><main><p>Mail ann@example.com about @include</p><b>Ann</b></main></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,95 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+)
+
+// BreadcrumbsTemplate is the partial rendering the breadcrumbs func, the
+// built-in markup is used when the views have none of that name
+const BreadcrumbsTemplate = "partials/breadcrumbs"
+
+// Breadcrumb is an entry of a breadcrumb trail
+type Breadcrumb struct {
+	Label string
+	URL   string
+	// 1-based position in the trail, set by the breadcrumbs func
+	Position int
+	// set on the last entry, the current page
+	Current bool
+}
+
+// breadcrumbsTemplate is the built-in breadcrumbs partial
+var breadcrumbsTemplate = template.Must(template.New(BreadcrumbsTemplate).Parse(
+	`<nav aria-label="Breadcrumb"><ol itemscope itemtype="https://schema.org/BreadcrumbList">` +
+		`{{range .}}<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem">` +
+		`{{if or .Current (not .URL)}}<span itemprop="name"{{if .Current}} aria-current="page"{{end}}>{{.Label}}</span>` +
+		`{{else}}<a itemprop="item" href="{{.URL}}"><span itemprop="name">{{.Label}}</span></a>{{end}}` +
+		`<meta itemprop="position" content="{{.Position}}"></li>{{end}}</ol></nav>`))
+
+// BreadcrumbsFunc registers {{breadcrumbs .Trail}}, which renders a trail
+// of []Breadcrumb, [][2]string or []string label and URL pairs as a
+// schema.org BreadcrumbList. The views may define BreadcrumbsTemplate to
+// change the markup, it receives the []Breadcrumb.
+func (e *Engine) BreadcrumbsFunc() *Engine {
+	return e.AddContextFunc("breadcrumbs", e.breadcrumbs)
+}
+
+// breadcrumbs renders trail with the breadcrumbs partial of the template
+// set and theme of the render.
+func (e *Engine) breadcrumbs(ctx context.Context, trail interface{}) (template.HTML, error) {
+	crumbs, err := Breadcrumbs(trail)
+	if err != nil {
+		return "", err
+	}
+	set, err := e.renderVersion(ctx).themed(e.theme(ctx))
+	if err != nil {
+		return "", err
+	}
+	tmpl := set.templates[BreadcrumbsTemplate]
+	if tmpl == nil {
+		tmpl = breadcrumbsTemplate
+	}
+	// The partial gets a frame of its own, its cache tags go to the page
+	if parent, ok := ctx.Value(fragmentKey{}).(*fragmentFrame); ok {
+		frame := &fragmentFrame{}
+		defer func() { parent.deps = append(parent.deps, frame.deps...) }()
+		ctx = context.WithValue(ctx, fragmentKey{}, frame)
+	}
+	var buf bytes.Buffer
+	// Parsed views are executed from pooled copies bound to ctx
+	if err = e.executeTemplate(ctx, tmpl, &buf, BreadcrumbsTemplate, crumbs, true); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// Breadcrumbs returns trail as breadcrumbs with their positions set.
+func Breadcrumbs(trail interface{}) ([]Breadcrumb, error) {
+	var crumbs []Breadcrumb
+	switch v := trail.(type) {
+	case []Breadcrumb:
+		crumbs = append(crumbs, v...)
+	case [][2]string:
+		for _, pair := range v {
+			crumbs = append(crumbs, Breadcrumb{Label: pair[0], URL: pair[1]})
+		}
+	case []string:
+		if len(v)%2 != 0 {
+			return nil, fmt.Errorf("breadcrumbs: odd number of labels and URLs")
+		}
+		for i := 0; i < len(v); i += 2 {
+			crumbs = append(crumbs, Breadcrumb{Label: v[i], URL: v[i+1]})
+		}
+	case nil:
+	default:
+		return nil, fmt.Errorf("breadcrumbs: %T is not a trail", trail)
+	}
+	for i := range crumbs {
+		crumbs[i].Position = i + 1
+		crumbs[i].Current = i == len(crumbs)-1
+	}
+	return crumbs, nil
+}
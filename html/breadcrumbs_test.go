@@ -0,0 +1,68 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_Breadcrumbs(t *testing.T) {
+	engine := New("./testdata/breadcrumbs", ".html")
+	engine.BreadcrumbsFunc()
+	// used by the custom views of the folder
+	engine.AddFunc("separator", func() string { return "/" })
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "page", map[string]interface{}{
+		"Trail": [][2]string{{"Home", "/"}, {"Fish & Chips", "/fish"}},
+	})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<nav aria-label="Breadcrumb"><ol itemscope itemtype="https://schema.org/BreadcrumbList"><li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/"><span itemprop="name">Home</span></a><meta itemprop="position" content="1"></li><li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><span itemprop="name" aria-current="page">Fish &amp; Chips</span><meta itemprop="position" content="2"></li></ol></nav>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	if _, err := Breadcrumbs([]string{"Home"}); err == nil {
+		t.Fatalf("Expected error for an odd trail\n")
+	}
+}
+
+func Test_Breadcrumbs_Partial(t *testing.T) {
+	engine := New("./testdata/breadcrumbs/custom", ".html")
+	engine.BreadcrumbsFunc()
+	engine.AddContextFunc("separator", func(ctx context.Context) string {
+		if Locale(ctx) == "fr" {
+			return "|"
+		}
+		return "/"
+	})
+	binding := map[string]interface{}{
+		"Trail": []string{"Home", "/", "Fish", "/fish"},
+	}
+	for _, tt := range []struct {
+		locale string
+		expect string
+	}{
+		{"", `<a href="/">Home</a>/ Fish`},
+		{"fr", `<a href="/">Home</a>| Fish`},
+	} {
+		var buf bytes.Buffer
+		if err := engine.RenderContext(WithLocale(context.Background(), tt.locale), &buf, "page", binding); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		if result := trim(buf.String()); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+	// The parsed partial is never executed, it still renders on its own
+	crumbs, _ := Breadcrumbs(binding["Trail"])
+	var buf bytes.Buffer
+	if err := engine.RenderPartial(&buf, BreadcrumbsTemplate, crumbs); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<a href="/">Home</a>/ Fish`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,290 @@
+package html
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestFile is the name of the manifest of a views bundle
+const ManifestFile = "manifest.json"
+
+// BundleManifest lists the files of a views bundle
+type BundleManifest struct {
+	// hex BundleDigest of the files, the manifest left out
+	Version string `json:"version"`
+	// time the bundle was made
+	Created time.Time `json:"created"`
+	// hex sha256 of each file by path
+	Files map[string]string `json:"files"`
+}
+
+// OpenBundle reads the views bundle at path, a .zip, .tar, .tar.gz or
+// .tgz file, into memory, to be loaded with WithFS or SetFS.
+func OpenBundle(path string) (fs.FS, error) {
+	file, err := openFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	defer file.Close()
+	return ReadBundle(file)
+}
+
+// MaxBundleSize caps the bytes ReadBundle reads and the total size of the
+// files once unpacked
+var MaxBundleSize int64 = 256 << 20
+
+// MaxBundleFileSize caps the size of each file of a bundle once unpacked
+var MaxBundleFileSize int64 = 16 << 20
+
+// ReadBundle reads a zip, tar or gzipped tar views bundle into memory,
+// the format is detected from the content. Bundles larger than
+// MaxBundleSize, or with a file larger than MaxBundleFileSize, fail.
+func ReadBundle(r io.Reader) (fs.FS, error) {
+	br := bufio.NewReader(&limitReader{r: r, n: MaxBundleSize + 1, err: fmt.Errorf("larger than %d bytes", MaxBundleSize)})
+	magic, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")):
+		buf, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		return readZip(zr)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		defer gz.Close()
+		return readTar(gz)
+	default:
+		return readTar(br)
+	}
+}
+
+// readZip copies the files of a zip archive, which may be compressed, to an
+// in-memory zip holding them unpacked.
+func readZip(zr *zip.Reader) (fs.FS, error) {
+	b := newBundleWriter()
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		err = b.add(file.Name, file.Modified, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.close()
+}
+
+// readTar copies the regular files of a tar archive to an in-memory zip,
+// which implements fs.FS.
+func readTar(r io.Reader) (fs.FS, error) {
+	b := newBundleWriter()
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err = b.add(hdr.Name, hdr.ModTime, tr); err != nil {
+			return nil, err
+		}
+	}
+	return b.close()
+}
+
+// bundleWriter unpacks the files of a bundle to an in-memory zip,
+// enforcing the bundle size limits
+type bundleWriter struct {
+	buf   bytes.Buffer
+	zw    *zip.Writer
+	total int64
+}
+
+// newBundleWriter returns an empty bundleWriter.
+func newBundleWriter() *bundleWriter {
+	b := &bundleWriter{}
+	b.zw = zip.NewWriter(&b.buf)
+	return b
+}
+
+// add copies the file name read from r.
+func (b *bundleWriter) add(name string, modified time.Time, r io.Reader) error {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	w, err := b.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("bundle: %v", err)
+	}
+	n, err := io.Copy(w, &limitReader{r: r, n: MaxBundleFileSize + 1, err: fmt.Errorf("%s: larger than %d bytes", name, MaxBundleFileSize)})
+	if err != nil {
+		return fmt.Errorf("bundle: %v", err)
+	}
+	if b.total += n; b.total > MaxBundleSize {
+		return fmt.Errorf("bundle: larger than %d bytes", MaxBundleSize)
+	}
+	return nil
+}
+
+// close returns the files copied as an fs.FS.
+func (b *bundleWriter) close() (fs.FS, error) {
+	if err := b.zw.Close(); err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b.buf.Bytes()), int64(b.buf.Len()))
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	return zr, nil
+}
+
+// limitReader reads up to n bytes of r, then fails with err instead of
+// ending the stream like io.LimitReader, so a truncated bundle can't pass
+// for a complete one
+type limitReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+// Read reads from l.r, failing once n bytes have been read.
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, l.err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	if l.n -= int64(n); l.n <= 0 {
+		return n, l.err
+	}
+	return n, err
+}
+
+// WriteBundle writes the files of fsys as a gzipped tar views bundle to w,
+// along with a manifest, and a signature of the bundle if sign is not nil,
+// e.g. an ed25519.Sign or SignHMAC closure over the digest. The signature
+// is written to signatureFile, SignatureFile if empty, the name passed to
+// VerifySignature.
+func WriteBundle(w io.Writer, fsys fs.FS, signatureFile string, sign func(digest []byte) ([]byte, error)) (*BundleManifest, error) {
+	if signatureFile == "" {
+		signatureFile = SignatureFile
+	}
+	files := make(map[string][]byte)
+	manifest := &BundleManifest{Created: time.Now().UTC(), Files: make(map[string]string)}
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path == ManifestFile || path == signatureFile {
+			return err
+		}
+		buf, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		files[path] = buf
+		sum := sha256.Sum256(buf)
+		manifest.Files[path] = hex.EncodeToString(sum[:])
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	if manifest.Version, err = digestFiles(files, signatureFile); err != nil {
+		return nil, err
+	}
+	if files[ManifestFile], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	if sign != nil {
+		// The signature covers the manifest too
+		digest, err := digestFiles(files, signatureFile)
+		if err != nil {
+			return nil, err
+		}
+		raw, _ := hex.DecodeString(digest)
+		signature, err := sign(raw)
+		if err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		files[signatureFile] = []byte(base64.StdEncoding.EncodeToString(signature))
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(files[name])), ModTime: manifest.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return nil, fmt.Errorf("bundle: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("bundle: %v", err)
+	}
+	return manifest, nil
+}
+
+// digestFiles returns the hex BundleDigest of the files, read back from
+// an in-memory zip so they are walked in the same order as once unpacked.
+func digestFiles(files map[string][]byte, signatureFile string) (string, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return "", fmt.Errorf("bundle: %v", err)
+		}
+		w.Write(content)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("bundle: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return "", fmt.Errorf("bundle: %v", err)
+	}
+	digest, err := BundleDigest(zr, signatureFile)
+	if err != nil {
+		return "", fmt.Errorf("bundle: %v", err)
+	}
+	return hex.EncodeToString(digest), nil
+}
@@ -0,0 +1,161 @@
+package html
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var bundleFiles = map[string]string{
+	"layouts/main.html": `<main>{{block "content" .}}{{end}}</main>`,
+	"index.html":        `{{define "content"}}<h1>{{.Title}}</h1>{{end}}`,
+}
+
+func zipBundle(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range bundleFiles {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip: %v\n", err)
+		}
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func tarBundle(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "./layouts/", Typeflag: tar.TypeDir, Mode: 0o755})
+	for name, content := range bundleFiles {
+		if err := tw.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("tar: %v\n", err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func Test_Bundle(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string][]byte{"views.zip": zipBundle(t), "views.tar.gz": tarBundle(t)} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, content, 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+		fsys, err := OpenBundle(file)
+		if err != nil {
+			t.Fatalf("open %s: %v\n", name, err)
+		}
+		engine := NewWithOptions(WithFS(fsys), WithLayout("layouts/main"))
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, "index", map[string]interface{}{"Title": name}); err != nil {
+			t.Fatalf("render %s: %v\n", name, err)
+		}
+		expect := `<main><h1>` + name + `</h1></main>`
+		if result := buf.String(); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+
+	// Hot swap
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	fsys, err := ReadBundle(bytes.NewReader(zipBundle(t)))
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	engine.SetFS(fsys).SetDirectory(".").Layout("layouts/main")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", map[string]interface{}{"Title": "swapped"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := buf.String(); result != `<main><h1>swapped</h1></main>` {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", `<main><h1>swapped</h1></main>`, result)
+	}
+}
+
+func Test_WriteBundle(t *testing.T) {
+	key := []byte("secret")
+	var buf bytes.Buffer
+	manifest, err := WriteBundle(&buf, os.DirFS("./views"), "", func(digest []byte) ([]byte, error) {
+		return SignHMAC(key, digest), nil
+	})
+	if err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	if manifest.Files["index.html"] == "" || manifest.Version == "" {
+		t.Fatalf("Unexpected manifest: %+v\n", manifest)
+	}
+	fsys, err := ReadBundle(&buf)
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	engine := NewWithOptions(WithFS(fsys), WithLayout("layouts/main"))
+	engine.VerifySignature(HMACVerifier(key), "")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	var out bytes.Buffer
+	if err := engine.Render(&out, "index", map[string]interface{}{"Title": "Bundled"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := trim(out.String()); !bytes.Contains([]byte(result), []byte("<h1>Bundled</h1>")) {
+		t.Fatalf("Expected the bundled index\nResult:\n%s\n", result)
+	}
+
+	// The signature is written under the name verified
+	buf.Reset()
+	if _, err = WriteBundle(&buf, os.DirFS("./views"), "release.sig", func(digest []byte) ([]byte, error) {
+		return SignHMAC(key, digest), nil
+	}); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	if fsys, err = ReadBundle(&buf); err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	engine = NewWithOptions(WithFS(fsys), WithLayout("layouts/main"))
+	engine.VerifySignature(HMACVerifier(key), "release.sig")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+}
+
+func Test_ReadBundleLimits(t *testing.T) {
+	defer func(total, file int64) {
+		MaxBundleSize, MaxBundleFileSize = total, file
+	}(MaxBundleSize, MaxBundleFileSize)
+	bundles := map[string][]byte{"zip": zipBundle(t), "tar.gz": tarBundle(t)}
+	for format, bundle := range bundles {
+		MaxBundleSize, MaxBundleFileSize = 1<<20, 44
+		if _, err := ReadBundle(bytes.NewReader(bundle)); err == nil || !strings.Contains(err.Error(), "index.html: larger than 44 bytes") {
+			t.Fatalf("%s: Expected the file limit to fail, got %v\n", format, err)
+		}
+		MaxBundleSize, MaxBundleFileSize = 60, 1<<20
+		if _, err := ReadBundle(bytes.NewReader(bundle)); err == nil || !strings.Contains(err.Error(), "larger than 60 bytes") {
+			t.Fatalf("%s: Expected the bundle limit to fail, got %v\n", format, err)
+		}
+		MaxBundleSize = int64(len(bundle) + 100)
+		if _, err := ReadBundle(bytes.NewReader(bundle)); err != nil {
+			t.Fatalf("%s: read: %v\n", format, err)
+		}
+	}
+}
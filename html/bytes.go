@@ -0,0 +1,74 @@
+package html
+
+import (
+	"context"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are not pooled, so
+// one large page does not pin its memory
+const maxPooledBuffer = 1 << 20
+
+// byteBuffer is a growable byte slice the templates write into
+type byteBuffer struct {
+	b []byte
+}
+
+func (b *byteBuffer) Write(p []byte) (int, error) {
+	b.b = append(b.b, p...)
+	return len(p), nil
+}
+
+func (b *byteBuffer) WriteString(s string) (int, error) {
+	b.b = append(b.b, s...)
+	return len(s), nil
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return &byteBuffer{b: make([]byte, 0, 4096)}
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *byteBuffer {
+	return bufferPool.Get().(*byteBuffer)
+}
+
+// putBuffer returns buf to the pool, its bytes must not be used after.
+func putBuffer(buf *byteBuffer) {
+	if cap(buf.b) > maxPooledBuffer {
+		return
+	}
+	buf.b = buf.b[:0]
+	bufferPool.Put(buf)
+}
+
+// renderBytes renders the template name into a pooled buffer, the caller
+// copies what it keeps and returns the buffer with putBuffer.
+func (e *Engine) renderBytes(ctx context.Context, name string, binding interface{}, partial bool) (*byteBuffer, error) {
+	buf := getBuffer()
+	if err := e.execute(ctx, buf, name, binding, partial); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// RenderString renders the template name along with the given values and
+// returns the result, e.g. for the body of a mail or a JSON field.
+func (e *Engine) RenderString(name string, binding interface{}) (string, error) {
+	return e.RenderStringContext(context.Background(), name, binding)
+}
+
+// RenderStringContext is like RenderString, ctx carries the parent span
+// and the per-request values of the render.
+func (e *Engine) RenderStringContext(ctx context.Context, name string, binding interface{}) (string, error) {
+	buf, err := e.renderBytes(ctx, name, binding, false)
+	if err != nil {
+		return "", err
+	}
+	s := string(buf.b)
+	putBuffer(buf)
+	return s, nil
+}
@@ -0,0 +1,27 @@
+package html
+
+import (
+	"testing"
+)
+
+func Test_RenderString(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	for i := 0; i < 2; i++ {
+		result, err := engine.RenderString("errors/404", map[string]interface{}{
+			"Error": "404 Not Found!",
+		})
+		if err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		expect := `<h1>404 Not Found!</h1>`
+		if result = trim(result); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+	if _, err := engine.RenderString("missing", nil); err == nil {
+		t.Fatalf("Expected missing template error\n")
+	}
+}
@@ -0,0 +1,323 @@
+package html
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fragmentKey is the context key of the fragment being rendered
+type fragmentKey struct{}
+
+// fragmentFrame collects the dependency keys of the fragment being
+// rendered, the root frame is the render itself
+type fragmentFrame struct {
+	tmpl  *template.Template
+	state *renderState
+	deps  []string
+}
+
+// depend adds key and its dependency keys to the frame.
+func (f *fragmentFrame) depend(key string, deps []string) {
+	f.deps = append(f.deps, key)
+	f.deps = append(f.deps, deps...)
+}
+
+// cacheEntry is a cached fragment or page
+type cacheEntry struct {
+	HTML    template.HTML `json:"html"`
+	Deps    []string      `json:"deps,omitempty"`
+	Expires time.Time     `json:"expires"`
+	// response headers restored along with a cached response, and the
+	// template Respond rendered it with
+	Header   map[string][]string `json:"header,omitempty"`
+	Template string              `json:"template,omitempty"`
+}
+
+// renderCache caches fragments or pages in a store, the keys of the
+// entries tagged with or depending on a key are stored along with them
+type renderCache struct {
+	mutex sync.Mutex
+	store CacheStore
+	// name of the cache in the store keys
+	name string
+	// namespace of the keys of a render, the prefix and template version
+	namespace func(ctx context.Context) string
+	ttl       time.Duration
+	// stale entries are served for that long while rendered again
+	stale time.Duration
+	// keys of the stale entries being rendered again
+	refreshing map[string]bool
+}
+
+// maxTagKeys bounds the keys listed by a tag, the oldest entries are
+// dropped beyond it
+const maxTagKeys = 1000
+
+func newRenderCache(store CacheStore, name string, namespace func(ctx context.Context) string, ttl, stale time.Duration) *renderCache {
+	return &renderCache{
+		store:      store,
+		name:       name,
+		namespace:  namespace,
+		ttl:        ttl,
+		stale:      stale,
+		refreshing: make(map[string]bool),
+	}
+}
+
+// entryKey returns the store key of the entry key.
+func (c *renderCache) entryKey(ctx context.Context, key string) string {
+	return c.namespace(ctx) + c.name + ":" + key
+}
+
+// tagKey returns the store key listing the entries depending on key.
+func (c *renderCache) tagKey(ctx context.Context, key string) string {
+	return c.namespace(ctx) + c.name + "-tag:" + key
+}
+
+// get returns the entry key unless it is missing or expired, revalidate
+// is true for the first get of a stale entry, which should render it again.
+func (c *renderCache) get(ctx context.Context, key string) (entry *cacheEntry, revalidate bool, err error) {
+	data, err := c.store.Get(ctx, c.entryKey(ctx, key))
+	if err != nil {
+		return nil, false, err
+	}
+	entry = &cacheEntry{}
+	if err = json.Unmarshal(data, entry); err != nil {
+		return nil, false, fmt.Errorf("cache: %s: %v", key, err)
+	}
+	now := time.Now()
+	if entry.Expires.IsZero() || now.Before(entry.Expires) {
+		return entry, false, nil
+	}
+	if now.Before(entry.Expires.Add(c.stale)) {
+		c.mutex.Lock()
+		revalidate = !c.refreshing[key]
+		c.refreshing[key] = true
+		c.mutex.Unlock()
+		return entry, revalidate, nil
+	}
+	return nil, false, ErrCacheMiss
+}
+
+// set stores the entry key along with its dependency keys.
+func (c *renderCache) set(ctx context.Context, key string, html template.HTML, deps []string) (*cacheEntry, error) {
+	entry := &cacheEntry{HTML: html, Deps: deps}
+	return entry, c.setEntry(ctx, key, entry)
+}
+
+// setEntry stores entry under key along with its dependency keys.
+func (c *renderCache) setEntry(ctx context.Context, key string, entry *cacheEntry) error {
+	deps := entry.Deps
+	var ttl time.Duration
+	if c.ttl > 0 {
+		entry.Expires = time.Now().Add(c.ttl)
+		ttl = c.ttl + c.stale
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err = c.store.Set(ctx, c.entryKey(ctx, key), data, ttl); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	seen := make(map[string]bool, len(deps))
+	for _, dep := range deps {
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+		keys, err := c.dependents(ctx, dep)
+		if err != nil {
+			return err
+		}
+		if containsString(keys, key) {
+			continue
+		}
+		keys = append(keys, key)
+		// Entries no longer listed could not be invalidated
+		for len(keys) > maxTagKeys {
+			if err = c.store.Delete(ctx, c.entryKey(ctx, keys[0])); err != nil {
+				return err
+			}
+			keys = keys[1:]
+		}
+		// The list outlives the entries set before it
+		if err = c.store.Set(ctx, c.tagKey(ctx, dep), []byte(strings.Join(keys, "\n")), ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dependents returns the keys of the entries depending on key.
+func (c *renderCache) dependents(ctx context.Context, key string) ([]string, error) {
+	data, err := c.store.Get(ctx, c.tagKey(ctx, key))
+	if err == ErrCacheMiss {
+		return nil, nil
+	}
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// refreshed marks the revalidation of the stale entry key as done.
+func (c *renderCache) refreshed(key string) {
+	c.mutex.Lock()
+	delete(c.refreshing, key)
+	c.mutex.Unlock()
+}
+
+// invalidate removes the entries of the keys and the entries depending
+// on them, including enclosing fragments, and returns the keys removed.
+func (c *renderCache) invalidate(ctx context.Context, keys ...string) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var deleted []string
+	for _, key := range keys {
+		dependents, err := c.dependents(ctx, key)
+		if err != nil {
+			return deleted, err
+		}
+		for _, dependent := range append(dependents, key) {
+			if err = c.store.Delete(ctx, c.entryKey(ctx, dependent)); err != nil {
+				return deleted, err
+			}
+			deleted = append(deleted, dependent)
+		}
+		if err = c.store.Delete(ctx, c.tagKey(ctx, key)); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// flush removes the entries of the namespace of ctx if the store
+// supports it, the others are left to expire.
+func (c *renderCache) flush(ctx context.Context) error {
+	if store, ok := c.store.(interface {
+		DeletePrefix(ctx context.Context, prefix string) error
+	}); ok {
+		if err := store.DeletePrefix(ctx, c.entryKey(ctx, "")); err != nil {
+			return err
+		}
+		return store.DeletePrefix(ctx, c.tagKey(ctx, ""))
+	}
+	return nil
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FragmentCache registers the cache func, which renders a template of the
+// page once and serves it from the cache until ttl expires, 0 keeps it
+// until invalidated: {{cache "product:42" "product" .Product "price:42"}}
+// renders the product template with .Product under the key product:42
+// depending on price:42. Fragments nest, a fragment depends on the keys of
+// the fragments rendered inside it, so invalidating a key drops the
+// enclosing fragments too, and {{cacheTag "key"}} adds tags from within
+// the fragment. Keys must tell apart whatever the output depends on, e.g.
+// the locale, and fragments are flushed on each load. Fragments live in
+// the CacheStore, failing stores make the fragments render uncached.
+func (e *Engine) FragmentCache(ttl time.Duration) *Engine {
+	e.fragments = newRenderCache(e.store(), "fragment", e.cacheNamespace, ttl, 0)
+	e.onClose(e.fragments.flush)
+	e.AddContextFunc("cacheTag", cacheTag)
+	return e.AddContextFunc("cache", e.cacheFragment)
+}
+
+// Invalidate purges the cached fragments, pages and responses of the keys
+// along with those tagged with or depending on them, e.g. after a model
+// update. Responses are tagged with their path.
+func (e *Engine) Invalidate(keys ...string) error {
+	ctx := context.Background()
+	for _, c := range e.caches() {
+		deleted, err := c.invalidate(ctx, keys...)
+		for _, key := range deleted {
+			e.event(ctx, slog.LevelDebug, "views: cache evicted", slog.String("cache", c.name), slog.String("key", key), slog.String("reason", "invalidated"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// caches returns the render caches in use.
+func (e *Engine) caches() []*renderCache {
+	var caches []*renderCache
+	for _, c := range []*renderCache{e.fragments, e.pages, e.responses} {
+		if c != nil {
+			caches = append(caches, c)
+		}
+	}
+	return caches
+}
+
+// cacheNamespace returns the namespace of the cache keys of the render of
+// ctx: the cache prefix and the version of its templates, so engines and
+// template versions sharing a store do not serve each other's output.
+func (e *Engine) cacheNamespace(ctx context.Context) string {
+	return e.cachePrefix + e.renderVersion(ctx).version + ":"
+}
+
+// flushCaches empties the render caches of the template set of ctx, the
+// current one if none, e.g. once it has been replaced.
+func (e *Engine) flushCaches(ctx context.Context) error {
+	for _, c := range e.caches() {
+		if err := c.flush(ctx); err != nil {
+			return err
+		}
+		e.event(ctx, slog.LevelDebug, "views: cache evicted", slog.String("cache", c.name), slog.String("namespace", c.namespace(ctx)), slog.String("reason", "replaced"))
+	}
+	return nil
+}
+
+// cacheFragment renders the template name with data under key.
+func (e *Engine) cacheFragment(ctx context.Context, key, name string, data interface{}, deps ...string) (template.HTML, error) {
+	parent, ok := ctx.Value(fragmentKey{}).(*fragmentFrame)
+	if !ok || e.fragments == nil {
+		return "", fmt.Errorf("cache: fragment %s rendered outside of the engine", key)
+	}
+	f, _, err := e.fragments.get(ctx, key)
+	if err == nil {
+		parent.depend(key, f.Deps)
+		return f.HTML, nil
+	}
+	if err != ErrCacheMiss {
+		e.event(ctx, slog.LevelError, "views: fragment cache failed", slog.String("key", key), slog.Any("error", err))
+	}
+	// Nested fragments add their keys to the frame
+	frame := &fragmentFrame{tmpl: parent.tmpl, state: parent.state, deps: append([]string(nil), deps...)}
+	state := parent.state
+	saved := state.ctx
+	state.ctx = context.WithValue(saved, fragmentKey{}, frame)
+	buf := getBuffer()
+	err = parent.tmpl.ExecuteTemplate(buf, name, data)
+	state.ctx = saved
+	content := template.HTML(buf.b)
+	putBuffer(buf)
+	if err != nil {
+		return "", err
+	}
+	if f, err = e.fragments.set(ctx, key, content, frame.deps); err != nil {
+		e.event(ctx, slog.LevelError, "views: fragment cache failed", slog.String("key", key), slog.Any("error", err))
+	}
+	parent.depend(key, f.Deps)
+	return f.HTML, nil
+}
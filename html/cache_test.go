@@ -0,0 +1,51 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_FragmentCache(t *testing.T) {
+	renders := 0
+	engine := New("./testdata/cache", ".html")
+	engine.FragmentCache(0)
+	engine.AddFunc("count", func() int {
+		renders++
+		return renders
+	})
+	type product struct {
+		ID   int
+		Name string
+	}
+	binding := map[string]interface{}{
+		"Products": []product{{1, "Tea"}, {2, "Cake"}},
+	}
+	render := func(expect string) {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, "products", binding); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		if result := trim(buf.String()); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+
+	render(`<ul><li>Tea 1</li><li>Cake 2</li></ul>3`)
+	render(`<ul><li>Tea 1</li><li>Cake 2</li></ul>3`)
+
+	// Invalidating a nested fragment renders the enclosing ones again
+	engine.Invalidate("product:1")
+	render(`<ul><li>Tea 4</li><li>Cake 2</li></ul>5`)
+
+	// Declared dependency keys
+	engine.Invalidate("catalog")
+	render(`<ul><li>Tea 4</li><li>Cake 2</li></ul>6`)
+
+	// Expiry
+	engine.FragmentCache(time.Nanosecond)
+	render(`<ul><li>Tea 7</li><li>Cake 8</li></ul>9`)
+	time.Sleep(time.Millisecond)
+	render(`<ul><li>Tea 10</li><li>Cake 11</li></ul>12`)
+}
@@ -0,0 +1,109 @@
+package html
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// TrackingParams are the query parameters stripped from the canonical URL
+// when Canonical is given none, a trailing * matches a prefix
+var TrackingParams = []string{"utm_*", "gclid", "dclid", "fbclid", "msclkid", "mc_cid", "mc_eid", "_ga"}
+
+// urlKey is the context key of the request URL
+type urlKey struct{}
+
+// WithURL returns a copy of ctx carrying the request URL the canonical
+// func builds on, Respond sets it from the Fiber context.
+func WithURL(ctx context.Context, u *url.URL) context.Context {
+	return context.WithValue(ctx, urlKey{}, u)
+}
+
+// RequestURL returns the request URL of ctx, nil if none was set.
+func RequestURL(ctx context.Context) *url.URL {
+	u, _ := ctx.Value(urlKey{}).(*url.URL)
+	return u
+}
+
+// Canonical registers {{canonical}}, which returns the canonical URL of
+// the request: its scheme, host, path and query without the strip
+// parameters, sorted, and without fragment. host, such as
+// https://www.example.com, replaces the scheme and host of the requests
+// when set. Without strip parameters TrackingParams are stripped.
+//
+// The Host header is set by the client, without host the URL keeps the
+// request host only if it is one of CanonicalHosts and is relative
+// otherwise, so a cached page cannot point to another site.
+func (e *Engine) Canonical(host string, strip ...string) *Engine {
+	if strip == nil {
+		strip = TrackingParams
+	}
+	e.canonical = true
+	e.canonicalHost, e.canonicalStrip = host, strip
+	return e.AddContextFunc("canonical", e.canonicalURL)
+}
+
+// CanonicalHosts sets the request hosts kept by the canonical URL when
+// Canonical has no host, patterns such as *.example.com match the
+// subdomains as with Host.
+func (e *Engine) CanonicalHosts(patterns ...string) *Engine {
+	hosts := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		hosts[i] = strings.ToLower(pattern)
+	}
+	e.canonicalHosts = hosts
+	return e
+}
+
+// canonicalURL returns the canonical URL of the request of ctx.
+func (e *Engine) canonicalURL(ctx context.Context) string {
+	u := url.URL{Path: Path(ctx)}
+	if r := RequestURL(ctx); r != nil {
+		u = *r
+	}
+	u.Fragment, u.RawFragment, u.User = "", "", nil
+	if host := e.canonicalHost; host != "" {
+		if scheme, rest, ok := strings.Cut(host, "://"); ok {
+			u.Scheme, host = scheme, rest
+		}
+		u.Host = strings.TrimSuffix(host, "/")
+	} else if !e.trustedHost(u.Hostname()) {
+		u.Scheme, u.Host = "", ""
+	}
+	if u.Path == "" && u.Host != "" {
+		u.Path = "/"
+	}
+	query := u.Query()
+	for key := range query {
+		if stripped(key, e.canonicalStrip) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// trustedHost reports whether the request hostname is one of
+// CanonicalHosts.
+func (e *Engine) trustedHost(hostname string) bool {
+	for _, pattern := range e.canonicalHosts {
+		if matchHost(pattern, hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripped reports whether the query parameter key matches one of params.
+func stripped(key string, params []string) bool {
+	for _, p := range params {
+		if prefix := strings.TrimSuffix(p, "*"); prefix != p {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == p {
+			return true
+		}
+	}
+	return false
+}
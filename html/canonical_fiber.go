@@ -0,0 +1,32 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// URLMiddleware sets the request URL in the user context of c, for the
+// adapters other than Respond.
+func (e *Engine) URLMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(e.withURL(c.UserContext(), c))
+		return c.Next()
+	}
+}
+
+// withURL returns ctx carrying the URL of c if the canonical func is used.
+func (e *Engine) withURL(ctx context.Context, c *fiber.Ctx) context.Context {
+	if !e.canonical {
+		return ctx
+	}
+	return WithURL(ctx, &url.URL{
+		Scheme:   c.Protocol(),
+		Host:     c.Hostname(),
+		Path:     c.Path(),
+		RawQuery: string(c.Request().URI().QueryString()),
+	})
+}
@@ -0,0 +1,43 @@
+//go:build !noos
+
+package html
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Canonical(t *testing.T) {
+	tests := []struct {
+		host   string
+		hosts  []string
+		strip  []string
+		path   string
+		expect string
+	}{
+		{"", []string{"example.com"}, nil, "/shop?utm_source=x&page=2&fbclid=y", `<link rel="canonical" href="http://example.com/shop?page=2">`},
+		{"", []string{"*.example.com"}, nil, "/shop?page=2", `<link rel="canonical" href="/shop?page=2">`},
+		{"", nil, nil, "/shop", `<link rel="canonical" href="/shop">`},
+		{"https://www.example.com/", nil, nil, "/shop?b=2&a=1", `<link rel="canonical" href="https://www.example.com/shop?a=1&amp;b=2">`},
+		{"www.example.com", nil, []string{"ref", "sort"}, "/shop?ref=mail&utm_source=x", `<link rel="canonical" href="http://www.example.com/shop?utm_source=x">`},
+	}
+	for _, tt := range tests {
+		engine := New("./testdata/canonical", ".html")
+		engine.Canonical(tt.host, tt.strip...).CanonicalHosts(tt.hosts...)
+		app := fiber.New()
+		app.Get("/shop", func(c *fiber.Ctx) error {
+			return engine.Respond(c, "page", nil)
+		})
+		resp, err := app.Test(httptest.NewRequest("GET", "http://example.com"+tt.path, nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if result := trim(string(body)); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package html
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Catalog registers the t func backed by a golang.org/x/text message
+// catalog: {{t "%d items" .Count}} is formatted by a message.Printer for
+// the render locale, which selects plural forms and formats numbers.
+// The catalog language is matched against the locale fallback chain.
+func (e *Engine) Catalog(cat catalog.Catalog) *Engine {
+	c := &catalogPrinters{cat: cat}
+	e.catalog = c
+	return e.AddContextFunc("t", func(ctx context.Context, key string, args ...interface{}) string {
+		return c.printer(e.localeChain(ctx)).Sprintf(key, args...)
+	})
+}
+
+// catalogPrinters caches a printer per catalog language
+type catalogPrinters struct {
+	cat      catalog.Catalog
+	printers sync.Map
+}
+
+// printer returns the printer of the catalog language best matching locales.
+func (c *catalogPrinters) printer(locales []string) *message.Printer {
+	tags := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		if tag, err := language.Parse(locale); err == nil {
+			tags = append(tags, tag)
+		}
+	}
+	var tag language.Tag
+	if languages := c.cat.Languages(); len(languages) > 0 {
+		_, index, _ := c.cat.Matcher().Match(tags...)
+		tag = languages[index]
+	}
+	if p, ok := c.printers.Load(tag); ok {
+		return p.(*message.Printer)
+	}
+	p := message.NewPrinter(tag, message.Catalog(c.cat))
+	c.printers.Store(tag, p)
+	return p
+}
@@ -0,0 +1,52 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+func Test_Catalog(t *testing.T) {
+	builder := catalog.NewBuilder(catalog.Fallback(language.English))
+	builder.SetString(language.English, "Hello, %s!", "Hello, %s!")
+	builder.Set(language.English, "%d items", plural.Selectf(1, "%d",
+		"=1", "one item",
+		"other", "%d items",
+	))
+	builder.SetString(language.French, "Hello, %s!", "Bonjour, %s !")
+	builder.Set(language.French, "%d items", plural.Selectf(1, "%d",
+		"one", "%d article",
+		"other", "%d articles",
+	))
+
+	engine := New("./testdata/catalog", ".html")
+	engine.Catalog(builder)
+
+	for _, tc := range []struct {
+		locale string
+		count  int
+		expect string
+	}{
+		{"en", 1, `<p>Hello, John! one item</p>`},
+		{"en-US", 1200, `<p>Hello, John! 1,200 items</p>`},
+		{"fr-CA", 1, `<p>Bonjour, John ! 1 article</p>`},
+		{"de", 3, `<p>Hello, John! 3 items</p>`},
+	} {
+		var buf bytes.Buffer
+		ctx := WithLocale(context.Background(), tc.locale)
+		if err := engine.RenderContext(ctx, &buf, "cart", map[string]interface{}{
+			"Name":  "John",
+			"Count": tc.count,
+		}); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		result := trim(buf.String())
+		if tc.expect != result {
+			t.Fatalf("Locale %s\nExpected:\n%s\nResult:\n%s\n", tc.locale, tc.expect, result)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package html
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"sort"
+)
+
+// ReloadDiff lists the templates changed by a reload, theme templates are
+// named theme:name
+type ReloadDiff struct {
+	Added    []string         `json:"added,omitempty"`
+	Removed  []string         `json:"removed,omitempty"`
+	Modified []TemplateChange `json:"modified,omitempty"`
+}
+
+// TemplateChange is a template whose content changed
+type TemplateChange struct {
+	Name string `json:"name"`
+	// content hashes before and after the reload
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Empty tells whether the reload changed no template.
+func (d ReloadDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// LogValue logs the names of the changed templates.
+func (d ReloadDiff) LogValue() slog.Value {
+	modified := make([]string, len(d.Modified))
+	for i, change := range d.Modified {
+		modified[i] = change.Name + " " + change.Before + ".." + change.After
+	}
+	return slog.GroupValue(
+		slog.Any("added", d.Added),
+		slog.Any("removed", d.Removed),
+		slog.Any("modified", modified),
+	)
+}
+
+// contentSum returns the hash of a template file reported by ReloadDiff.
+func contentSum(buf []byte) string {
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:8])
+}
+
+// sumKey returns the name of a template of theme in ReloadDiff.
+func sumKey(theme, name string) string {
+	if theme == "" {
+		return name
+	}
+	return theme + ":" + name
+}
+
+// diffSums compares the content hashes of two loads.
+func diffSums(before, after map[string]string) ReloadDiff {
+	var diff ReloadDiff
+	for name, sum := range after {
+		if old, ok := before[name]; !ok {
+			diff.Added = append(diff.Added, name)
+		} else if old != sum {
+			diff.Modified = append(diff.Modified, TemplateChange{Name: name, Before: old, After: sum})
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Modified, func(i, j int) bool {
+		return diff.Modified[i].Name < diff.Modified[j].Name
+	})
+	return diff
+}
@@ -0,0 +1,46 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_ReloadChanges(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+	write("index.html", "index")
+	write("about.html", "about")
+	write("old.html", "old")
+	engine := New(dir, ".html")
+	var changes ReloadDiff
+	engine.OnReload(func(ctx context.Context, stats Stats) {
+		changes = stats.Changes
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	write("index.html", "index changed")
+	write("new.html", "new")
+	if err := os.Remove(filepath.Join(dir, "old.html")); err != nil {
+		t.Fatalf("remove: %v\n", err)
+	}
+	engine.Reload(true)
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+
+	expect := fmt.Sprintf("added [new] removed [old] modified [{index %s %s}]", contentSum([]byte("index")), contentSum([]byte("index changed")))
+	result := fmt.Sprintf("added %v removed %v modified %v", changes.Added, changes.Removed, changes.Modified)
+	if result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
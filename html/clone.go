@@ -0,0 +1,124 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"sync"
+	"sync/atomic"
+)
+
+// Clone returns a copy of the engine sharing the parsed templates until
+// the copy changes its layout, delimiters or funcs, which makes it parse
+// its own templates on the next render. Globals, hooks and tenants are
+// independent, the tenants of the copy start with the funcs and globals of
+// the original ones. The configuration of helper funcs registered before
+// cloning, such as sanitize policies, is shared.
+// The fragment, page and response caches of the copy start empty, under a
+// CachePrefix of their own in the shared CacheStore.
+func (e *Engine) Clone() *Engine {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	c := *e
+	c.mutex = &sync.RWMutex{}
+	c.loaded = &atomic.Bool{}
+	c.loaded.Store(e.loaded.Load())
+	c.current = &atomic.Pointer[templateVersion]{}
+	c.current.Store(e.current.Load())
+	c.stats = &engineStats{}
+	c.life = newLifecycle()
+	c.refreshStop = nil
+	c.sriHashes = newSRIHashes()
+	c.cachePrefix = fmt.Sprintf("%sclone%d:", e.cachePrefix, clones.Add(1))
+	c.fragments = c.cloneCache(e.fragments)
+	c.pages = c.cloneCache(e.pages)
+	c.responses = c.cloneCache(e.responses)
+	c.funcmap = copyMap(e.funcmap)
+	c.ctxfuncs = copyMap(e.ctxfuncs)
+	c.settings = &atomic.Pointer[renderSettings]{}
+	c.globals = copyMap(e.globals)
+	c.tenants = make(map[string]*Tenant, len(e.tenants))
+	for name, t := range e.tenants {
+		// Tenant funcs call the engine they belong to
+		rebound := &Tenant{engine: &c, name: name}
+		d := t.data.Load()
+		rebound.data.Store(d)
+		for fn := range d.funcs {
+			c.ctxfuncs[fn] = c.tenantFunc(fn)
+			c.loaded.Store(false)
+		}
+		c.tenants[name] = rebound
+	}
+	c.functags = make(map[string][]string, len(e.functags))
+	for name, tags := range e.functags {
+		c.functags[name] = tags
+	}
+	c.sanitizers = make(map[string]Sanitizer, len(e.sanitizers))
+	for name, policy := range e.sanitizers {
+		c.sanitizers[name] = policy
+	}
+	c.fallbacks = make(map[string][]string, len(e.fallbacks))
+	for locale, fallbacks := range e.fallbacks {
+		c.fallbacks[locale] = fallbacks
+	}
+	c.variants = make(map[string]map[string]string, len(e.variants))
+	for name, variants := range e.variants {
+		c.variants[name] = variants
+	}
+	c.required = make(map[string][]string, len(e.required))
+	for name, keys := range e.required {
+		c.required[name] = append(keys[:0:0], keys...)
+	}
+	c.emails = make(map[string]bool, len(e.emails))
+	for name := range e.emails {
+		c.emails[name] = true
+	}
+	c.prefixes = append(e.prefixes[:0:0], e.prefixes...)
+	c.sitemap = append(e.sitemap[:0:0], e.sitemap...)
+	c.mounts = append(e.mounts[:0:0], e.mounts...)
+	c.packs = append(e.packs[:0:0], e.packs...)
+	c.overrides = make(map[string]*template.Template, len(e.overrides))
+	for name, tmpl := range e.overrides {
+		c.overrides[name] = tmpl
+	}
+	c.layouts = append(e.layouts[:0:0], e.layouts...)
+	c.hosts = append(e.hosts[:0:0], e.hosts...)
+	c.maintenanceAllow = append(e.maintenanceAllow[:0:0], e.maintenanceAllow...)
+	c.merged = append(e.merged[:0:0], e.merged...)
+	c.history = append(e.history[:0:0], e.history...)
+	c.warmTargets = append(e.warmTargets[:0:0], e.warmTargets...)
+	c.postProcessors = append(e.postProcessors[:0:0], e.postProcessors...)
+	c.preProcessors = append(e.preProcessors[:0:0], e.preProcessors...)
+	c.transforms = append(e.transforms[:0:0], e.transforms...)
+	c.onRender = append(e.onRender[:0:0], e.onRender...)
+	c.onLoad = append(e.onLoad[:0:0], e.onLoad...)
+	c.onReload = append(e.onReload[:0:0], e.onReload...)
+	c.onError = append(e.onError[:0:0], e.onError...)
+	c.onRenderError = append(e.onRenderError[:0:0], e.onRenderError...)
+	c.publish()
+	return &c
+}
+
+// clones numbers the copies made by Clone
+var clones atomic.Int64
+
+// cloneCache returns an empty cache configured as r, nil if r is.
+func (e *Engine) cloneCache(r *renderCache) *renderCache {
+	if r == nil {
+		return nil
+	}
+	c := newRenderCache(r.store, r.name, e.cacheNamespace, r.ttl, r.stale)
+	e.onClose(c.flush)
+	return c
+}
+
+// copyMap returns a shallow copy of m.
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,83 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_Clone(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+
+	// Shares the parsed templates
+	shared := engine.Clone()
+	shared.Globals(map[string]interface{}{"Title": "Shared"})
+	var buf bytes.Buffer
+	if err := shared.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if shared.Templates["index"] != engine.Templates["index"] || shared.Stats().Loads != 0 {
+		t.Fatalf("Expected clone to share the parsed templates\n")
+	}
+	if result := trim(buf.String()); !strings.Contains(result, "<h1>Shared</h1>") {
+		t.Fatalf("Expected clone globals\nResult:\n%s\n", result)
+	}
+
+	// Parses its own templates once changed
+	clone := engine.Clone()
+	clone.Layout("layouts/assets")
+	clone.AddFunc("isAdmin", func(user string) bool {
+		return false
+	})
+	buf.Reset()
+	if err := clone.Render(&buf, "admin", map[string]interface{}{"User": "admin"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := trim(buf.String()); !strings.Contains(result, "<title>Assets</title>") {
+		t.Fatalf("Expected clone layout\nResult:\n%s\n", result)
+	}
+	if clone.Templates["index"] == engine.Templates["index"] {
+		t.Fatalf("Expected clone to parse its own templates\n")
+	}
+
+	buf.Reset()
+	if err := engine.Render(&buf, "index", map[string]interface{}{"Title": "Hello, World!"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_CloneCaches(t *testing.T) {
+	engine := New("./testdata/pagecache", ".html").RenderCache(0, 0)
+	engine.AddFunc("count", func() int { return 0 })
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	ctx := context.Background()
+	if _, err := engine.pages.set(ctx, "/counter", "original", nil); err != nil {
+		t.Fatalf("set: %v\n", err)
+	}
+	clone := engine.Clone()
+	if clone.pages == engine.pages {
+		t.Fatalf("Expected the clone to have its own page cache\n")
+	}
+	if _, _, err := clone.pages.get(ctx, "/counter"); err != ErrCacheMiss {
+		t.Fatalf("Expected the clone cache to start empty, got %v\n", err)
+	}
+	clone.pages.set(ctx, "/counter", "clone", nil)
+	if entry, _, err := engine.pages.get(ctx, "/counter"); err != nil || entry.HTML != "original" {
+		t.Fatalf("Expected the original page to be kept, got %v\n", err)
+	}
+}
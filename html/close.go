@@ -0,0 +1,91 @@
+package html
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned by renders of a closed engine
+var ErrClosed = errors.New("views: engine closed")
+
+// lifecycle tracks the in-flight renders and background resources
+type lifecycle struct {
+	mutex   sync.Mutex
+	closed  bool
+	renders sync.WaitGroup
+	// closed by Close to stop background goroutines
+	done chan struct{}
+	// release background resources, called in reverse order
+	closers []func(ctx context.Context) error
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{done: make(chan struct{})}
+}
+
+// enter records a render, it fails once the engine is closed.
+func (l *lifecycle) enter() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	l.renders.Add(1)
+	return nil
+}
+
+// leave records the end of a render.
+func (l *lifecycle) leave() {
+	l.renders.Done()
+}
+
+// onClose registers fn to release a background resource on Close.
+func (e *Engine) onClose(fn func(ctx context.Context) error) {
+	e.life.mutex.Lock()
+	e.life.closers = append(e.life.closers, fn)
+	e.life.mutex.Unlock()
+}
+
+// Close stops the background goroutines of the engine, waits for the
+// in-flight renders and releases caches and loaders, later renders fail
+// with ErrClosed. It returns ctx.Err() if ctx is done first, Close may be
+// called again to keep waiting. It is meant to be called as part
+// of app.Hooks().OnShutdown in Fiber:
+//
+//	app.Hooks().OnShutdown(func() error {
+//		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//		defer cancel()
+//		return engine.Close(ctx)
+//	})
+func (e *Engine) Close(ctx context.Context) error {
+	l := e.life
+	l.mutex.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.done)
+	}
+	l.mutex.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		l.renders.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	l.mutex.Lock()
+	closers := l.closers
+	l.closers = nil
+	l.mutex.Unlock()
+	var err error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if cerr := closers[i](ctx); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Close(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	engine := New("./testdata/close", ".html")
+	engine.AddContextFunc("wait", func(ctx context.Context) string {
+		close(started)
+		<-release
+		return "done"
+	})
+	var closed bool
+	engine.onClose(func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+
+	var buf bytes.Buffer
+	rendered := make(chan error)
+	go func() {
+		rendered <- engine.Render(&buf, "index", nil)
+	}()
+	<-started
+
+	// Times out while the render is in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := engine.Close(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected deadline exceeded, got %v\n", err)
+	}
+	if closed {
+		t.Fatalf("Expected resources to be kept until renders finish\n")
+	}
+	if err := engine.Render(&buf, "index", nil); err != ErrClosed {
+		t.Fatalf("Expected ErrClosed, got %v\n", err)
+	}
+
+	close(release)
+	if err := engine.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v\n", err)
+	}
+	if err := <-rendered; err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<p>done</p>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if !closed {
+		t.Fatalf("Expected resources to be released\n")
+	}
+}
+
+func Test_CloseReleases(t *testing.T) {
+	store := NewLRUStore(10)
+	engine := New("./testdata/close", ".html")
+	engine.CacheStore(store).FragmentCache(time.Minute).RefreshEvery(time.Hour)
+	if _, err := engine.fragments.set(context.Background(), "key", "cached", nil); err != nil {
+		t.Fatalf("set: %v\n", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := engine.Close(ctx); err != nil {
+		t.Fatalf("close: %v\n", err)
+	}
+	if n := store.Len(); n != 0 {
+		t.Fatalf("Expected the fragments to be flushed, %d keys left\n", n)
+	}
+}
@@ -0,0 +1,248 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Group is a group of items made by groupBy, sharing the value Key
+type Group struct {
+	Key   interface{}
+	Items []interface{}
+}
+
+// collectionFuncs are the funcs registered by CollectionFuncs
+var collectionFuncs = template.FuncMap{
+	"groupBy": groupBy,
+	"sortBy":  sortBy,
+	"where":   where,
+	"chunk":   chunk,
+}
+
+// CollectionFuncs registers the funcs reshaping slices, arrays and maps for
+// display, fields are struct fields or map keys, dotted for nested ones:
+//   - {{range groupBy .Posts "Author.Name"}}{{.Key}}: {{len .Items}}{{end}}
+//     groups the items in order of first appearance
+//   - {{sortBy .Posts "Date" "desc"}} sorts by field, ascending by default,
+//     items missing the field go last
+//   - {{where .Posts "Draft" false}} keeps the items whose field equals the
+//     value, {{where .Posts "Price" "gt" 10}} compares with eq, ne, lt,
+//     le, gt or ge
+//   - {{range chunk 3 .Products}}<div class="row">...</div>{{end}} splits
+//     into rows of at most 3 items
+//
+// Maps are taken as their values in key order. Numbers compare by value
+// whatever their type, strings and times by order.
+func (e *Engine) CollectionFuncs() *Engine {
+	for name, fn := range collectionFuncs {
+		e.AddFunc(name, fn)
+	}
+	return e
+}
+
+// groupBy groups the items of list by the value of field.
+func groupBy(list interface{}, field string) ([]Group, error) {
+	items, err := listOf("groupBy", list)
+	if err != nil {
+		return nil, err
+	}
+	var groups []Group
+	for _, item := range items {
+		key, _ := fieldOf(item, field)
+		var k interface{}
+		if key.IsValid() {
+			k = key.Interface()
+		}
+		found := false
+		for i := range groups {
+			if compareValues(reflect.ValueOf(groups[i].Key), key) == 0 {
+				groups[i].Items = append(groups[i].Items, item)
+				found = true
+				break
+			}
+		}
+		if !found {
+			groups = append(groups, Group{Key: k, Items: []interface{}{item}})
+		}
+	}
+	return groups, nil
+}
+
+// sortBy returns the items of list sorted by field, in the order asc or
+// desc.
+func sortBy(list interface{}, field string, order ...string) ([]interface{}, error) {
+	items, err := listOf("sortBy", list)
+	if err != nil {
+		return nil, err
+	}
+	desc := false
+	if len(order) > 0 {
+		switch order[0] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return nil, fmt.Errorf("sortBy: order %q is not asc or desc", order[0])
+		}
+	}
+	sorted := append([]interface{}(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, aok := fieldOf(sorted[i], field)
+		b, bok := fieldOf(sorted[j], field)
+		if !aok || !bok {
+			return aok && !bok
+		}
+		if desc {
+			return compareValues(b, a) < 0
+		}
+		return compareValues(a, b) < 0
+	})
+	return sorted, nil
+}
+
+// where returns the items of list whose field compares to the value with
+// the operator, eq if args is the value alone.
+func where(list interface{}, field string, args ...interface{}) ([]interface{}, error) {
+	items, err := listOf("where", list)
+	if err != nil {
+		return nil, err
+	}
+	op := "eq"
+	var value interface{}
+	switch len(args) {
+	case 1:
+		value = args[0]
+	case 2:
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("where: operator %v is not a string", args[0])
+		}
+		op, value = s, args[1]
+	default:
+		return nil, fmt.Errorf("where: expected a value or an operator and a value")
+	}
+	var match func(c int) bool
+	switch op {
+	case "eq":
+		match = func(c int) bool { return c == 0 }
+	case "ne":
+		match = func(c int) bool { return c != 0 }
+	case "lt":
+		match = func(c int) bool { return c < 0 }
+	case "le":
+		match = func(c int) bool { return c <= 0 }
+	case "gt":
+		match = func(c int) bool { return c > 0 }
+	case "ge":
+		match = func(c int) bool { return c >= 0 }
+	default:
+		return nil, fmt.Errorf("where: unknown operator %s", op)
+	}
+	var kept []interface{}
+	for _, item := range items {
+		v, ok := fieldOf(item, field)
+		if ok && match(compareValues(v, reflect.ValueOf(value))) {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+// chunk splits the items of list into chunks of size items, the last one
+// holds the rest.
+func chunk(size int, list interface{}) ([][]interface{}, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("chunk: size %d is not positive", size)
+	}
+	items, err := listOf("chunk", list)
+	if err != nil {
+		return nil, err
+	}
+	var chunks [][]interface{}
+	for len(items) > size {
+		chunks = append(chunks, items[:size:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		chunks = append(chunks, items)
+	}
+	return chunks, nil
+}
+
+// listOf returns the elements of the slice or array list, or the values of
+// the map list in key order.
+func listOf(name string, list interface{}) ([]interface{}, error) {
+	v := indirect(reflect.ValueOf(list))
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+		return items, nil
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return compareValues(keys[i], keys[j]) < 0
+		})
+		items := make([]interface{}, len(keys))
+		for i, key := range keys {
+			items[i] = v.MapIndex(key).Interface()
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("%s: %T is not a slice, an array or a map", name, list)
+}
+
+// fieldOf returns the value of the dotted field of item.
+func fieldOf(item interface{}, field string) (reflect.Value, bool) {
+	v, ok := bindingPath(reflect.ValueOf(item), strings.Split(field, "."))
+	return indirect(v), ok
+}
+
+// compareValues returns -1, 0 or 1 as a is less than, equal to or greater
+// than b. Numbers compare by value, strings, times and bools by order, the
+// other values by their printed form.
+func compareValues(a, b reflect.Value) int {
+	a, b = indirect(a), indirect(b)
+	if !a.IsValid() || !b.IsValid() {
+		return btoi(a.IsValid()) - btoi(b.IsValid())
+	}
+	if _, x, _, err := number("", a.Interface()); err == nil {
+		if _, y, _, err := number("", b.Interface()); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := a.Interface().(time.Time); ok {
+		if y, ok := b.Interface().(time.Time); ok {
+			return x.Compare(y)
+		}
+	}
+	if a.Kind() == reflect.Bool && b.Kind() == reflect.Bool {
+		return btoi(a.Bool()) - btoi(b.Bool())
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String())
+	}
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+// btoi returns 1 for true and 0 for false.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_CollectionFuncs(t *testing.T) {
+	type author struct{ Name string }
+	type post struct {
+		Title  string
+		Author *author
+		Votes  uint
+		Draft  bool
+	}
+	ann, bob := &author{"Ann"}, &author{"Bob"}
+	posts := []post{
+		{"A", ann, 3, false},
+		{"B", bob, 8, true},
+		{"C", ann, 5, false},
+	}
+	engine := New("./testdata/collections", ".html")
+	engine.CollectionFuncs()
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "posts", map[string]interface{}{"Posts": posts}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h2>Ann</h2><p>C</p><p>A</p><h2>Bob</h2><p>B</p>` +
+		`<ul><li>B</li><li>C</li></ul>` +
+		`<ul><li>B</li></ul>` +
+		`<div>A;B;</div><div>C;</div>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	sorted, err := sortBy(map[string]map[string]int{"x": {"n": 2}, "y": {"n": 1}, "z": {}}, "n")
+	if err != nil {
+		t.Fatalf("sortBy: %v\n", err)
+	}
+	if len(sorted) != 3 || sorted[0].(map[string]int)["n"] != 1 || len(sorted[2].(map[string]int)) != 0 {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", "[map[n:1] map[n:2] map[]]", sorted)
+	}
+	if _, err := chunk(0, posts); err == nil {
+		t.Fatalf("Expected error for a zero chunk size\n")
+	}
+}
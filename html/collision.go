@@ -0,0 +1,76 @@
+package html
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CollisionError is returned by Load when several files map to template
+// names differing only in case, which case-insensitive file systems and
+// deploy targets cannot tell apart
+type CollisionError struct {
+	// name of the template kept, the one of the first path
+	Name string
+	// conflicting paths in walk order
+	Paths []string
+}
+
+func (c *CollisionError) Error() string {
+	return fmt.Sprintf("views: template %s is defined by several files: %s", c.Name, strings.Join(c.Paths, ", "))
+}
+
+// OnCollision sets a hook called with the collisions found by Load instead
+// of failing it. Files are walked in lexical order and the first file of
+// a name is kept, so the result is the same on every machine.
+func (e *Engine) OnCollision(fn func(err *CollisionError)) *Engine {
+	e.onCollision = fn
+	return e
+}
+
+// collisions tracks the files of each template name during a walk
+type collisions struct {
+	// paths by lower case template name
+	paths map[string][]string
+	// first template name of each lower case name
+	names map[string]string
+}
+
+func newCollisions() *collisions {
+	return &collisions{
+		paths: make(map[string][]string),
+		names: make(map[string]string),
+	}
+}
+
+// add records path for name and reports whether it is the first file of
+// that name.
+func (c *collisions) add(name, path string) bool {
+	key := strings.ToLower(name)
+	c.paths[key] = append(c.paths[key], path)
+	if _, ok := c.names[key]; ok {
+		return false
+	}
+	c.names[key] = name
+	return true
+}
+
+// check reports the collisions to the hook of e, or returns the first in
+// name order without one.
+func (c *collisions) check(e *Engine) error {
+	keys := make([]string, 0, len(c.paths))
+	for key, paths := range c.paths {
+		if len(paths) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		err := &CollisionError{Name: c.names[key], Paths: c.paths[key]}
+		if e.onCollision == nil {
+			return err
+		}
+		e.onCollision(err)
+	}
+	return nil
+}
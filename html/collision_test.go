@@ -0,0 +1,73 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Collision(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"index.html", "Index.html"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+	engine := New(dir, ".html")
+	var collision *CollisionError
+	if err := engine.Load(); !errors.As(err, &collision) {
+		t.Fatalf("Expected a collision error, got %v\n", err)
+	}
+	expect := `views: template Index is defined by several files: Index.html, index.html`
+	if result := collision.Error(); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	var reported []*CollisionError
+	engine = New(dir, ".html")
+	engine.OnCollision(func(err *CollisionError) {
+		reported = append(reported, err)
+	})
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "Index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if buf.String() != "Index.html" || len(reported) != 1 {
+		t.Fatalf("Expected Index.html to win with 1 collision, got %q and %d\n", buf.String(), len(reported))
+	}
+}
+
+func Test_IgnoreExtensionCase(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"INDEX.HTML", "about.Html"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+	engine := New(dir, ".html")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "about", nil); err == nil {
+		t.Fatalf("Expected about.Html to be skipped\n")
+	}
+	engine.IgnoreExtensionCase(true)
+	for _, name := range []string{"INDEX", "about"} {
+		buf.Reset()
+		if err := engine.Render(&buf, name, nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+	}
+	if buf.String() != "about.Html" {
+		t.Fatalf("Unexpected output %q\n", buf.String())
+	}
+
+	// index.html and index.HTML map to the same name
+	if err := os.WriteFile(filepath.Join(dir, "about.html"), nil, 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	var collision *CollisionError
+	if err := New(dir, ".html").IgnoreExtensionCase(true).Load(); !errors.As(err, &collision) || len(collision.Paths) != 2 {
+		t.Fatalf("Expected a collision error, got %v\n", err)
+	}
+}
@@ -0,0 +1,92 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"io"
+	"net/http"
+)
+
+// Compat renders like gofiber/template/html to ease switching: layouts are
+// passed to Render, as in c.Render("index", data, "layouts/main"), and
+// include the page with {{embed}}. The setters of the upstream engine
+// return the Compat so chained configurations keep working, the others
+// are those of Engine. Migrate lists what does not carry over.
+type Compat struct {
+	*Engine
+	// name of the func including the page in the layout
+	embed string
+}
+
+// embedKey is the context key of the page included by the layout
+type embedKey struct{}
+
+// NewCompat returns a compat engine reading the views of directory.
+func NewCompat(directory, extension string) *Compat {
+	return newCompat(New(directory, extension))
+}
+
+// NewCompatFileSystem returns a compat engine reading the views of fs.
+func NewCompatFileSystem(fs http.FileSystem, extension string) *Compat {
+	return newCompat(NewFileSystem(fs, extension))
+}
+
+func newCompat(e *Engine) *Compat {
+	c := &Compat{Engine: e}
+	return c.Layout("embed")
+}
+
+// Layout sets the name of the func including the page in the layout,
+// embed by default. Unlike Engine.Layout it does not name a layout file.
+func (c *Compat) Layout(key string) *Compat {
+	c.embed = key
+	c.Engine.AddContextFunc(key, func(ctx context.Context) template.HTML {
+		page, _ := ctx.Value(embedKey{}).(template.HTML)
+		return page
+	})
+	return c
+}
+
+// AddFunc adds the function to the template's function map.
+func (c *Compat) AddFunc(name string, fn interface{}) *Compat {
+	c.Engine.AddFunc(name, fn)
+	return c
+}
+
+// Reload if set to true the templates are reloading on each render.
+func (c *Compat) Reload(enabled bool) *Compat {
+	c.Engine.Reload(enabled)
+	return c
+}
+
+// Debug will print the parsed templates when Load is triggered.
+func (c *Compat) Debug(enabled bool) *Compat {
+	c.Engine.Debug(enabled)
+	return c
+}
+
+// Delims sets the action delimiters to the specified strings.
+func (c *Compat) Delims(left, right string) *Compat {
+	c.Engine.Delims(left, right)
+	return c
+}
+
+// Render executes the template name, within the first layout if given.
+func (c *Compat) Render(out io.Writer, name string, binding interface{}, layout ...string) error {
+	return c.RenderContext(context.Background(), out, name, binding, layout...)
+}
+
+// RenderContext executes the template name with the render context,
+// within the first layout if given.
+func (c *Compat) RenderContext(ctx context.Context, out io.Writer, name string, binding interface{}, layout ...string) error {
+	if len(layout) == 0 || layout[0] == "" {
+		return c.Engine.RenderContext(ctx, out, name, binding)
+	}
+	var page bytes.Buffer
+	if err := c.Engine.RenderContext(ctx, &page, name, binding); err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, embedKey{}, template.HTML(page.String()))
+	return c.Engine.RenderContext(ctx, out, layout[0], binding)
+}
@@ -0,0 +1,38 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func Test_Compat(t *testing.T) {
+	engine := NewCompat("./testdata/compat", ".html").Reload(true)
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "plain", map[string]interface{}{"Title": "Hello"}, "layouts/main"); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<main><h1>Hello</h1></main>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	buf.Reset()
+	if err := engine.Render(&buf, "plain", map[string]interface{}{"Title": "Hello"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if expect, result := `<h1>Hello</h1>`, trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_Migrate(t *testing.T) {
+	notes, err := Migrate(os.DirFS("./testdata/compat"), AnalyzeConfig{})
+	if err != nil {
+		t.Fatalf("migrate: %v\n", err)
+	}
+	expect := `[layouts/main:1:8: layout layouts/main includes the page with {{embed}}, render it with Compat or set Layout("layouts/main") with a {{block "content" .}}{{end}} in place of {{embed}} and the pages in {{define "content"}} index:1:30: includes the view partials/footer, views are parsed separately, define it in the layout instead]`
+	if result := fmt.Sprint(notes); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
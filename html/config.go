@@ -0,0 +1,75 @@
+package html
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// Config configures the engine built by NewWithConfig
+type Config struct {
+	// views folder, relative to the root of FS if set, defaults to ./views
+	Directory string
+	// file system holding the views, e.g. an embed.FS, optional
+	FS fs.FS
+	// views extension including the dot, defaults to .html
+	Extension string
+	// layout name without extension, optional
+	Layout string
+	// left and right action delimiters, defaults to {{ and }}
+	Delims [2]string
+	// template funcs
+	Funcs map[string]interface{}
+	// reload the templates on each render
+	Reload bool
+	// print the parsed templates
+	Debug bool
+}
+
+// NewWithConfig returns a HTML render engine for Fiber configured by cfg,
+// or an error if cfg is invalid: the extension must start with a dot, the
+// views folder must exist and the layout must be found in it.
+func NewWithConfig(cfg Config) (*Engine, error) {
+	if cfg.Extension == "" {
+		cfg.Extension = ".html"
+	}
+	if !strings.HasPrefix(cfg.Extension, ".") {
+		return nil, fmt.Errorf("config: extension %q must start with a dot", cfg.Extension)
+	}
+	if (cfg.Delims[0] == "") != (cfg.Delims[1] == "") {
+		return nil, fmt.Errorf("config: delims must be both set or both empty")
+	}
+	opts := []Option{
+		WithExtension(cfg.Extension),
+		WithLayout(cfg.Layout),
+		WithFuncs(cfg.Funcs),
+		WithReload(cfg.Reload),
+		WithDebug(cfg.Debug),
+	}
+	if cfg.Delims[0] != "" {
+		opts = append(opts, WithDelims(cfg.Delims[0], cfg.Delims[1]))
+	}
+	if cfg.FS != nil {
+		opts = append(opts, WithFS(cfg.FS))
+	}
+	if cfg.Directory != "" {
+		opts = append(opts, WithDirectory(cfg.Directory))
+	}
+	engine := NewWithOptions(opts...)
+
+	src, err := engine.source()
+	if err != nil {
+		return nil, fmt.Errorf("config: directory %s: %v", engine.directory, err)
+	}
+	if info, err := fs.Stat(src, "."); err != nil {
+		return nil, fmt.Errorf("config: directory %s does not exist: %v", engine.directory, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("config: directory %s is not a directory", engine.directory)
+	}
+	if cfg.Layout != "" {
+		if _, err := fs.Stat(src, cfg.Layout+cfg.Extension); err != nil {
+			return nil, fmt.Errorf("config: layout %s not found in %s: %v", cfg.Layout, engine.directory, err)
+		}
+	}
+	return engine, nil
+}
@@ -0,0 +1,46 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_NewWithConfig(t *testing.T) {
+	engine, err := NewWithConfig(Config{
+		Directory: "./views",
+		Layout:    "layouts/main",
+		Funcs: map[string]interface{}{
+			"isAdmin": func(user string) bool {
+				return user == "admin"
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("config: %v\n", err)
+	}
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", map[string]interface{}{
+		"Title": "Hello, World!",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	for _, cfg := range []Config{
+		{Directory: "./views", Extension: "html"},
+		{Directory: "./missing"},
+		{Directory: "./views/home.html"},
+		{Directory: "./views", Layout: "layouts/missing"},
+		{Directory: "./views", Delims: [2]string{"[[", ""}},
+		{FS: fstest.MapFS{"index.html": {}}, Layout: "main"},
+	} {
+		if _, err := NewWithConfig(cfg); err == nil {
+			t.Fatalf("Expected error for %+v\n", cfg)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"reflect"
+	"sync"
+)
+
+// contextType is the type of the first argument of context funcs
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// AddContextFunc adds the function to the template's function map, fn takes
+// the render context as first argument which templates omit, e.g.
+// func(ctx context.Context, key string) string is called as {{t "key"}}.
+// Templates are executed from pooled copies when context funcs exist, so
+// each func sees the context of its own render.
+func (e *Engine) AddContextFunc(name string, fn interface{}) *Engine {
+	e.mutex.Lock()
+	if e.ctxfuncs == nil {
+		e.ctxfuncs = make(map[string]interface{})
+	}
+	e.ctxfuncs[name] = fn
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// renderState is the render context seen by the funcs of a pooled copy
+type renderState struct {
+	ctx context.Context
+}
+
+// pooledTemplate is a copy of a parsed template with funcs bound to state
+type pooledTemplate struct {
+	tmpl  *template.Template
+	state *renderState
+}
+
+// templatePool holds the copies of a parsed template along with the
+// context funcs they bind, merged templates keep the funcs of their engine
+type templatePool struct {
+	sync.Pool
+	funcs map[string]interface{}
+}
+
+// bindContextFuncs checks the context funcs and returns funcmap extended
+// with placeholders for them along with the funcs bound by the pools, the
+// parsed templates are never executed.
+func (e *Engine) bindContextFuncs(funcmap map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	ctxfuncs := make(map[string]interface{}, len(e.ctxfuncs)+1)
+	for name, fn := range e.ctxfuncs {
+		t := reflect.TypeOf(fn)
+		if t == nil || t.Kind() != reflect.Func || t.NumIn() == 0 || t.In(0) != contextType {
+			return nil, nil, fmt.Errorf("funcs: %s must be a func taking a context.Context first", name)
+		}
+		ctxfuncs[name] = fn
+	}
+	if e.profile {
+		ctxfuncs = profileFuncs(funcmap, ctxfuncs)
+	}
+	if e.traceComments {
+		ctxfuncs[traceFunc] = e.traceComment
+	}
+	if e.audit {
+		ctxfuncs[auditFunc] = auditRecord
+	}
+	if e.wrappers != "" {
+		ctxfuncs[wrappingFunc] = wrapping
+		ctxfuncs[wrapFunc] = wrap
+		ctxfuncs["wrapped"] = wrapped
+	}
+	if len(ctxfuncs) == 0 {
+		return funcmap, nil, nil
+	}
+	result := make(map[string]interface{}, len(funcmap)+len(ctxfuncs))
+	for name, fn := range funcmap {
+		result[name] = fn
+	}
+	for name, fn := range bindFuncs(ctxfuncs, &renderState{}) {
+		result[name] = fn
+	}
+	return result, ctxfuncs, nil
+}
+
+// acquire returns a pooled copy of tmpl, making one if the pool is empty.
+func (e *Engine) acquire(pool *templatePool, tmpl *template.Template) (*pooledTemplate, error) {
+	if p, ok := pool.Get().(*pooledTemplate); ok {
+		return p, nil
+	}
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	state := &renderState{}
+	clone.Funcs(bindFuncs(pool.funcs, state))
+	return &pooledTemplate{tmpl: clone, state: state}, nil
+}
+
+// bindFuncs returns the context funcs with the context argument taken from state.
+func bindFuncs(ctxfuncs map[string]interface{}, state *renderState) template.FuncMap {
+	funcs := make(template.FuncMap, len(ctxfuncs))
+	for name, fn := range ctxfuncs {
+		funcs[name] = bindContext(fn, state)
+	}
+	return funcs
+}
+
+// bindContext returns fn without its context argument.
+func bindContext(fn interface{}, state *renderState) interface{} {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	in := make([]reflect.Type, t.NumIn()-1)
+	for i := range in {
+		in[i] = t.In(i + 1)
+	}
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return reflect.MakeFunc(reflect.FuncOf(in, out, t.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+		ctx := state.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		args = append([]reflect.Value{reflect.ValueOf(&ctx).Elem()}, args...)
+		if t.IsVariadic() {
+			return v.CallSlice(args)
+		}
+		return v.Call(args)
+	}).Interface()
+}
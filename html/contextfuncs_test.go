@@ -0,0 +1,48 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type userKey struct{}
+
+func Test_AddContextFunc(t *testing.T) {
+	engine := New("./testdata/contextfuncs", ".html")
+	engine.AddContextFunc("userName", func(ctx context.Context) string {
+		name, _ := ctx.Value(userKey{}).(string)
+		return name
+	})
+	engine.AddContextFunc("join", func(ctx context.Context, sep string, parts ...string) string {
+		return strings.Join(parts, sep)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var buf bytes.Buffer
+			ctx := context.WithValue(context.Background(), userKey{}, fmt.Sprintf("user%d", i))
+			if err := engine.RenderContext(ctx, &buf, "user", nil); err != nil {
+				t.Errorf("render: %v\n", err)
+				return
+			}
+			expect := fmt.Sprintf(`<p>user%d a-b</p>`, i)
+			if result := trim(buf.String()); expect != result {
+				t.Errorf("Expected:\n%s\nResult:\n%s\n", expect, result)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	engine = New("./testdata/contextfuncs", ".html")
+	engine.AddContextFunc("userName", func() string { return "" })
+	if err := engine.Load(); err == nil {
+		t.Fatalf("Expected error for func without context\n")
+	}
+}
@@ -0,0 +1,183 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+)
+
+var (
+	linkTag      = regexp.MustCompile(`(?i)<link\b[^>]*>`)
+	headEnd      = regexp.MustCompile(`(?i)</head\s*>`)
+	relAttr      = regexp.MustCompile(`(?i)\srel\s*=\s*"?stylesheet"?`)
+	hrefAttr     = regexp.MustCompile(`(?i)\shref\s*=\s*"([^"]*)"`)
+	combinators  = regexp.MustCompile(`\s*[>+~]\s*|\s+`)
+	pseudoOrAttr = regexp.MustCompile(`::?[a-zA-Z-]+(\([^)]*\))?|\[[^\]]*\]`)
+)
+
+// criticalRule is a rule of the style sheet inlined by CriticalCSS
+type criticalRule struct {
+	// selectors or at-rule
+	prelude string
+	body    string
+	// rightmost compound of each selector, matched against the elements
+	keys []*cssRule
+	// rules of @media and @supports blocks
+	nested []criticalRule
+	// at-rules kept as is, such as @font-face
+	always bool
+}
+
+// CriticalCSS returns a processor inlining the rules of css that select
+// elements of the page into a <style> block at the end of its <head>,
+// and deferring the <link rel="stylesheet"> of href, which holds the full
+// style sheet, so the first paint does not wait for it. Rules are matched
+// on the rightmost part of their selectors, the inlined CSS may hold more
+// rules than the page needs but none it uses is left out. Empty href
+// defers nothing.
+func CriticalCSS(css, href string) PostProcessor {
+	rules := parseCritical(cssComment.ReplaceAllString(css, ""))
+	return func(ctx context.Context, name string, html []byte) ([]byte, error) {
+		head := headEnd.FindIndex(html)
+		if head == nil {
+			return html, nil
+		}
+		elements := pageElements(html)
+		var critical strings.Builder
+		writeCritical(&critical, rules, elements)
+		var out bytes.Buffer
+		out.Grow(len(html) + critical.Len() + 200)
+		out.Write(html[:head[0]])
+		if critical.Len() > 0 {
+			out.WriteString("<style data-critical>")
+			out.WriteString(critical.String())
+			out.WriteString("</style>")
+		}
+		out.Write(html[head[0]:])
+		result := out.Bytes()
+		if href == "" {
+			return result, nil
+		}
+		return linkTag.ReplaceAllFunc(result, func(tag []byte) []byte {
+			m := hrefAttr.FindSubmatch(tag)
+			if m == nil || string(m[1]) != href || !relAttr.Match(tag) {
+				return tag
+			}
+			var b bytes.Buffer
+			b.Write(relAttr.ReplaceAll(tag, []byte(` rel="preload" as="style" onload="this.onload=null;this.rel='stylesheet'"`)))
+			b.WriteString("<noscript>")
+			b.Write(tag)
+			b.WriteString("</noscript>")
+			return b.Bytes()
+		}), nil
+	}
+}
+
+// pageElement is the tag, id and classes of an element of the page
+type pageElement struct {
+	tag     string
+	id      string
+	classes map[string]bool
+}
+
+// pageElements returns the elements of html.
+func pageElements(html []byte) []pageElement {
+	var elements []pageElement
+	for _, m := range startTag.FindAllSubmatch(html, -1) {
+		el := pageElement{tag: string(m[1]), classes: make(map[string]bool)}
+		if idm := idAttr.FindSubmatch(m[2]); idm != nil {
+			el.id = string(idm[1])
+		}
+		if cm := classAttr.FindSubmatch(m[2]); cm != nil {
+			for _, class := range strings.Fields(string(cm[1])) {
+				el.classes[class] = true
+			}
+		}
+		elements = append(elements, el)
+	}
+	return elements
+}
+
+// parseCritical parses the rules of css.
+func parseCritical(css string) []criticalRule {
+	var rules []criticalRule
+	for len(css) > 0 {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+		prelude := strings.TrimSpace(css[:open])
+		// Match the braces of nested blocks such as @media
+		depth, end := 0, -1
+		for i := open; i < len(css); i++ {
+			if css[i] == '{' {
+				depth++
+			} else if css[i] == '}' {
+				if depth--; depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			break
+		}
+		// Statements such as @import end before the block
+		if semi := strings.LastIndexByte(prelude, ';'); semi >= 0 {
+			prelude = strings.TrimSpace(prelude[semi+1:])
+		}
+		rule := criticalRule{prelude: prelude, body: strings.TrimSpace(css[open+1 : end])}
+		switch {
+		case strings.HasPrefix(prelude, "@media"), strings.HasPrefix(prelude, "@supports"):
+			rule.nested = parseCritical(rule.body)
+		case strings.HasPrefix(prelude, "@font-face"):
+			rule.always = true
+		case strings.HasPrefix(prelude, "@"):
+			// Keyframes and the like are left to the full style sheet
+			rule.prelude = ""
+		default:
+			for _, sel := range strings.Split(prelude, ",") {
+				parts := combinators.Split(strings.TrimSpace(sel), -1)
+				key := pseudoOrAttr.ReplaceAllString(parts[len(parts)-1], "")
+				rule.keys = append(rule.keys, newCSSRule(strings.TrimPrefix(key, "*"), "", 0))
+			}
+		}
+		if rule.prelude != "" {
+			rules = append(rules, rule)
+		}
+		css = css[end+1:]
+	}
+	return rules
+}
+
+// writeCritical writes the rules selecting elements.
+func writeCritical(b *strings.Builder, rules []criticalRule, elements []pageElement) {
+	for _, rule := range rules {
+		switch {
+		case rule.always:
+			b.WriteString(rule.prelude + "{" + rule.body + "}")
+		case rule.nested != nil:
+			var nested strings.Builder
+			writeCritical(&nested, rule.nested, elements)
+			if nested.Len() > 0 {
+				b.WriteString(rule.prelude + "{" + nested.String() + "}")
+			}
+		case rule.selects(elements):
+			b.WriteString(rule.prelude + "{" + rule.body + "}")
+		}
+	}
+}
+
+// selects reports whether a selector of the rule may select one of the
+// elements.
+func (r *criticalRule) selects(elements []pageElement) bool {
+	for _, key := range r.keys {
+		for _, el := range elements {
+			if key.matches(el.tag, el.id, el.classes) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package html
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func Test_CriticalCSS(t *testing.T) {
+	css, err := os.ReadFile("./testdata/critical/app.css")
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	engine := New("./testdata/critical", ".html")
+	engine.PostProcess(CriticalCSS(string(css), "/app.css"))
+	var buf bytes.Buffer
+	if err = engine.Render(&buf, "page", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<html><head><link rel="preload" as="style" onload="this.onload=null;this.rel='stylesheet'" href="/app.css"><noscript><link rel="stylesheet" href="/app.css"></noscript>` +
+		`<style data-critical>body{margin: 0}.menu >a:hover{color: red}@media (max-width: 600px){.menu{display: none}}@font-face{font-family: Brand; src: url(/brand.woff2)}</style></head>` +
+		`<body><nav class="menu"><a href="/">Home</a></nav></body></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
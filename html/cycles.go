@@ -0,0 +1,72 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// checkCycles returns an error if a template of tmpl includes itself,
+// directly or through other templates, outside of an if, range or with
+// action. Such includes recurse until the stack overflows, guarded ones
+// such as menus rendering their children are left alone.
+func checkCycles(tmpl *template.Template, file string) error {
+	includes := make(map[string][]string)
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && t.Tree.Root != nil {
+			includes[t.Name()] = unconditionalIncludes(t.Tree.Root, nil)
+		}
+	}
+	names := make([]string, 0, len(includes))
+	for name := range includes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// 1 while on the current chain, 2 once every include is checked
+	state := make(map[string]int)
+	var chain []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			for i, n := range chain {
+				if n == name {
+					return fmt.Errorf("views: %s includes itself: %s -> %s", file, strings.Join(chain[i:], " -> "), name)
+				}
+			}
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		chain = append(chain, name)
+		for _, include := range includes[name] {
+			if err := visit(include); err != nil {
+				return err
+			}
+		}
+		chain = chain[:len(chain)-1]
+		state[name] = 2
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unconditionalIncludes appends the templates list always includes.
+func unconditionalIncludes(list *parse.ListNode, names []string) []string {
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TemplateNode:
+			names = append(names, n.Name)
+		case *parse.ListNode:
+			names = unconditionalIncludes(n, names)
+		}
+	}
+	return names
+}
@@ -0,0 +1,31 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+type menuItem struct {
+	Name     string
+	Children []menuItem
+}
+
+func Test_Cycles(t *testing.T) {
+	engine := New("./testdata/cycles/loop", ".html")
+	err := engine.Load()
+	expect := `views: page.html includes itself: a -> b -> a`
+	if err == nil || err.Error() != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expect, err)
+	}
+
+	// Recursion guarded by range is fine
+	engine = New("./testdata/cycles/guarded", ".html")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "menu", []menuItem{{Name: "a", Children: []menuItem{{Name: "b"}}}}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<ul><li>a<ul><li>b<ul></ul></li></ul></li></ul>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
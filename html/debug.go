@@ -0,0 +1,40 @@
+package html
+
+import (
+	"sort"
+)
+
+// DebugInfo describes the engine state reported by DebugHandler
+type DebugInfo struct {
+	Directory string   `json:"directory"`
+	Extension string   `json:"extension"`
+	Layout    string   `json:"layout"`
+	Delims    []string `json:"delims"`
+	Reload    bool     `json:"reload"`
+	Templates []string `json:"templates"`
+	Funcs     []string `json:"funcs"`
+	Unused    []string `json:"unused"`
+	Stats     Stats    `json:"stats"`
+}
+
+// DebugInfo returns the engine configuration, templates and counters.
+func (e *Engine) DebugInfo() DebugInfo {
+	e.mutex.RLock()
+	funcs := make([]string, 0, len(e.funcmap))
+	for name := range e.funcmap {
+		funcs = append(funcs, name)
+	}
+	e.mutex.RUnlock()
+	sort.Strings(funcs)
+	return DebugInfo{
+		Directory: e.directory,
+		Extension: e.extension,
+		Layout:    e.layout,
+		Delims:    []string{e.left, e.right},
+		Reload:    e.reload,
+		Templates: e.Names(),
+		Funcs:     funcs,
+		Unused:    e.Unused(),
+		Stats:     e.Stats(),
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !noos
+
+package html
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// DebugHandler returns a handler reporting DebugInfo as JSON. Nothing is
+// exposed unless the handler is mounted, which should happen behind
+// authentication since it reveals the application's views.
+func (e *Engine) DebugHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(e.DebugInfo())
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !noos
+
+package html
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_DebugHandler(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+
+	app := fiber.New(fiber.Config{
+		Views: engine,
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.Render("index", fiber.Map{})
+	})
+	app.Get("/debug/views", engine.DebugHandler())
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	resp, err := app.Test(httptest.NewRequest("GET", "/debug/views", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	var info DebugInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatalf("decode: %v\n", err)
+	}
+	if info.Layout != "layouts/main" || len(info.Funcs) != 1 || info.Funcs[0] != "isAdmin" {
+		t.Fatalf("Unexpected config: %+v\n", info)
+	}
+	if info.Stats.Templates != len(info.Templates) || info.Stats.Renders != 1 || info.Stats.Loads != 1 || info.Stats.LastLoad.IsZero() {
+		t.Fatalf("Unexpected stats: %+v\n", info.Stats)
+	}
+}
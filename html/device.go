@@ -0,0 +1,55 @@
+package html
+
+import (
+	"context"
+	"html/template"
+)
+
+// device classes returned by DeviceClass
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+)
+
+type deviceKey struct{}
+
+// WithDevice returns a copy of ctx rendering for the device class, e.g.
+// mobile: index renders index.mobile.html if it exists, in the layout
+// DeviceLayouts maps the class to.
+func WithDevice(ctx context.Context, class string) context.Context {
+	return context.WithValue(ctx, deviceKey{}, class)
+}
+
+// Device returns the device class selected by ctx.
+func Device(ctx context.Context) string {
+	class, _ := ctx.Value(deviceKey{}).(string)
+	return class
+}
+
+// DeviceLayouts sets the layout of each device class, e.g. a mobile
+// layout with its own navigation. The layouts are added to Layouts, the
+// other classes use the default layout.
+func (e *Engine) DeviceLayouts(layouts map[string]string) *Engine {
+	e.deviceLayouts = layouts
+	names := make([]string, 0, len(layouts))
+	for _, layout := range layouts {
+		names = append(names, layout)
+	}
+	return e.Layouts(names...)
+}
+
+// deviceLayout returns the layout of the device class of ctx, empty for
+// the default layout.
+func (e *Engine) deviceLayout(ctx context.Context) string {
+	return e.deviceLayouts[Device(ctx)]
+}
+
+// deviceVariant returns the name of the variant of name for the device
+// class of ctx if it exists, or name itself.
+func deviceVariant(ctx context.Context, templates map[string]*template.Template, name string) string {
+	if class := Device(ctx); class != "" && templates[name+"."+class] != nil {
+		return name + "." + class
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeviceClass derives the device class of a request from the
+// Sec-CH-UA-Mobile client hint, or the User-Agent without it.
+func DeviceClass(c *fiber.Ctx) string {
+	if hint := c.Get("Sec-CH-UA-Mobile"); hint == "?1" {
+		return DeviceMobile
+	}
+	ua := c.Get(fiber.HeaderUserAgent)
+	switch {
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet") || strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone"):
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
+
+// DeviceSelector sets the func picking the device class of a request for
+// Respond and DeviceMiddleware, DeviceClass by default.
+func (e *Engine) DeviceSelector(fn func(c *fiber.Ctx) string) *Engine {
+	e.deviceSelector = fn
+	return e
+}
+
+// DeviceMiddleware selects the device class of each request in its user
+// context for the adapters other than Respond, and asks browsers for the
+// mobile client hint.
+func (e *Engine) DeviceMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Accept-CH", "Sec-CH-UA-Mobile")
+		c.SetUserContext(e.withDevice(c.UserContext(), c))
+		return c.Next()
+	}
+}
+
+// withDevice returns ctx with the device class of c, if the engine
+// selects devices and ctx has none, the response varies on the headers
+// the class is derived from.
+func (e *Engine) withDevice(ctx context.Context, c *fiber.Ctx) context.Context {
+	if Device(ctx) != "" || e.deviceSelector == nil && len(e.deviceLayouts) == 0 {
+		return ctx
+	}
+	c.Vary("Sec-CH-UA-Mobile", fiber.HeaderUserAgent)
+	selector := e.deviceSelector
+	if selector == nil {
+		selector = DeviceClass
+	}
+	return WithDevice(ctx, selector(c))
+}
@@ -0,0 +1,44 @@
+//go:build !noos
+
+package html
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_DeviceLayouts(t *testing.T) {
+	engine := New("./testdata/device", ".html")
+	engine.Layout("layouts/main").DeviceLayouts(map[string]string{DeviceMobile: "layouts/mobile"})
+	app := fiber.New()
+	app.Get("/:page", func(c *fiber.Ctx) error {
+		return engine.Respond(c, c.Params("page"), nil)
+	})
+	tests := []struct {
+		path    string
+		headers map[string]string
+		expect  string
+	}{
+		{"/index", nil, `<html><p>index</p></html>`},
+		{"/index", map[string]string{"Sec-CH-UA-Mobile": "?1"}, `<html class="mobile"><p>compact index</p></html>`},
+		{"/about", map[string]string{"User-Agent": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148"}, `<html class="mobile"><p>about</p></html>`},
+		{"/about", map[string]string{"User-Agent": "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)"}, `<html><p>about</p></html>`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if result := trim(string(body)); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+}
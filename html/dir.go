@@ -0,0 +1,104 @@
+package html
+
+import (
+	"context"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlTag      = regexp.MustCompile(`(?i)<html\b[^>]*>`)
+	langDirAttrs = regexp.MustCompile(`(?i)\s(?:xml:)?(?:lang|dir)\s*=\s*(?:"[^"]*"|'[^']*'|[^\s>]+)`)
+)
+
+// rtlLanguages are the languages written right to left
+var rtlLanguages = map[string]bool{
+	"ar": true, "arc": true, "ckb": true, "dv": true, "fa": true, "he": true,
+	"iw": true, "ks": true, "ps": true, "sd": true, "ug": true, "ur": true, "yi": true,
+}
+
+// rtlScripts are the scripts written right to left
+var rtlScripts = map[string]bool{
+	"arab": true, "hebr": true, "nkoo": true, "rohg": true, "syrc": true, "thaa": true,
+}
+
+// Dir returns the text direction of locale, "rtl" or "ltr".
+func Dir(locale string) string {
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+	// A script subtag overrides the language, e.g. pa-Arab
+	for _, part := range parts[1:] {
+		if len(part) == 4 {
+			if rtlScripts[part] {
+				return "rtl"
+			}
+			return "ltr"
+		}
+	}
+	if rtlLanguages[parts[0]] {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// DirFuncs registers direction helpers driven by the render locale:
+// {{dir}} returns rtl or ltr, {{alignStart}} and {{alignEnd}} return left
+// or right, and {{dirClass "ml-4" "mr-4"}} returns the first class in
+// left to right locales and the second one otherwise.
+func (e *Engine) DirFuncs() *Engine {
+	e.AddContextFunc("dir", e.dir)
+	e.AddContextFunc("alignStart", func(ctx context.Context) string {
+		if e.dir(ctx) == "rtl" {
+			return "right"
+		}
+		return "left"
+	})
+	e.AddContextFunc("alignEnd", func(ctx context.Context) string {
+		if e.dir(ctx) == "rtl" {
+			return "left"
+		}
+		return "right"
+	})
+	e.AddContextFunc("dirClass", func(ctx context.Context, ltr, rtl string) string {
+		if e.dir(ctx) == "rtl" {
+			return rtl
+		}
+		return ltr
+	})
+	return e
+}
+
+// dir returns the text direction of the render locale.
+func (e *Engine) dir(ctx context.Context) string {
+	locale := Locale(ctx)
+	if locale == "" {
+		locale = e.published().defaultLocale
+	}
+	return Dir(locale)
+}
+
+// LangAttributes adds a processor setting the lang and dir attributes of
+// the <html> tag of the pages to the render locale, replacing the ones
+// written in the layout. Pages are left as is when no locale is selected.
+func (e *Engine) LangAttributes() *Engine {
+	return e.PostProcess(e.langAttributes)
+}
+
+// langAttributes sets the lang and dir attributes of the <html> tag.
+func (e *Engine) langAttributes(ctx context.Context, name string, html []byte) ([]byte, error) {
+	locale := Locale(ctx)
+	if locale == "" {
+		locale = e.published().defaultLocale
+	}
+	tag := htmlTag.FindIndex(html)
+	if locale == "" || tag == nil {
+		return html, nil
+	}
+	end := tag[1] - 1
+	attrs := langDirAttrs.ReplaceAll(html[tag[0]+len("<html"):end], nil)
+	out := make([]byte, 0, len(html)+len(locale)+20)
+	out = append(out, html[:tag[0]+len("<html")]...)
+	out = append(out, ` lang="`+template.HTMLEscapeString(locale)+`" dir="`+Dir(locale)+`"`...)
+	out = append(out, attrs...)
+	return append(out, html[end:]...), nil
+}
@@ -0,0 +1,73 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_Dir(t *testing.T) {
+	for locale, expect := range map[string]string{
+		"":        "ltr",
+		"en-US":   "ltr",
+		"ar":      "rtl",
+		"he-IL":   "rtl",
+		"fa_IR":   "rtl",
+		"pa-Arab": "rtl",
+		"ku-Latn": "ltr",
+	} {
+		if result := Dir(locale); expect != result {
+			t.Fatalf("Locale %s\nExpected:\n%s\nResult:\n%s\n", locale, expect, result)
+		}
+	}
+}
+
+func Test_DirFuncs(t *testing.T) {
+	engine := New("./testdata/dir", ".html")
+	engine.DirFuncs()
+
+	var buf bytes.Buffer
+	if err := engine.RenderContext(WithLocale(context.Background(), "ar-EG"), &buf, "box", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<div dir="rtl" style="text-align: right" class="mr-4"></div>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	buf.Reset()
+	if err := engine.Render(&buf, "box", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<div dir="ltr" style="text-align: left" class="ml-4"></div>`
+	result = trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_LangAttributes(t *testing.T) {
+	engine := New("./testdata/dir", ".html")
+	engine.DirFuncs().LangAttributes()
+
+	var buf bytes.Buffer
+	if err := engine.RenderContext(WithLocale(context.Background(), "he-IL"), &buf, "document", "Shalom"); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<html lang="he-IL" dir="rtl" class="no-js"><body>Shalom</body></html>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	buf.Reset()
+	if err := engine.Render(&buf, "document", "Hello"); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<html lang="en" class="no-js"><body>Hello</body></html>`
+	result = trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Dispatcher routes renders to the engine mounted on the longest prefix of
+// the template name, e.g. "emails/" to a text engine and "admin/" to an
+// engine with another layout, so one fiber.App can use several engines.
+type Dispatcher struct {
+	// mounts sorted by decreasing prefix length
+	mounts []mount
+	// engine of the names matching no prefix, may be nil
+	fallback fiber.Views
+}
+
+type mount struct {
+	prefix string
+	views  fiber.Views
+}
+
+// Dispatcher implements fiber.Views and Renderer
+var _ Renderer = (*Dispatcher)(nil)
+
+// NewDispatcher returns a dispatcher rendering the names matching no
+// mounted prefix with fallback, nil makes them fail.
+func NewDispatcher(fallback fiber.Views) *Dispatcher {
+	return &Dispatcher{fallback: fallback}
+}
+
+// Mount routes the names starting with prefix to views, which sees them
+// without the prefix: "emails/welcome" renders "welcome" of views.
+func (d *Dispatcher) Mount(prefix string, views fiber.Views) *Dispatcher {
+	d.mounts = append(d.mounts, mount{prefix: prefix, views: views})
+	sort.SliceStable(d.mounts, func(i, j int) bool {
+		return len(d.mounts[i].prefix) > len(d.mounts[j].prefix)
+	})
+	return d
+}
+
+// Load loads the mounted engines and the fallback.
+func (d *Dispatcher) Load() error {
+	for _, m := range d.mounts {
+		if err := m.views.Load(); err != nil {
+			return fmt.Errorf("views %s: %v", m.prefix, err)
+		}
+	}
+	if d.fallback != nil {
+		return d.fallback.Load()
+	}
+	return nil
+}
+
+// Render executes the named template with the engine of its prefix.
+func (d *Dispatcher) Render(out io.Writer, name string, binding interface{}, layout ...string) error {
+	return d.RenderContext(context.Background(), out, name, binding, layout...)
+}
+
+// RenderContext executes the named template with the engine of its
+// prefix, the context is dropped for engines without RenderContext.
+func (d *Dispatcher) RenderContext(ctx context.Context, out io.Writer, name string, binding interface{}, layout ...string) error {
+	views, name, err := d.route(name)
+	if err != nil {
+		return err
+	}
+	if r, ok := views.(Renderer); ok {
+		return r.RenderContext(ctx, out, name, binding, layout...)
+	}
+	return views.Render(out, name, binding, layout...)
+}
+
+// RenderPartialContext executes the named template without the layout,
+// which requires the engine of its prefix to implement Renderer.
+func (d *Dispatcher) RenderPartialContext(ctx context.Context, out io.Writer, name string, binding interface{}) error {
+	views, name, err := d.route(name)
+	if err != nil {
+		return err
+	}
+	r, ok := views.(Renderer)
+	if !ok {
+		return fmt.Errorf("render: %T cannot render partials", views)
+	}
+	return r.RenderPartialContext(ctx, out, name, binding)
+}
+
+// route returns the engine of name and the name it knows the template by.
+func (d *Dispatcher) route(name string) (fiber.Views, string, error) {
+	for _, m := range d.mounts {
+		if strings.HasPrefix(name, m.prefix) {
+			return m.views, strings.TrimPrefix(name, m.prefix), nil
+		}
+	}
+	if d.fallback == nil {
+		return nil, "", fmt.Errorf("render: no views mounted for template %s", name)
+	}
+	return d.fallback, name, nil
+}
@@ -0,0 +1,46 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Dispatcher(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	views := NewDispatcher(engine).Mount("emails/", New("./testdata/dispatch", ".html"))
+
+	app := fiber.New(fiber.Config{Views: views})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.Render("index", fiber.Map{"Title": "Hello, World!"})
+	})
+	app.Get("/email", func(c *fiber.Ctx) error {
+		return c.Render("emails/welcome", fiber.Map{"Name": "Ann"})
+	})
+	tests := map[string]string{
+		"/":      `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`,
+		"/email": `Welcome Ann`,
+	}
+	for path, expect := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if result := trim(string(body)); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+
+	if err := NewDispatcher(nil).Render(ioutil.Discard, "index", nil); err == nil {
+		t.Fatalf("Expected error without fallback\n")
+	}
+}
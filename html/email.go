@@ -0,0 +1,183 @@
+package html
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Email flags the templates as emails, their output gets the rules of its
+// <style> blocks inlined into style attributes, see InlineCSS.
+func (e *Engine) Email(names ...string) *Engine {
+	e.mutex.Lock()
+	if e.emails == nil {
+		e.emails = make(map[string]bool)
+	}
+	for _, name := range names {
+		e.emails[name] = true
+	}
+	e.mutex.Unlock()
+	return e
+}
+
+var (
+	styleBlock  = regexp.MustCompile(`(?is)<style[^>]*>(.*?)</style>`)
+	cssComment  = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	startTag    = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9-]*)(\s[^<>]*?)?(/?)>`)
+	simpleCSS   = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*)?((?:[#.][a-zA-Z0-9_-]+)*)$`)
+	classAttr   = regexp.MustCompile(`(?i)\sclass\s*=\s*"([^"]*)"`)
+	idAttr      = regexp.MustCompile(`(?i)\sid\s*=\s*"([^"]*)"`)
+	styleAttr   = regexp.MustCompile(`(?i)\sstyle\s*=\s*"([^"]*)"`)
+	cssSelector = regexp.MustCompile(`[#.]?[a-zA-Z0-9_-]+`)
+)
+
+// cssRule is an inlinable rule of a style block
+type cssRule struct {
+	tag         string
+	id          string
+	classes     []string
+	specificity int
+	order       int
+	decls       string
+}
+
+// matches reports whether the rule applies to the element.
+func (r *cssRule) matches(tag, id string, classes map[string]bool) bool {
+	if r.tag != "" && !strings.EqualFold(r.tag, tag) {
+		return false
+	}
+	if r.id != "" && r.id != id {
+		return false
+	}
+	for _, class := range r.classes {
+		if !classes[class] {
+			return false
+		}
+	}
+	return true
+}
+
+// InlineCSS moves the rules of the <style> blocks of html into the style
+// attributes of the elements they select, since most mail clients ignore
+// style blocks. Only type, class and id selectors are inlined, other
+// rules and at-rules such as @media stay in the style block. Existing
+// style attributes win over inlined rules.
+func InlineCSS(html string) string {
+	var rules []*cssRule
+	html = styleBlock.ReplaceAllStringFunc(html, func(block string) string {
+		css := cssComment.ReplaceAllString(styleBlock.FindStringSubmatch(block)[1], "")
+		kept := parseCSS(css, &rules)
+		if strings.TrimSpace(kept) == "" {
+			return ""
+		}
+		return "<style>" + kept + "</style>"
+	})
+	if len(rules) == 0 {
+		return html
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].specificity != rules[j].specificity {
+			return rules[i].specificity < rules[j].specificity
+		}
+		return rules[i].order < rules[j].order
+	})
+	return startTag.ReplaceAllStringFunc(html, func(tag string) string {
+		m := startTag.FindStringSubmatch(tag)
+		name, attrs := m[1], m[2]
+		var id string
+		if idm := idAttr.FindStringSubmatch(attrs); idm != nil {
+			id = idm[1]
+		}
+		classes := make(map[string]bool)
+		if cm := classAttr.FindStringSubmatch(attrs); cm != nil {
+			for _, class := range strings.Fields(cm[1]) {
+				classes[class] = true
+			}
+		}
+		var decls []string
+		for _, rule := range rules {
+			if rule.matches(name, id, classes) {
+				decls = append(decls, rule.decls)
+			}
+		}
+		if len(decls) == 0 {
+			return tag
+		}
+		if sm := styleAttr.FindStringSubmatch(attrs); sm != nil {
+			decls = append(decls, strings.TrimSuffix(strings.TrimSpace(sm[1]), ";"))
+			attrs = styleAttr.ReplaceAllString(attrs, "")
+		}
+		style := strings.ReplaceAll(strings.Join(decls, "; "), `"`, "'")
+		return "<" + name + attrs + ` style="` + style + `"` + m[3] + ">"
+	})
+}
+
+// parseCSS appends the inlinable rules of css to rules and returns the
+// rest of the style sheet.
+func parseCSS(css string, rules *[]*cssRule) string {
+	var kept strings.Builder
+	for len(css) > 0 {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+		selector := strings.TrimSpace(css[:open])
+		// Match the braces of nested blocks such as @media
+		depth, end := 0, -1
+		for i := open; i < len(css); i++ {
+			if css[i] == '{' {
+				depth++
+			} else if css[i] == '}' {
+				if depth--; depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		if end < 0 {
+			kept.WriteString(css)
+			break
+		}
+		block := css[open+1 : end]
+		rest := css[end+1:]
+		if strings.HasPrefix(selector, "@") || !inlinable(selector) {
+			kept.WriteString(selector + " {" + block + "}")
+		} else {
+			decls := strings.TrimSuffix(strings.TrimSpace(block), ";")
+			for _, sel := range strings.Split(selector, ",") {
+				*rules = append(*rules, newCSSRule(strings.TrimSpace(sel), decls, len(*rules)))
+			}
+		}
+		css = rest
+	}
+	return kept.String()
+}
+
+// inlinable reports whether every selector of the list is simple.
+func inlinable(selectors string) bool {
+	for _, sel := range strings.Split(selectors, ",") {
+		if sel = strings.TrimSpace(sel); sel == "" || !simpleCSS.MatchString(sel) {
+			return false
+		}
+	}
+	return true
+}
+
+// newCSSRule parses a simple selector.
+func newCSSRule(selector, decls string, order int) *cssRule {
+	rule := &cssRule{decls: decls, order: order}
+	for _, part := range cssSelector.FindAllString(selector, -1) {
+		switch part[0] {
+		case '#':
+			rule.id = part[1:]
+			rule.specificity += 100
+		case '.':
+			rule.classes = append(rule.classes, part[1:])
+			rule.specificity += 10
+		default:
+			rule.tag = part
+			rule.specificity++
+		}
+	}
+	return rule
+}
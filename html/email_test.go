@@ -0,0 +1,29 @@
+package html
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_Email(t *testing.T) {
+	engine := New("./testdata/email", ".html")
+	engine.Email("welcome")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "welcome", map[string]interface{}{"Name": "John"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<html><head><style>td p { padding: 4px }@media (max-width: 600px) { p { font-size: 18px } }</style></head><body><p class="lead" style="color: #333; margin: 0">Hi John</p><a class="cta" href="/start" style="background: blue; font-family: 'Arial'; color: white">Start</a><p id="footer" style="color: #333; margin: 0; font-size: 12px">Bye</p></body></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Templates not flagged keep their style blocks
+	buf.Reset()
+	if err := engine.Render(&buf, "plain", map[string]interface{}{"Name": "John"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := buf.String(); !strings.Contains(result, "#footer") || strings.Contains(result, `style="color: #333`) {
+		t.Fatalf("Expected the style block to be left alone\nResult:\n%s\n", result)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build !noos
+
+package html
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// StatusLayout sets the layout of the error pages by status code, used by
+// ErrorHandler, e.g. a slim layout for 404 and one without dependencies
+// for 500, in case the default layout is what fails. Other codes use the
+// default layout. The layouts must be the default layout or added by
+// Layouts.
+func (e *Engine) StatusLayout(layouts map[int]string) *Engine {
+	e.statusLayouts = layouts
+	return e
+}
+
+// ErrorHandler returns a Fiber error handler rendering the template name
+// with the Status code, the Message of the error and the Error itself,
+// in the layout StatusLayout maps the code to. Messages of errors other
+// than *fiber.Error are not shown, they may leak internals. If the error
+// page fails to render the plain message is sent.
+func (e *Engine) ErrorHandler(name string) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		code := fiber.StatusInternalServerError
+		message := utils.StatusMessage(code)
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			code, message = fe.Code, fe.Message
+		}
+		binding := fiber.Map{"Status": code, "Message": message, "Error": err}
+		ctx := e.withURL(WithPath(e.WithSite(c.UserContext(), c.Hostname()), c.Path()), c)
+		var buf bytes.Buffer
+		if layout, ok := e.statusLayouts[code]; ok {
+			ctx = UseLayout(ctx, layout)
+		}
+		c.Status(code)
+		if rerr := e.RenderContext(ctx, &buf, name, binding); rerr != nil {
+			c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+			return c.SendString(message)
+		}
+		c.Type("html", "utf-8")
+		return c.Send(buf.Bytes())
+	}
+}
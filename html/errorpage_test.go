@@ -0,0 +1,72 @@
+//go:build !noos
+
+package html
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_ErrorHandler(t *testing.T) {
+	engine := New("./testdata/errorpage", ".html")
+	engine.AddFunc("broken", func() (string, error) {
+		return "", errors.New("broken nav")
+	})
+	engine.Layout("layouts/main").Layouts("layouts/slim", "layouts/bare")
+	engine.StatusLayout(map[int]string{
+		fiber.StatusNotFound:            "layouts/slim",
+		fiber.StatusInternalServerError: "layouts/bare",
+	})
+	app := fiber.New(fiber.Config{ErrorHandler: engine.ErrorHandler("error")})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return errors.New("database password in message")
+	})
+	app.Get("/teapot", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusTeapot, "Short and stout")
+	})
+	tests := []struct {
+		path   string
+		status int
+		expect string
+	}{
+		{"/missing", fiber.StatusNotFound, `<html class="slim"><h1>404 Cannot GET /missing</h1></html>`},
+		{"/fail", fiber.StatusInternalServerError, `<html class="bare"><h1>500 Internal Server Error</h1></html>`},
+		// The default layout fails, the plain message is sent
+		{"/teapot", fiber.StatusTeapot, `Short and stout`},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status {
+			t.Fatalf("Expected status %d, got %d\n", tt.status, resp.StatusCode)
+		}
+		if result := trim(string(body)); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+}
+
+func Test_RenderLayoutArgument(t *testing.T) {
+	engine := New("./testdata/errorpage", ".html")
+	engine.AddFunc("broken", func() string { return "" })
+	engine.Layout("layouts/main").Layouts("layouts/slim")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "error", fiber.Map{"Status": 200, "Message": "OK"}, "layouts/slim"); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<html class="slim"><h1>200 OK</h1></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if err := engine.Render(&buf, "error", nil, "layouts/other"); err == nil {
+		t.Fatalf("Expected missing layout error\n")
+	}
+}
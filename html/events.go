@@ -0,0 +1,21 @@
+package html
+
+import (
+	"context"
+	"log/slog"
+)
+
+// event emits a structured engine event to the logger.
+func (e *Engine) event(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := e.logger
+	if logger == nil {
+		if !e.debug {
+			return
+		}
+		logger = debugLogger
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,105 @@
+package html
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_Logger(t *testing.T) {
+	var log bytes.Buffer
+	engine := New("./views", ".html")
+	engine.Logger(slog.NewTextHandler(&log, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "home", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	engine.Render(&buf, "missing", map[string]interface{}{
+		"User":  struct{ Name, Password string }{"john", "hunter2"},
+		"Token": "abc",
+	})
+
+	result := log.String()
+	for _, expect := range []string{
+		`level=DEBUG msg="views: render started" template=home partial=false`,
+		`level=DEBUG msg="views: parsed template" template=errors/404`,
+		`level=INFO msg="views: loaded templates" directory=./views`,
+		`level=DEBUG msg="views: render finished" template=home`,
+		`level=ERROR msg="views: render failed" template=missing error="render: template missing does not exist" binding="map[Token:[REDACTED] User:map[Name:john Password:[REDACTED]]]"`,
+	} {
+		if !strings.Contains(result, expect) {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
+
+func Test_LoggerDuringReloads(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Logger(slog.NewTextHandler(io.Discard, nil))
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.Reload(true)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			if err := engine.Render(&buf, "home", nil); err != nil {
+				t.Errorf("render: %v\n", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_CacheEvictedEvents(t *testing.T) {
+	var log bytes.Buffer
+	engine := New("./testdata/cache", ".html")
+	engine.Logger(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	engine.FragmentCache(0)
+	engine.AddFunc("count", func() int { return 0 })
+	binding := map[string]interface{}{
+		"Products": []struct {
+			ID   int
+			Name string
+		}{{1, "Tea"}},
+	}
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "products", binding); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if err := engine.Invalidate("product:1"); err != nil {
+		t.Fatalf("invalidate: %v\n", err)
+	}
+	engine.SetDirectory("./testdata/cache")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	result := log.String()
+	for _, expect := range []string{
+		`msg="views: cache evicted" cache=fragment key=product:1 reason=invalidated`,
+		`msg="views: cache evicted" cache=fragment namespace=`,
+		`reason=replaced`,
+	} {
+		if !strings.Contains(result, expect) {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
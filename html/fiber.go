@@ -0,0 +1,98 @@
+//go:build !noos
+
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The Fiber adapters live in this file and the _fiber.go files, built
+// without the noos tag, which leaves Fiber out of the WASM builds.
+
+// Map is the map of the bindings merged by Merge, fiber.Map in the
+// builds with Fiber
+type Map = fiber.Map
+
+// deviceSelectorFunc picks the device class of a request
+type deviceSelectorFunc = func(c *fiber.Ctx) string
+
+// bindingMap returns the binding as a map with string keys, ok is false
+// if it is none.
+func bindingMap(binding interface{}) (m map[string]interface{}, ok bool) {
+	switch m := binding.(type) {
+	case map[string]interface{}:
+		return m, true
+	case fiber.Map:
+		return m, true
+	}
+	return nil, false
+}
+
+// ServerTiming if set to true Respond appends a Server-Timing entry with
+// the render duration, so browser tooling can attribute backend time
+// to template rendering.
+func (e *Engine) ServerTiming(enabled bool) *Engine {
+	e.serverTiming = enabled
+	return e
+}
+
+// Respond renders the template name as the HTML response of c. Unlike
+// c.Render the request context is passed to the engine, which carries
+// the parent span and the per-request values, along with the request path
+// compared by the active funcs, the site of the request host, the device
+// class if the engine selects devices, the print layout if asked for and
+// the request URL if the canonical func is used.
+// Responses rendered by the maintenance template have the 503 status.
+func (e *Engine) Respond(c *fiber.Ctx, name string, binding interface{}) error {
+	var buf bytes.Buffer
+	start := time.Now()
+	if e.maintenanceFor(name) != "" {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	// The response cache checks it against maintenance mode
+	c.Locals(respondKey{}, name)
+	err := e.RenderContext(e.requestContext(c), &buf, name, binding)
+	if e.serverTiming {
+		c.Append(fiber.HeaderServerTiming, serverTiming(name, time.Since(start)))
+	}
+	if err != nil {
+		return err
+	}
+	c.Type("html", "utf-8")
+	return c.Send(buf.Bytes())
+}
+
+// respondKey is the Fiber local of the template rendered by Respond
+type respondKey struct{}
+
+// requestContext returns the user context of c with the request path,
+// site, device class, print layout and URL of the request.
+func (e *Engine) requestContext(c *fiber.Ctx) context.Context {
+	ctx := e.withDevice(WithPath(e.WithSite(c.UserContext(), c.Hostname()), c.Path()), c)
+	return e.withURL(e.withPrint(ctx, c), c)
+}
+
+// serverTiming formats a Server-Timing entry for the template name, desc
+// is a quoted-string: quotes and backslashes are escaped, control
+// characters left out.
+func serverTiming(name string, d time.Duration) string {
+	var desc strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c == '"' || c == '\\':
+			desc.WriteByte('\\')
+			desc.WriteByte(c)
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			// Not allowed in a quoted-string
+		default:
+			desc.WriteByte(c)
+		}
+	}
+	return fmt.Sprintf(`tmpl;dur=%.3f;desc="%s"`, float64(d)/float64(time.Millisecond), desc.String())
+}
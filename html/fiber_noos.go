@@ -0,0 +1,18 @@
+//go:build noos
+
+package html
+
+// Map is the map of the bindings merged by Merge, fiber.Map in the
+// builds with Fiber
+type Map = map[string]interface{}
+
+// deviceSelectorFunc is never set without Fiber, DeviceLayouts apply to
+// the renders given a device class with WithDevice
+type deviceSelectorFunc = func()
+
+// bindingMap returns the binding as a map with string keys, ok is false
+// if it is none.
+func bindingMap(binding interface{}) (m map[string]interface{}, ok bool) {
+	m, ok = binding.(map[string]interface{})
+	return m, ok
+}
@@ -0,0 +1,54 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Respond(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.ServerTiming(true)
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return engine.Respond(c, "index", fiber.Map{
+			"Title": "Hello, World!",
+		})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+	result := trim(string(body))
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if resp.Header.Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Fatalf("Unexpected content type: %s\n", resp.Header.Get("Content-Type"))
+	}
+	timing := resp.Header.Get("Server-Timing")
+	if !regexp.MustCompile(`^tmpl;dur=\d+\.\d{3};desc="index"$`).MatchString(timing) {
+		t.Fatalf("Unexpected Server-Timing: %s\n", timing)
+	}
+}
+
+func Test_ServerTiming(t *testing.T) {
+	expect := `tmpl;dur=1.500;desc="say \"hi\" \\ bye"`
+	if result := serverTiming("say \"hi\" \\ bye\r\n", 1500*time.Microsecond); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
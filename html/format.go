@@ -0,0 +1,458 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// FormatOptions configures Format
+type FormatOptions struct {
+	// left and right action delimiters, defaults to {{ and }}
+	Delims [2]string
+	// indentation of a nesting level, defaults to two spaces
+	Indent string
+	// start tags longer than that get one attribute per line, 0 means 100,
+	// negative never wraps
+	Width int
+}
+
+// voidElements have no end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawElements keep their content as is
+var rawElements = map[string]bool{"pre": true, "textarea": true, "script": true, "style": true}
+
+// blockActions open a level closed by {{end}}
+var blockActions = map[string]bool{"if": true, "range": true, "with": true, "define": true, "block": true}
+
+// Format normalizes the layout of a template: actions lose the spaces
+// around their content, {{ .Title }} becomes {{.Title}}, lines are indented
+// by HTML element and block action nesting, and long start tags get one
+// attribute per line. The contents of pre, textarea, script and style
+// elements and of comments are left as is, so is the text of the lines.
+func Format(src []byte, opts FormatOptions) ([]byte, error) {
+	if opts.Delims[0] == "" {
+		opts.Delims = [2]string{"{{", "}}"}
+	}
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	if opts.Width == 0 {
+		opts.Width = 100
+	}
+	f := &formatter{opts: opts}
+	normalized, err := f.normalizeActions(string(src))
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(normalized, "\n")
+	var out bytes.Buffer
+	for i, line := range lines {
+		f.line(&out, line)
+		if i < len(lines)-1 {
+			out.WriteByte('\n')
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// formatter carries the nesting state across the lines
+type formatter struct {
+	opts  FormatOptions
+	depth int
+	// inside a start tag spanning several lines
+	inTag bool
+	// quote of the attribute value the tag line ended in
+	quote byte
+	// raw element whose content is being copied
+	raw string
+	// inside a comment spanning several lines
+	inComment bool
+}
+
+// normalizeActions trims and collapses the spaces of the actions of src.
+func (f *formatter) normalizeActions(src string) (string, error) {
+	left, right := f.opts.Delims[0], f.opts.Delims[1]
+	var b strings.Builder
+	for {
+		i := strings.Index(src, left)
+		if i < 0 {
+			b.WriteString(src)
+			return b.String(), nil
+		}
+		b.WriteString(src[:i])
+		src = src[i+len(left):]
+		end := actionEnd(src, right)
+		if end < 0 {
+			return "", fmt.Errorf("format: unterminated action %s%s", left, firstLine(src))
+		}
+		b.WriteString(left)
+		b.WriteString(normalizeAction(src[:end]))
+		b.WriteString(right)
+		src = src[end+len(right):]
+	}
+}
+
+// actionEnd returns the index of the right delimiter closing the action
+// starting s, skipping the delimiters in strings and comments.
+func actionEnd(s, right string) int {
+	if t := strings.TrimLeft(strings.TrimPrefix(s, "-"), " \t\r\n"); strings.HasPrefix(t, "/*") {
+		end := strings.Index(s, "*/")
+		if end < 0 {
+			return -1
+		}
+		if i := strings.Index(s[end:], right); i >= 0 {
+			return end + i
+		}
+		return -1
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case strings.HasPrefix(s[i:], right):
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeAction trims the content of an action and collapses its spaces,
+// trim markers keep the space they need.
+func normalizeAction(action string) string {
+	trimLeft := strings.HasPrefix(action, "- ") || strings.HasPrefix(action, "-\t") || strings.HasPrefix(action, "-\n")
+	if trimLeft {
+		action = action[1:]
+	}
+	trimRight := strings.HasSuffix(action, " -") || strings.HasSuffix(action, "\t-") || strings.HasSuffix(action, "\n-")
+	if trimRight {
+		action = action[:len(action)-1]
+	}
+	action = strings.TrimSpace(action)
+	if !strings.HasPrefix(action, "/*") {
+		action = collapseSpaces(action)
+	}
+	if trimLeft {
+		action = "- " + action
+	}
+	if trimRight {
+		action += " -"
+	}
+	return action
+}
+
+// collapseSpaces replaces the runs of spaces outside strings with one space.
+func collapseSpaces(s string) string {
+	var b strings.Builder
+	space := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' || c == '\'' || c == '`' {
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && c != '`' {
+					j++
+				}
+			}
+			if j >= len(s) {
+				j = len(s) - 1
+			}
+			if space {
+				b.WriteByte(' ')
+				space = false
+			}
+			b.WriteString(s[i : j+1])
+			i = j
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// line writes line indented to the current depth and updates the state.
+func (f *formatter) line(out *bytes.Buffer, line string) {
+	if f.raw != "" || f.inComment || f.quote != 0 {
+		// Copied as is, the state still follows the end of the element
+		out.WriteString(strings.TrimRight(line, " \t\r"))
+		f.scan(line)
+		return
+	}
+	text := strings.TrimSpace(line)
+	if text == "" {
+		return
+	}
+	depth := f.depth
+	if f.inTag {
+		depth++
+	} else if f.closes(text) {
+		depth--
+	}
+	if depth < 0 {
+		depth = 0
+	}
+	indent := strings.Repeat(f.opts.Indent, depth)
+	if !f.inTag && f.opts.Width > 0 && len(indent)+len(text) > f.opts.Width {
+		if wrapped, ok := f.wrapTag(text, indent); ok {
+			out.WriteString(wrapped)
+			f.scan(text)
+			return
+		}
+	}
+	out.WriteString(indent)
+	out.WriteString(text)
+	f.scan(text)
+}
+
+// closes reports whether text starts with an end tag, {{end}} or {{else}},
+// which are indented one level less.
+func (f *formatter) closes(text string) bool {
+	if strings.HasPrefix(text, "</") {
+		return true
+	}
+	if !strings.HasPrefix(text, f.opts.Delims[0]) {
+		return false
+	}
+	word := actionWord(text[len(f.opts.Delims[0]):])
+	return word == "end" || word == "else"
+}
+
+// actionWord returns the first word of the content of an action.
+func actionWord(action string) string {
+	action = strings.TrimLeft(strings.TrimPrefix(action, "-"), " \t")
+	end := strings.IndexAny(action, " \t-}")
+	if end < 0 {
+		return action
+	}
+	return action[:end]
+}
+
+// scan follows the tags, actions and comments of text to update the
+// nesting depth.
+func (f *formatter) scan(text string) {
+	left, right := f.opts.Delims[0], f.opts.Delims[1]
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case f.inComment:
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return
+			}
+			f.inComment = false
+			i += end + 3
+		case strings.HasPrefix(rest, left):
+			end := actionEnd(rest[len(left):], right)
+			if end < 0 {
+				return
+			}
+			// Actions are transparent to raw elements and quoted values
+			if f.raw == "" && f.quote == 0 && !f.inTag {
+				switch word := actionWord(rest[len(left):]); {
+				case blockActions[word]:
+					f.depth++
+				case word == "end":
+					f.depth--
+				}
+			}
+			i += len(left) + end + len(right)
+		case f.quote != 0:
+			if rest[0] == f.quote {
+				f.quote = 0
+			}
+			i++
+		case f.inTag:
+			switch rest[0] {
+			case '"', '\'':
+				f.quote = rest[0]
+			case '>':
+				f.inTag = false
+			}
+			i++
+		case f.raw != "":
+			if len(rest) >= len(f.raw)+2 && strings.EqualFold(rest[:len(f.raw)+2], "</"+f.raw) {
+				i += len(f.raw) + 2
+				f.raw = ""
+				f.depth--
+				continue
+			}
+			i++
+		case strings.HasPrefix(rest, "<!--"):
+			f.inComment = true
+			i += 4
+		case strings.HasPrefix(rest, "</"):
+			f.depth--
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return
+			}
+			i += end + 1
+		case rest[0] == '<' && len(rest) > 1 && isLetter(rest[1]):
+			name := tagName(rest[1:])
+			i += 1 + len(name)
+			end := tagEnd(text[i:], left, right)
+			selfClosing := end >= 0 && end > 0 && text[i+end-1] == '/'
+			name = strings.ToLower(name)
+			if !voidElements[name] && !selfClosing {
+				f.depth++
+				if rawElements[name] {
+					f.raw = name
+				}
+			}
+			if end < 0 {
+				f.inTag = true
+				f.scanTag(text[i:])
+				return
+			}
+			i += end + 1
+		default:
+			i++
+		}
+	}
+}
+
+// scanTag follows the quotes of the start tag text ends in.
+func (f *formatter) scanTag(text string) {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case f.quote != 0:
+			if text[i] == f.quote {
+				f.quote = 0
+			}
+		case text[i] == '"' || text[i] == '\'':
+			f.quote = text[i]
+		}
+	}
+}
+
+// tagEnd returns the index of the > closing the start tag whose
+// attributes start s, -1 if it continues on the next line.
+func tagEnd(s, left, right string) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], left):
+			end := actionEnd(s[i+len(left):], right)
+			if end < 0 {
+				return -1
+			}
+			i += len(left) + end + len(right) - 1
+		case s[i] == '"' || s[i] == '\'':
+			end := strings.IndexByte(s[i+1:], s[i])
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		case s[i] == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// wrapTag returns text with one attribute per line if it is a single
+// start tag with several attributes.
+func (f *formatter) wrapTag(text, indent string) (string, bool) {
+	left, right := f.opts.Delims[0], f.opts.Delims[1]
+	if text[0] != '<' || len(text) < 2 || !isLetter(text[1]) {
+		return "", false
+	}
+	name := tagName(text[1:])
+	rest := text[1+len(name):]
+	end := tagEnd(rest, left, right)
+	if end != len(rest)-1 {
+		return "", false
+	}
+	closing := ">"
+	attrs := rest[:end]
+	if strings.HasSuffix(attrs, "/") {
+		attrs, closing = strings.TrimSuffix(attrs, "/"), " />"
+	}
+	fields := splitAttributes(attrs, left, right)
+	if len(fields) < 2 {
+		return "", false
+	}
+	var b strings.Builder
+	b.WriteString(indent + "<" + name)
+	for _, field := range fields {
+		b.WriteString("\n" + indent + f.opts.Indent + field)
+	}
+	b.WriteString(strings.TrimPrefix(closing, " "))
+	return b.String(), true
+}
+
+// splitAttributes splits the attributes of a start tag on the spaces
+// outside of values and actions.
+func splitAttributes(s, left, right string) []string {
+	var fields []string
+	start := -1
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], left):
+			if start < 0 {
+				start = i
+			}
+			end := actionEnd(s[i+len(left):], right)
+			if end < 0 {
+				end = len(s) - i - len(left) - len(right)
+			}
+			i += len(left) + end + len(right) - 1
+		case s[i] == '"' || s[i] == '\'':
+			if start < 0 {
+				start = i
+			}
+			if end := strings.IndexByte(s[i+1:], s[i]); end >= 0 {
+				i += end + 1
+			}
+		case s[i] == ' ' || s[i] == '\t':
+			if start >= 0 {
+				fields = append(fields, s[start:i])
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, s[start:])
+	}
+	return fields
+}
+
+// tagName returns the element name s starts with.
+func tagName(s string) string {
+	end := 0
+	for end < len(s) && (isLetter(s[end]) || s[end] >= '0' && s[end] <= '9' || s[end] == '-') {
+		end++
+	}
+	return s[:end]
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,32 @@
+package html
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_Format(t *testing.T) {
+	src, err := os.ReadFile("./testdata/format/page.html")
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	expect, err := os.ReadFile("./testdata/format/page.formatted.html")
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	result, err := Format(src, FormatOptions{Width: 80})
+	if err != nil {
+		t.Fatalf("format: %v\n", err)
+	}
+	if string(result) != string(expect) {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	// Formatting is idempotent
+	if again, _ := Format(result, FormatOptions{Width: 80}); string(again) != string(result) {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", result, again)
+	}
+
+	if _, err := Format([]byte(`<p>{{.Name</p>`), FormatOptions{}); err == nil {
+		t.Fatalf("Expected error for an unterminated action\n")
+	}
+}
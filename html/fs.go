@@ -0,0 +1,147 @@
+package html
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// source returns the views folder as a file system, with the mounted
+// modules.
+func (e *Engine) source() (fs.FS, error) {
+	src, err := e.views()
+	if err != nil || len(e.mounts) == 0 {
+		return src, err
+	}
+	return e.mountModules(src)
+}
+
+// views returns the views folder as a file system.
+func (e *Engine) views() (fs.FS, error) {
+	if e.fsys != nil {
+		dir := strings.Trim(path.Clean("/"+e.directory), "/")
+		if dir == "" {
+			return e.fsys, nil
+		}
+		return fs.Sub(e.fsys, dir)
+	}
+	if e.fileSystem != nil {
+		return toFS(e.fileSystem, e.directory), nil
+	}
+	return dirFS(e.directory)
+}
+
+// readFile returns the content of the template file, enforcing the
+// maximum template size, run through the pre-processors.
+func (e *Engine) readFile(src fs.FS, name string) ([]byte, error) {
+	file, err := src.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if e.maxSize <= 0 {
+		buf, err := io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		return e.preProcess(name, buf)
+	}
+	if info, err := file.Stat(); err == nil && info.Size() > e.maxSize {
+		return nil, fmt.Errorf("load: template %s is %d bytes, exceeds the maximum size of %d bytes", name, info.Size(), e.maxSize)
+	}
+	// The size reported by a remote file system may be wrong
+	buf, err := io.ReadAll(io.LimitReader(file, e.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(buf)) > e.maxSize {
+		return nil, fmt.Errorf("load: template %s exceeds the maximum size of %d bytes", name, e.maxSize)
+	}
+	return e.preProcess(name, buf)
+}
+
+// httpFS adapts a http.FileSystem rooted at root to fs.FS
+type httpFS struct {
+	fs   http.FileSystem
+	root string
+}
+
+// toFS returns the http.FileSystem folder root as a fs.FS.
+func toFS(fsys http.FileSystem, root string) fs.FS {
+	return httpFS{fs: fsys, root: root}
+}
+
+// Open opens the file name relative to the root.
+func (h httpFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	file, err := h.fs.Open(path.Join("/", h.root, name))
+	if err != nil {
+		return nil, err
+	}
+	return httpFile{file}, nil
+}
+
+// httpFile adds ReadDir to http.File
+type httpFile struct {
+	http.File
+}
+
+// ReadDir reads the directory entries of the file.
+func (f httpFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	infos, err := f.Readdir(n)
+	entries := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	return entries, err
+}
+
+// overlayFS serves the files of upper, falling back to lower
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+// Open opens name from upper if it exists there, otherwise from lower.
+// Other errors of upper are returned rather than hidden by lower.
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.upper.Open(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return file, err
+	}
+	return o.lower.Open(name)
+}
+
+// ReadDir merges the entries of the directory name in both file systems,
+// which may lack it.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	if upperErr != nil && !errors.Is(upperErr, fs.ErrNotExist) {
+		return nil, upperErr
+	}
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if lowerErr != nil && (upperErr != nil || !errors.Is(lowerErr, fs.ErrNotExist)) {
+		return nil, lowerErr
+	}
+	seen := make(map[string]bool, len(upper))
+	entries := append([]fs.DirEntry(nil), upper...)
+	for _, entry := range upper {
+		seen[entry.Name()] = true
+	}
+	for _, entry := range lower {
+		if !seen[entry.Name()] {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
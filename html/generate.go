@@ -0,0 +1,124 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// GenerateConfig configures the Go file written by Generate
+type GenerateConfig struct {
+	// package of the generated file
+	Package string
+	// views folder relative to the package, e.g. views
+	Dir string
+	// views extension including the dot, defaults to .html
+	Extension string
+	// layout name without extension, optional
+	Layout string
+	// name of the engine variable, defaults to Views
+	Var string
+	// name of a func(*html.Engine) of the package called once the engine
+	// is built, e.g. to add funcs, optional
+	Configure string
+}
+
+// generated is the Go file written by Generate
+var generated = template.Must(template.New("generated").Funcs(template.FuncMap{"join": strings.Join}).Parse(`// Code generated by views generate. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/znbang/gofiber-layout/html"
+)
+
+//go:embed {{join .Patterns " "}}
+var {{.Var}}Files embed.FS
+
+// {{.Var}} renders the views embedded from {{.Dir}}
+var {{.Var}} *html.Engine
+
+func init() {
+	files, err := fs.Sub({{.Var}}Files, {{printf "%q" .Dir}})
+	if err != nil {
+		panic(err)
+	}
+	{{.Var}} = html.NewWithOptions(
+		html.WithFS(files),
+		html.WithExtension({{printf "%q" .Extension}}),
+		{{- if .Layout}}
+		html.WithLayout({{printf "%q" .Layout}}),
+		{{- end}}
+	)
+	{{- if .Configure}}
+	{{.Configure}}({{.Var}})
+	{{- end}}
+}
+`))
+
+// Generate writes a Go file embedding the views of the folder cfg.Dir of
+// fsys, the package folder, and building the engine in an init, meant for
+// go:generate:
+//
+//	//go:generate views generate -dir views -layout layouts/main -configure setupViews
+func Generate(w io.Writer, fsys fs.FS, cfg GenerateConfig) error {
+	if cfg.Package == "" {
+		return fmt.Errorf("generate: no package")
+	}
+	if cfg.Extension == "" {
+		cfg.Extension = ".html"
+	}
+	if cfg.Var == "" {
+		cfg.Var = "Views"
+	}
+	cfg.Dir = path.Clean(strings.TrimPrefix(cfg.Dir, "./"))
+	if !fs.ValidPath(cfg.Dir) || cfg.Dir == "." {
+		return fmt.Errorf("generate: %s must be a folder below the package", cfg.Dir)
+	}
+	// Embed the views by folder, the ones starting with . or _ included
+	dirs := make(map[string]bool)
+	err := fs.WalkDir(fsys, cfg.Dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(name) == cfg.Extension {
+			dirs[path.Dir(name)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("generate: %v", err)
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("generate: no %s views in %s", cfg.Extension, cfg.Dir)
+	}
+	patterns := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		patterns = append(patterns, dir+"/*"+cfg.Extension)
+	}
+	sort.Strings(patterns)
+
+	var buf bytes.Buffer
+	err = generated.Execute(&buf, struct {
+		GenerateConfig
+		Patterns []string
+	}{cfg, patterns})
+	if err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("generate: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,36 @@
+package html
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_Generate(t *testing.T) {
+	var buf bytes.Buffer
+	err := Generate(&buf, os.DirFS("."), GenerateConfig{
+		Package:   "main",
+		Dir:       "./views",
+		Layout:    "layouts/main",
+		Configure: "setupViews",
+	})
+	if err != nil {
+		t.Fatalf("generate: %v\n", err)
+	}
+	for _, expect := range []string{
+		"package main\n",
+		"//go:embed views/*.html views/errors/*.html views/layouts/*.html\nvar ViewsFiles embed.FS\n",
+		`files, err := fs.Sub(ViewsFiles, "views")`,
+		`html.WithLayout("layouts/main"),`,
+		"\tsetupViews(Views)\n",
+	} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, buf.String())
+		}
+	}
+
+	if err := Generate(&buf, os.DirFS("."), GenerateConfig{Package: "main", Dir: "../views"}); err == nil {
+		t.Fatalf("Expected error for a folder outside of the package\n")
+	}
+}
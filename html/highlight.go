@@ -0,0 +1,54 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// Highlighter renders source code in the language lang as highlighted HTML
+// styled by theme. A chroma adapter formats the tokens of
+// lexers.Get(lang) with html.New() and styles.Get(theme).
+type Highlighter interface {
+	Highlight(lang, code, theme string) (string, error)
+}
+
+// HighlighterFunc adapts a func to the Highlighter interface
+type HighlighterFunc func(lang, code, theme string) (string, error)
+
+// Highlight calls f(lang, code, theme).
+func (f HighlighterFunc) Highlight(lang, code, theme string) (string, error) {
+	return f(lang, code, theme)
+}
+
+// PlainHighlighter highlights nothing, it escapes the code into a
+// <pre><code class="language-lang"> block for client or CSS styling.
+var PlainHighlighter = HighlighterFunc(func(lang, code, theme string) (string, error) {
+	class := ""
+	if lang != "" {
+		class = ` class="language-` + template.HTMLEscapeString(lang) + `"`
+	}
+	return `<pre><code` + class + `>` + template.HTMLEscapeString(code) + `</code></pre>`, nil
+})
+
+// Highlighter sets the highlighter and its default theme, and registers the
+// highlight func rendering code blocks on the server:
+// {{highlight "go" .Snippet}}, or with another theme
+// {{highlight "go" .Snippet "monokai"}}. The highlighter output is trusted
+// as safe HTML, it must escape the code.
+func (e *Engine) Highlighter(h Highlighter, theme string) *Engine {
+	e.highlighter, e.highlightTheme = h, theme
+	return e.AddFunc("highlight", e.highlight)
+}
+
+// highlight returns code highlighted in the default theme or the given one.
+func (e *Engine) highlight(lang, code string, theme ...string) (template.HTML, error) {
+	style := e.highlightTheme
+	if len(theme) > 0 {
+		style = theme[0]
+	}
+	out, err := e.highlighter.Highlight(lang, code, style)
+	if err != nil {
+		return "", fmt.Errorf("highlight: %s: %w", lang, err)
+	}
+	return template.HTML(out), nil
+}
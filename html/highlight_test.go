@@ -0,0 +1,25 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"testing"
+)
+
+func Test_Highlighter(t *testing.T) {
+	engine := New("./testdata/highlight", ".html")
+	engine.Highlighter(HighlighterFunc(func(lang, code, theme string) (string, error) {
+		plain, _ := PlainHighlighter(lang, code, theme)
+		return fmt.Sprintf(`<div class="%s">%s</div>`, template.HTMLEscapeString(theme), plain), nil
+	}), "github")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "post", map[string]interface{}{"Snippet": `if a < b {}`}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<article><div class="github"><pre><code class="language-go">if a &lt; b {}</code></pre></div>` +
+		`<div class="dark"><pre><code>if a &lt; b {}</code></pre></div></article>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,54 @@
+package html
+
+import "context"
+
+// OnLoad adds a hook called after each successful load, including
+// reloads, e.g. to warm caches.
+func (e *Engine) OnLoad(fn func(ctx context.Context, stats Stats)) *Engine {
+	e.onLoad = append(e.onLoad, fn)
+	return e
+}
+
+// OnReload adds a hook called after each successful load but the first
+// that changed the templates, e.g. to notify live reload clients.
+func (e *Engine) OnReload(fn func(ctx context.Context, stats Stats)) *Engine {
+	e.onReload = append(e.onReload, fn)
+	return e
+}
+
+// OnError adds a hook called with the error of each failed load, e.g. to
+// page an operator when a production reload fails.
+func (e *Engine) OnError(fn func(ctx context.Context, err error)) *Engine {
+	e.onError = append(e.onError, fn)
+	return e
+}
+
+// OnRenderError adds a hook called with the template name and error of
+// each failed render, Stats counts the errors of each template.
+func (e *Engine) OnRenderError(fn func(name string, err error)) *Engine {
+	e.onRenderError = append(e.onRenderError, fn)
+	return e
+}
+
+// loadHooks calls the hooks of a load, reload tells whether the templates
+// had been loaded before.
+func (e *Engine) loadHooks(ctx context.Context, reload bool, err error) {
+	if err != nil {
+		for _, fn := range e.onError {
+			fn(ctx, err)
+		}
+		return
+	}
+	if len(e.onLoad) == 0 && (!reload || len(e.onReload) == 0) {
+		return
+	}
+	stats := e.Stats()
+	for _, fn := range e.onLoad {
+		fn(ctx, stats)
+	}
+	if reload {
+		for _, fn := range e.onReload {
+			fn(ctx, stats)
+		}
+	}
+}
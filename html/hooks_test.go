@@ -0,0 +1,114 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_LoadHooks(t *testing.T) {
+	var loads, reloads, errors int
+	dir := t.TempDir()
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+	write("v1")
+	engine := New(dir, ".html")
+	engine.OnLoad(func(ctx context.Context, stats Stats) {
+		if stats.Templates == 0 {
+			t.Fatalf("Expected load stats\n")
+		}
+		loads++
+	}).OnReload(func(ctx context.Context, stats Stats) {
+		reloads++
+	}).OnError(func(ctx context.Context, err error) {
+		errors++
+	})
+
+	engine.Reload(true)
+	var buf bytes.Buffer
+	for i := 0; i < 2; i++ {
+		if err := engine.Render(&buf, "index", nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+	}
+	// The same templates loaded again are no reload
+	if loads != 2 || reloads != 0 || errors != 0 {
+		t.Fatalf("Expected 2 loads and no reload, got %d loads %d reloads %d errors\n", loads, reloads, errors)
+	}
+	write("v2")
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if loads != 3 || reloads != 1 || errors != 0 {
+		t.Fatalf("Expected 3 loads and 1 reload, got %d loads %d reloads %d errors\n", loads, reloads, errors)
+	}
+
+	engine.SetDirectory("./missing")
+	if err := engine.Render(&buf, "index", nil); err == nil {
+		t.Fatalf("Expected load error\n")
+	}
+	if loads != 3 || errors != 1 {
+		t.Fatalf("Expected 1 error, got %d loads %d errors\n", loads, errors)
+	}
+}
+
+func Test_ConcurrentFirstLoad(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	var loads atomic.Int32
+	engine.OnLoad(func(ctx context.Context, stats Stats) {
+		loads.Add(1)
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			if err := engine.Render(&buf, "index", nil); err != nil {
+				t.Errorf("render: %v\n", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := loads.Load(); n != 1 || engine.Stats().Loads != 1 {
+		t.Fatalf("Expected 1 load, got %d hooks and %d loads\n", n, engine.Stats().Loads)
+	}
+}
+
+func Test_OnRenderError(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	var failed []string
+	engine.OnRenderError(func(name string, err error) {
+		failed = append(failed, name)
+	})
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := engine.Render(&buf, "missing", nil); err == nil {
+			t.Fatalf("Expected render error\n")
+		}
+	}
+	if len(failed) != 2 || failed[0] != "missing" {
+		t.Fatalf("Expected 2 failed renders of missing, got %v\n", failed)
+	}
+	stats := engine.Stats()
+	if stats.TemplateErrors["missing"] != 2 || stats.TemplateErrors["index"] != 0 || stats.LastError.IsZero() {
+		t.Fatalf("Unexpected stats %+v\n", stats)
+	}
+}
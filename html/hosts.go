@@ -0,0 +1,77 @@
+package html
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// Site is the theme, tenant and locale of the requests to a host, empty
+// fields are left to the render context and the engine defaults
+type Site struct {
+	Theme  string
+	Tenant string
+	Locale string
+}
+
+// host is a host pattern and its site
+type host struct {
+	pattern string
+	site    Site
+}
+
+// Host serves the requests to the host pattern with site, e.g. for white
+// label deployments. Patterns are host names such as shop.example.com or
+// *.example.com matching the subdomains, the first matching one wins.
+// Respond selects the site of the request, HostMiddleware does it for the
+// other adapters.
+func (e *Engine) Host(pattern string, site Site) *Engine {
+	e.mutex.Lock()
+	e.hosts = append(e.hosts, host{pattern: strings.ToLower(pattern), site: site})
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// WithSite returns a copy of ctx selecting the site of hostname, the
+// theme, tenant and locale already selected by ctx take precedence.
+func (e *Engine) WithSite(ctx context.Context, hostname string) context.Context {
+	site, ok := e.site(hostname)
+	if !ok {
+		return ctx
+	}
+	if _, ok := ctx.Value(themeKey{}).(string); !ok && site.Theme != "" {
+		ctx = WithTheme(ctx, site.Theme)
+	}
+	if _, ok := ctx.Value(tenantKey{}).(string); !ok && site.Tenant != "" {
+		ctx = WithTenant(ctx, site.Tenant)
+	}
+	if Locale(ctx) == "" && site.Locale != "" {
+		ctx = WithLocale(ctx, site.Locale)
+	}
+	return ctx
+}
+
+// site returns the site of the first pattern matching hostname.
+func (e *Engine) site(hostname string) (Site, bool) {
+	if h, _, err := net.SplitHostPort(hostname); err == nil {
+		hostname = h
+	}
+	for _, h := range e.published().hosts {
+		if matchHost(h.pattern, hostname) {
+			return h.site, true
+		}
+	}
+	return Site{}, false
+}
+
+// matchHost reports whether hostname, without port, matches the lower case
+// host pattern.
+func matchHost(pattern, hostname string) bool {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	if pattern == hostname {
+		return true
+	}
+	suffix := strings.TrimPrefix(pattern, "*")
+	return suffix != pattern && strings.HasSuffix(hostname, suffix)
+}
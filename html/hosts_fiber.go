@@ -0,0 +1,16 @@
+//go:build !noos
+
+package html
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// HostMiddleware selects the site of the request host in the user context
+// of c, for adapters such as Inertia rendering with it.
+func (e *Engine) HostMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(e.WithSite(c.UserContext(), c.Hostname()))
+		return c.Next()
+	}
+}
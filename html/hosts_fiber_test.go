@@ -0,0 +1,40 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Host(t *testing.T) {
+	engine := New("./testdata/hosts/base", ".html")
+	engine.Themes(map[string]fs.FS{"acme": os.DirFS("./testdata/hosts/acme")})
+	engine.Host("*.acme.com", Site{Theme: "acme"})
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return engine.Respond(c, "page", "home")
+	})
+	tests := map[string]string{
+		"shop.acme.com:8080": `acme home`,
+		"example.com":        `base home`,
+	}
+	for hostname, expect := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Host = hostname
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if result := trim(string(body)); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
@@ -1,17 +1,24 @@
 package html
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"html/template"
 	"io"
+	"io/fs"
+	"log/slog"
 	"net/http"
-	"os"
-	"path"
-	"path/filepath"
+	pathpkg "path"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
-
-	"github.com/gofiber/template/utils"
+	"sync/atomic"
+	"time"
 )
 
 // Engine struct
@@ -23,22 +30,196 @@ type Engine struct {
 	directory string
 	// http.FileSystem supports embedded files
 	fileSystem http.FileSystem
+	// fs.FS supports embedded files, takes precedence over fileSystem
+	fsys fs.FS
 	// views extension
 	extension string
 	// layout variable name that incapsulates the template
 	layout string
 	// determines if the engine parsed all templates
-	loaded bool
+	loaded *atomic.Bool
+	// template set of the last successful load, read by renders without
+	// the lock
+	current *atomic.Pointer[templateVersion]
+	// runtime configuration read by renders without the lock, and the
+	// number of times it was published
+	settings   *atomic.Pointer[renderSettings]
+	generation uint64
 	// reload on each render
 	reload bool
 	// debug prints the parsed templates
 	debug bool
+	// receives structured engine events
+	logger *slog.Logger
+	// receives render and load measurements
+	metrics Metrics
+	// starts render and load spans
+	tracer Tracer
+	// render and load counters
+	stats *engineStats
+	// in-flight renders and background resources
+	life *lifecycle
+	// stops the checks of the last RefreshEvery
+	refreshStop chan struct{}
+	// append Server-Timing entries in Respond
+	serverTiming bool
+	// record the templates executed by each render
+	audit bool
+	// time the funcs, sub-templates and range loops of each render
+	profile bool
+	// wrap included templates in HTML comments
+	traceComments bool
+	traceID       func(ctx context.Context) string
+	// helper packs added with Use and the errors of their Init
+	packs    []usedPack
+	packErrs []error
+	// modules mounted in the views folder
+	mounts []moduleMount
+	// allowed template name prefixes
+	prefixes []string
+	// maximum template file size in bytes, 0 means unlimited
+	maxSize int64
+	// strict mode func policy
+	policy *FuncPolicy
+	// categories of the tagged funcs
+	functags map[string][]string
+	// static assets for the sri func
+	assets http.FileSystem
+	// integrity hashes of the assets, replaced on each load
+	sriHashes *atomic.Pointer[sriCache]
+	// cached fragments of the cache func
+	fragments *renderCache
+	// cached pages of the renders with a cache key
+	pages *renderCache
+	// cached responses of the ResponseCache middleware
+	responses *renderCache
+	// stores the cached pages and fragments under the keys prefix
+	cacheStore  CacheStore
+	cachePrefix string
+	// pages rendered into the cache after each load
+	warmTargets []WarmTarget
+	// email templates getting their styles inlined
+	emails map[string]bool
+	// content hash of the loaded templates
+	version string
+	// number of replaced template sets kept for Rollback
+	keepVersions int
+	// replaced template sets, the most recent last
+	history []*templateVersion
+	// checks the signature of the views folder
+	verifier Verifier
+	// name of the signature file in the views folder
+	signatureFile string
+	// sanitize policies by name
+	sanitizers map[string]Sanitizer
+	// keys of the binding values redacted from debug output
+	redactPatterns []*regexp.Regexp
+	// theme views by name
+	themes map[string]fs.FS
+	// theme used when the render context selects none
+	defaultTheme string
+	// templates parsed for each theme
+	themeSets map[string]*templateSet
+	// locale used when the render context selects none
+	defaultLocale string
+	// configured locale fallback chains
+	fallbacks map[string][]string
+	// looks up the messages of the t func
+	translator Translator
+	// formats the messages of the t func from a message catalog
+	catalog *catalogPrinters
+	// timezone used when the render context selects none
+	defaultTimezone *time.Location
+	// age from which timeago renders dates
+	timeagoThreshold time.Duration
+	// layout of the dates rendered by timeago
+	timeagoLayout string
+	// key and lifetime of the links of the signedURL func
+	urlKey []byte
+	urlTTL time.Duration
+	// tenants by name
+	tenants map[string]*Tenant
+	// data merged into map bindings
+	globals map[string]interface{}
+	// variant templates by experiment template and variant key
+	variants map[string]map[string]string
+	// called after each render
+	onRender []func(ctx context.Context, info RenderInfo)
+	// called after loads, reloads and failed loads
+	onLoad   []func(ctx context.Context, stats Stats)
+	onReload []func(ctx context.Context, stats Stats)
+	onError  []func(ctx context.Context, err error)
+	// rewrite the output of each page render
+	postProcessors []PostProcessor
+	// rewrite the source of each template file at load
+	preProcessors []PreProcessor
+	// map the bindings before each render
+	transforms []func(name string, binding interface{}) (interface{}, error)
+	// check the bindings against the data required by each template
+	validateBindings bool
+	required         map[string][]string
+	// content hashes of the files of the last load by template, and the
+	// templates changed by the last reload
+	sums    map[string]string
+	changes ReloadDiff
+	// template rendered in place of the others, empty if not in maintenance
+	maintenance string
+	// templates still rendered in maintenance mode
+	maintenanceAllow []string
+	// sites selected by request host
+	hosts []host
+	// picks the device class of requests and its layout
+	deviceSelector deviceSelectorFunc
+	deviceLayouts  map[string]string
+	// layout of the print version and the query parameter asking for it
+	printLayout string
+	printParam  string
+	// canonical func registered, its host override, trusted request hosts
+	// and stripped parameters
+	canonical      bool
+	canonicalHost  string
+	canonicalHosts []string
+	canonicalStrip []string
+	// highlighter of the highlight func and its default theme
+	highlighter    Highlighter
+	highlightTheme string
+	// templates listed in the sitemap
+	sitemap []sitemapPage
+	// layouts of the error pages by status code
+	statusLayouts map[int]string
+	// alternate layouts and the pages parsed with each
+	layouts    []string
+	layoutSets map[string]*templateSet
+	// folders of the partials and wrappers parsed into every page
+	partials string
+	wrappers string
+	// match the extension of the views case-insensitively
+	extensionFold bool
+	// called with the name collisions found by a load
+	onCollision func(err *CollisionError)
+	// called with the error of each failed render
+	onRenderError []func(name string, err error)
+	// funcs taking the render context
+	ctxfuncs map[string]interface{}
+	// context funcs of the parsed templates
+	contextFuncs map[string]interface{}
+	// copies of the parsed templates bound to a render context
+	pools map[*template.Template]*templatePool
+	// engines whose templates are merged on load
+	merged []mergedEngine
 	// lock for funcmap and templates
-	mutex sync.RWMutex
+	mutex *sync.RWMutex
 	// template funcmap
 	funcmap map[string]interface{}
 	// templates
+	//
+	// Deprecated: use Template, SetTemplate and Names, the map is replaced
+	// on each load and reading it races with reloads.
 	Templates map[string]*template.Template
+	// templates set by SetTemplate, kept across loads
+	overrides map[string]*template.Template
+	// assets referenced by each template
+	preloads map[string][]Preload
 }
 
 // New returns a HTML render engine for Fiber
@@ -50,6 +231,13 @@ func New(directory, extension string) *Engine {
 		extension: extension,
 		layout:    "",
 		funcmap:   make(map[string]interface{}),
+		mutex:     &sync.RWMutex{},
+		loaded:    &atomic.Bool{},
+		current:   &atomic.Pointer[templateVersion]{},
+		settings:  &atomic.Pointer[renderSettings]{},
+		stats:     &engineStats{},
+		life:      newLifecycle(),
+		sriHashes: newSRIHashes(),
 	}
 	return engine
 }
@@ -64,6 +252,13 @@ func NewFileSystem(fs http.FileSystem, extension string) *Engine {
 		extension:  extension,
 		layout:     "",
 		funcmap:    make(map[string]interface{}),
+		mutex:      &sync.RWMutex{},
+		loaded:     &atomic.Bool{},
+		current:    &atomic.Pointer[templateVersion]{},
+		settings:   &atomic.Pointer[renderSettings]{},
+		stats:      &engineStats{},
+		life:       newLifecycle(),
+		sriHashes:  newSRIHashes(),
 	}
 	return engine
 }
@@ -71,6 +266,7 @@ func NewFileSystem(fs http.FileSystem, extension string) *Engine {
 // Layout defines the variable name that will incapsulate the template
 func (e *Engine) Layout(key string) *Engine {
 	e.layout = key
+	e.loaded.Store(false)
 	return e
 }
 
@@ -79,6 +275,47 @@ func (e *Engine) Layout(key string) *Engine {
 // corresponding default: {{ or }}.
 func (e *Engine) Delims(left, right string) *Engine {
 	e.left, e.right = left, right
+	e.loaded.Store(false)
+	return e
+}
+
+// SetDirectory points the engine at another views folder, the templates
+// are loaded from it on the next render.
+func (e *Engine) SetDirectory(directory string) *Engine {
+	e.mutex.Lock()
+	e.directory = directory
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// SetFS points the engine at another file system, e.g. a new bundle from
+// OpenBundle, the templates are loaded from it on the next render.
+func (e *Engine) SetFS(fsys fs.FS) *Engine {
+	e.mutex.Lock()
+	e.fsys = fsys
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// SetExtension changes the views extension, the templates are loaded
+// again on the next render.
+func (e *Engine) SetExtension(extension string) *Engine {
+	e.mutex.Lock()
+	e.extension = extension
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// SetDelims is like Delims but safe to call while the engine renders,
+// the templates are loaded again on the next render.
+func (e *Engine) SetDelims(left, right string) *Engine {
+	e.mutex.Lock()
+	e.left, e.right = left, right
+	e.loaded.Store(false)
+	e.mutex.Unlock()
 	return e
 }
 
@@ -87,6 +324,7 @@ func (e *Engine) Delims(left, right string) *Engine {
 func (e *Engine) AddFunc(name string, fn interface{}) *Engine {
 	e.mutex.Lock()
 	e.funcmap[name] = fn
+	e.loaded.Store(false)
 	e.mutex.Unlock()
 	return e
 }
@@ -99,12 +337,51 @@ func (e *Engine) Reload(enabled bool) *Engine {
 	return e
 }
 
-// Debug will print the parsed templates when Load is triggered.
+// IgnoreExtensionCase if set to true also loads the files whose extension
+// differs in case, e.g. INDEX.HTML or index.Html loading as INDEX and
+// index with the .html extension. The layout file keeps the exact extension.
+func (e *Engine) IgnoreExtensionCase(enabled bool) *Engine {
+	e.mutex.Lock()
+	e.extensionFold = enabled
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// Debug will print the parsed templates when Load is triggered,
+// along with the other engine events if no Logger is set.
 func (e *Engine) Debug(enabled bool) *Engine {
 	e.debug = enabled
 	return e
 }
 
+// MaxTemplateSize makes Load fail on template files larger than size bytes,
+// so a huge file dropped into the views folder is never read into memory.
+func (e *Engine) MaxTemplateSize(size int64) *Engine {
+	e.maxSize = size
+	return e
+}
+
+// Logger sets the handler receiving structured engine events: parsed
+// templates, loads, reloads and renders.
+func (e *Engine) Logger(h slog.Handler) *Engine {
+	e.logger = slog.New(h)
+	return e
+}
+
+// Metrics sets the hook receiving render counts, durations, errors,
+// template cache hits and reloads.
+func (e *Engine) Metrics(m Metrics) *Engine {
+	e.metrics = m
+	return e
+}
+
+// Tracer sets the tracer starting a span per Render and Load.
+func (e *Engine) Tracer(t Tracer) *Engine {
+	e.tracer = t
+	return e
+}
+
 // Parse is deprecated, please use Load() instead
 func (e *Engine) Parse() error {
 	fmt.Println("Parse() is deprecated, please use Load() instead.")
@@ -113,114 +390,593 @@ func (e *Engine) Parse() error {
 
 // Load parses the templates to the engine.
 func (e *Engine) Load() error {
-	if e.loaded {
+	return e.LoadContext(context.Background())
+}
+
+// LoadContext parses the templates to the engine, ctx carries the parent
+// span of the load.
+func (e *Engine) LoadContext(ctx context.Context) error {
+	if e.loaded.Load() {
+		return nil
+	}
+	return e.loadContext(ctx, false)
+}
+
+// loadContext parses the templates, again if force is set while renders
+// go on with the current set.
+func (e *Engine) loadContext(ctx context.Context, force bool) error {
+	e.mutex.RLock()
+	directory, layout := e.directory, e.layout
+	e.mutex.RUnlock()
+	var span Span
+	if e.tracer != nil {
+		_, span = e.tracer.Start(ctx, "html.Load")
+		span.SetAttribute("directory", directory)
+		span.SetAttribute("layout", layout)
+	}
+	start := time.Now()
+	reload := e.stats.lastLoad.Load() != 0
+	var result loadResult
+	err := recovered("load", func() (err error) {
+		result, err = e.load(force)
+		return err
+	})
+	if span != nil {
+		span.SetAttribute("templates", result.templates)
+		span.End(err)
+	}
+	// Another render loaded them, its load is the one observed
+	if !result.parsed && err == nil {
 		return nil
 	}
+	e.stats.observeLoad(start, err)
+	if e.metrics != nil {
+		e.metrics.ObserveLoad(time.Since(start), err)
+	}
+	if err != nil {
+		e.event(ctx, slog.LevelError, "views: load failed", slog.String("directory", directory), slog.Any("error", err))
+	} else {
+		e.event(ctx, slog.LevelInfo, "views: loaded templates", slog.String("directory", directory), slog.Int("templates", result.templates), slog.Duration("duration", time.Since(start)))
+		if reload && result.changed {
+			e.mutex.RLock()
+			changes := e.changes
+			e.mutex.RUnlock()
+			if !changes.Empty() {
+				e.event(ctx, slog.LevelInfo, "views: templates changed", slog.Any("changes", changes))
+			}
+		}
+	}
+	// Warming errors are logged, the templates did load
+	if err == nil && len(e.warmTargets) > 0 && !e.reload {
+		e.warm(ctx, e.warmTargets)
+	}
+	// Reloads of the same templates, e.g. each render in Reload mode, are
+	// not reported to the reload hooks
+	e.loadHooks(ctx, reload && result.changed, err)
+	return err
+}
+
+// loadResult is what a load did
+type loadResult struct {
+	// the templates were parsed, not loaded by another render meanwhile
+	parsed bool
+	// number of templates of the published set
+	templates int
+	// the version of the templates changed
+	changed bool
+}
+
+// load walks the views folder and parses the templates, force parses
+// them even if loaded.
+func (e *Engine) load(force bool) (loadResult, error) {
 	// race safe
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	e.Templates = make(map[string]*template.Template)
+	// Another render loaded them while this one waited for the lock
+	if e.loaded.Load() && !force {
+		return loadResult{templates: len(e.Templates)}, nil
+	}
+	l, err := e.loadTemplates()
+	// renders keep the last published set after a failed load
+	if err != nil {
+		if e.current.Load() != nil {
+			e.loaded.Store(true)
+		}
+		return loadResult{parsed: true, templates: len(e.Templates)}, err
+	}
+	result := loadResult{parsed: true, templates: len(l.templates), changed: e.version != l.version}
+	// Keep the set replaced by a new version
+	if e.keepVersions > 0 && e.version != "" && e.version != l.version {
+		e.history = append(e.history, e.snapshot())
+		if len(e.history) > e.keepVersions {
+			e.history = e.history[len(e.history)-e.keepVersions:]
+		}
+	}
+	if e.sums != nil {
+		e.changes = diffSums(e.sums, l.sums)
+	}
+	e.version, e.sums = l.version, l.sums
+	e.Templates, e.preloads = l.templates, l.preloads
+	e.themeSets, e.layoutSets = l.themeSets, l.layoutSets
+	e.pools, e.contextFuncs = l.pools, l.contextFuncs
+	// Assets may have changed as well
+	e.sriHashes.Store(&sriCache{})
+	replaced := e.current.Load()
+	e.current.Store(e.snapshot())
+	// notify engine that we parsed all templates
+	e.loaded.Store(true)
+	// Cached output of the replaced set may come from other funcs
+	if replaced != nil {
+		return result, e.flushCaches(context.WithValue(context.Background(), versionKey{}, replaced))
+	}
+	return result, nil
+}
+
+// templateLoad is the template set built by a load along with the hashes
+// of its files, the engine takes it once all templates parsed
+type templateLoad struct {
+	templateVersion
+	digest hash.Hash
+	sums   map[string]string
+}
+
+// loadTemplates parses the templates, the caller holds the lock.
+func (e *Engine) loadTemplates() (*templateLoad, error) {
+	if len(e.packErrs) > 0 {
+		return nil, errors.Join(e.packErrs...)
+	}
+	// Check funcs against the sandbox policy
+	funcmap, err := e.sandboxFuncs()
+	if err != nil {
+		return nil, err
+	}
+	l := &templateLoad{
+		templateVersion: templateVersion{
+			templates:  make(map[string]*template.Template),
+			preloads:   make(map[string][]Preload),
+			themeSets:  make(map[string]*templateSet),
+			layoutSets: make(map[string]*templateSet),
+			pools:      make(map[*template.Template]*templatePool),
+		},
+		digest: sha256.New(),
+		sums:   make(map[string]string),
+	}
+	// Context funcs are bound to the render context of pooled copies
+	if funcmap, l.contextFuncs, err = e.bindContextFuncs(funcmap); err != nil {
+		return nil, err
+	}
+	if err = checkFuncs(funcmap); err != nil {
+		return nil, err
+	}
+	src, err := e.source()
+	if err != nil {
+		return nil, err
+	}
+	if e.verifier != nil {
+		if err = e.verifySources(e, src); err != nil {
+			return nil, err
+		}
+	}
+	base := &templateSet{l.templates, l.preloads}
+	if err = e.parse(l, src, "", e.layout, funcmap, base); err != nil {
+		return nil, err
+	}
+	if err = e.mergeInto(l, base); err != nil {
+		return nil, err
+	}
+	for name, tmpl := range e.overrides {
+		l.templates[name] = tmpl
+	}
+	// Themes fall back to the views folder for the files they lack
+	themes := make([]string, 0, len(e.themes))
+	for theme := range e.themes {
+		themes = append(themes, theme)
+	}
+	sort.Strings(themes)
+	for _, theme := range themes {
+		fsys := e.themes[theme]
+		set := &templateSet{
+			templates: make(map[string]*template.Template),
+			preloads:  make(map[string][]Preload),
+		}
+		if err = e.parse(l, overlayFS{fsys, src}, theme, e.layout, funcmap, set); err != nil {
+			return nil, fmt.Errorf("theme %s: %v", theme, err)
+		}
+		if err = e.mergeInto(l, set); err != nil {
+			return nil, fmt.Errorf("theme %s: %v", theme, err)
+		}
+		l.themeSets[theme] = set
+	}
+	// Alternate layouts are parsed for the views folder and each theme
+	for _, theme := range append([]string{""}, themes...) {
+		views := src
+		if theme != "" {
+			views = overlayFS{e.themes[theme], src}
+		}
+		for _, layout := range e.layouts {
+			key := layoutSetKey(theme, layout)
+			if layout == e.layout || l.layoutSets[key] != nil {
+				continue
+			}
+			set := &templateSet{
+				templates: make(map[string]*template.Template),
+				preloads:  make(map[string][]Preload),
+			}
+			if err = e.parse(l, views, theme, layout, funcmap, set); err != nil {
+				if theme != "" {
+					return nil, fmt.Errorf("theme %s: layout %s: %v", theme, layout, err)
+				}
+				return nil, fmt.Errorf("layout %s: %v", layout, err)
+			}
+			l.layoutSets[key] = set
+		}
+	}
+	l.version = hex.EncodeToString(l.digest.Sum(nil))[:16]
+	return l, nil
+}
 
+// parse walks src and parses the templates with layout into set.
+func (e *Engine) parse(l *templateLoad, src fs.FS, theme, layout string, funcmap map[string]interface{}, set *templateSet) error {
 	// Load layout
 	var layoutBuf []byte = nil
-	if e.layout != "" {
+	if layout != "" {
 		var err error
-		if layoutBuf, err = utils.ReadFile(path.Join(e.directory, e.layout+e.extension), e.fileSystem); err != nil {
+		if layoutBuf, err = e.readFile(src, layout+e.extension); err != nil {
 			return err
 		}
+		if err = e.checkLayout(layout, layoutBuf, funcmap); err != nil {
+			return err
+		}
+	}
+	layoutPreloads := e.scanPreloads(layoutBuf, nil)
+	fmt.Fprintf(l.digest, "theme %s layout %s %d\n", theme, layout, len(layoutBuf))
+	if layout != "" {
+		l.sums[sumKey(theme, layout)] = contentSum(layoutBuf)
+	}
+	l.digest.Write(layoutBuf)
+	// The partials folder of the app overrides those of the packs
+	partials, err := e.readPacks()
+	if err != nil {
+		return err
+	}
+	shared, err := e.readShared(src, e.partials)
+	if err != nil {
+		return err
+	}
+	partials = append(partials, shared...)
+	wrappers, err := e.readShared(src, e.wrappers)
+	if err != nil {
+		return err
 	}
 
-	walkFn := func(path string, info os.FileInfo, err error) error {
+	names := newCollisions()
+	walkFn := func(path string, d fs.DirEntry, err error) error {
 		// Return error if exist
 		if err != nil {
 			return err
 		}
-		// Skip file if it's a directory or has no file info
-		if info == nil || info.IsDir() {
+		// Skip file if it's a directory
+		if d.IsDir() {
 			return nil
 		}
 		// Get file extension of file
-		ext := filepath.Ext(path)
+		ext := pathpkg.Ext(path)
 		// Skip file if it does not equal the given template extension
-		if ext != e.extension {
+		if ext != e.extension && !(e.extensionFold && strings.EqualFold(ext, e.extension)) {
 			return nil
 		}
+		// Paths are relative to the views folder and use forward slashes
+		// partials/footer.tmpl -> partials/footer
+		name := strings.TrimSuffix(path, ext)
 		// Skip layout
-		if e.layout != "" && strings.HasSuffix(path, e.layout+e.extension) {
+		if layout != "" && strings.HasSuffix(name, layout) && ext == e.extension {
 			return nil
 		}
-		// Get the relative file path
-		// ./views/html/index.tmpl -> index.tmpl
-		rel, err := filepath.Rel(e.directory, path)
-		if err != nil {
-			return err
+		// The first file of a name wins, WalkDir walks in lexical order
+		if !names.add(name, path) {
+			return nil
 		}
-		// Reverse slashes '\' -> '/' and
-		// partials\footer.tmpl -> partials/footer.tmpl
-		name := filepath.ToSlash(rel)
-		// Remove ext from name 'index.tmpl' -> 'index'
-		name = strings.TrimSuffix(name, e.extension)
-		// name = strings.Replace(name, e.extension, "", -1)
 		// Read the file
-		// #gosec G304
-		buf, err := utils.ReadFile(path, e.fileSystem)
+		buf, err := e.readFile(src, path)
 		if err != nil {
 			return err
 		}
+		fmt.Fprintf(l.digest, "%s %d\n", name, len(buf))
+		l.sums[sumKey(theme, name)] = contentSum(buf)
+		l.digest.Write(buf)
 		// Create new template
 		var tmpl *template.Template
-		if e.layout != "" {
-			tmpl = template.New(e.layout)
+		if layout != "" {
+			tmpl = template.New(layout)
 		} else {
 			tmpl = template.New(name)
 		}
 		// Set template settings
 		tmpl.Delims(e.left, e.right)
-		tmpl.Funcs(e.funcmap)
+		tmpl.Funcs(funcmap)
 		// Parse layout
-		if e.layout != "" {
+		if layout != "" {
 			if _, err = tmpl.Parse(string(layoutBuf)); err != nil {
 				return err
 			}
+			if e.audit {
+				if err = instrument(tmpl, nil, layout); err != nil {
+					return err
+				}
+			}
+			if e.wrappers != "" {
+				if err = instrumentWrap(tmpl); err != nil {
+					return err
+				}
+			}
+			// Defines of the page replace the shared partials
+			if err = e.parsePartials(tmpl, append(partials, wrappers...), false); err != nil {
+				return err
+			}
+			before := trees(tmpl)
 			if _, err = tmpl.New(name).Parse(string(buf)); err != nil {
 				return err
 			}
+			if e.audit {
+				if err = instrument(tmpl, before, name); err != nil {
+					return err
+				}
+			}
 		} else {
 			if _, err = tmpl.Parse(string(buf)); err != nil {
 				return err
 			}
+			if e.audit {
+				if err = instrument(tmpl, nil, name); err != nil {
+					return err
+				}
+			}
+			if err = e.parsePartials(tmpl, partials, true); err != nil {
+				return err
+			}
 		}
-		e.Templates[name] = tmpl
-		// Debugging
-		if e.debug {
-			fmt.Printf("views: parsed template: %s\n", name)
+		if err = checkCycles(tmpl, path); err != nil {
+			return err
+		}
+		if e.profile {
+			if err = instrumentProfile(tmpl); err != nil {
+				return err
+			}
+		}
+		if e.traceComments {
+			if err = instrumentTrace(tmpl); err != nil {
+				return err
+			}
+		}
+		set.templates[name] = tmpl
+		if l.contextFuncs != nil {
+			l.pools[tmpl] = &templatePool{funcs: l.contextFuncs}
 		}
+		set.preloads[name] = e.scanPreloads(buf, append([]Preload(nil), layoutPreloads...))
+		// Debugging
+		e.event(context.Background(), slog.LevelDebug, "views: parsed template", slog.String("template", name), slog.String("theme", theme))
 		return err
 	}
-	// notify engine that we parsed all templates
-	e.loaded = true
-	if e.fileSystem != nil {
-		return utils.Walk(e.fileSystem, e.directory, walkFn)
+	if err := fs.WalkDir(src, ".", walkFn); err != nil {
+		return err
 	}
-	return filepath.Walk(e.directory, walkFn)
+	return names.check(e)
 }
 
-// Render will execute the template name along with the given values.
-func (e *Engine) Render(out io.Writer, template string, binding interface{}, layout ...string) error {
-	if !e.loaded || e.reload {
+// lookup loads the templates if needed and returns the template name
+// along with the name of the page it resolved to and the template set it
+// was taken from, hit is false if the templates had to be parsed first.
+func (e *Engine) lookup(ctx context.Context, name string) (tmpl *template.Template, page string, set *templateVersion, hit bool, err error) {
+	if err = e.checkName(name); err != nil {
+		return nil, "", nil, false, err
+	}
+	hit = e.loaded.Load() && !e.reload
+	e.stats.observeCache(hit)
+	if e.metrics != nil {
+		e.metrics.ObserveCache(name, hit)
+	}
+	if !hit {
 		if e.reload {
-			e.loaded = false
+			e.loaded.Store(false)
+			e.event(ctx, slog.LevelDebug, "views: reload triggered", slog.String("template", name))
 		}
-		if err := e.Load(); err != nil {
-			return err
+		if err = e.LoadContext(ctx); err != nil {
+			return nil, "", nil, hit, err
 		}
 	}
-	tmpl := e.Templates[template]
+	tmpl, page, set, _, err = e.resolve(ctx, name)
+	return tmpl, page, set, hit, err
+}
+
+// resolve returns the template name rendered for ctx from the loaded set,
+// along with the name of the page and the template sets it was taken from.
+func (e *Engine) resolve(ctx context.Context, name string) (*template.Template, string, *templateVersion, *templateSet, error) {
+	// Loads publish a new set, the render sticks to this one
+	set := e.current.Load()
+	if set == nil {
+		return nil, "", nil, nil, fmt.Errorf("render: template %s does not exist", name)
+	}
+	pages, err := e.pageSet(ctx, set)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	templates := pages.templates
+	page, tmpl := e.localized(ctx, templates, deviceVariant(ctx, templates, alternate(ctx, templates, e.variant(ctx, name))))
 	if tmpl == nil {
-		return fmt.Errorf("render: template %s does not exist", template)
+		return nil, "", nil, nil, fmt.Errorf("render: template %s does not exist", name)
 	}
+	return tmpl, page, set, pages, nil
+}
+
+// pageSet returns the templates of set rendered for ctx, those of its
+// theme parsed with its layout.
+func (e *Engine) pageSet(ctx context.Context, set *templateVersion) (*templateSet, error) {
+	theme := e.theme(ctx)
+	pages, err := set.themed(theme)
+	layout := layoutOf(ctx)
+	if layout == "" {
+		layout = e.deviceLayout(ctx)
+	}
+	if err == nil && layout != "" && layout != e.layout {
+		pages, err = set.laidOut(theme, layout)
+	}
+	return pages, err
+}
+
+// Render will execute the template name along with the given values.
+func (e *Engine) Render(out io.Writer, template string, binding interface{}, layout ...string) error {
+	return e.RenderContext(context.Background(), out, template, binding, layout...)
+}
+
+// RenderContext will execute the template name along with the given values,
+// ctx carries the parent span of the render. The layout argument picks one
+// of the layouts added by Layouts.
+func (e *Engine) RenderContext(ctx context.Context, out io.Writer, name string, binding interface{}, layout ...string) error {
 	if len(layout) > 0 {
-		return fmt.Errorf("render: layout argument is not supported")
+		ctx = UseLayout(ctx, layout[0])
+	}
+	return e.execute(ctx, out, name, binding, false)
+}
+
+// RenderPartial will execute the template name without the layout.
+func (e *Engine) RenderPartial(out io.Writer, name string, binding interface{}) error {
+	return e.RenderPartialContext(context.Background(), out, name, binding)
+}
+
+// RenderPartialContext will execute the template name without the layout,
+// ctx carries the parent span of the render.
+func (e *Engine) RenderPartialContext(ctx context.Context, out io.Writer, name string, binding interface{}) error {
+	return e.execute(ctx, out, name, binding, true)
+}
+
+// execute runs the template name with metrics and tracing,
+// partial skips the layout.
+func (e *Engine) execute(ctx context.Context, out io.Writer, name string, binding interface{}, partial bool) error {
+	if err := e.life.enter(); err != nil {
+		return err
+	}
+	defer e.life.leave()
+	if maintenance := e.maintenanceFor(name); maintenance != "" {
+		name = maintenance
+	}
+	var span Span
+	var cw *countWriter
+	if e.tracer != nil {
+		ctx, span = e.tracer.Start(ctx, "html.Render")
+		span.SetAttribute("template", name)
+		if !partial {
+			span.SetAttribute("layout", e.layout)
+		}
+		cw = &countWriter{w: out}
+		out = cw
+	}
+	e.event(ctx, slog.LevelDebug, "views: render started", slog.String("template", name), slog.Bool("partial", partial), slog.Any("binding", redactedBinding{e, binding}))
+	start := time.Now()
+	// Panics of the funcs, hooks and file systems fail the render only
+	var page string
+	var hit bool
+	err := recovered("render: "+name, func() error {
+		tmpl, p, set, h, err := e.lookup(ctx, name)
+		page, hit = p, h
+		if err != nil {
+			return err
+		}
+		ctx = context.WithValue(ctx, versionKey{}, set)
+		if ctx.Value(selfTestKey{}) == nil {
+			e.stats.rendered.Store(page, true)
+		}
+		var data interface{}
+		if data, err = e.transform(name, binding); err == nil {
+			data = e.withGlobals(ctx, data)
+			if data, err = resolveLazy(set, tmpl, data); err == nil {
+				err = e.validateBinding(name, data)
+			}
+		}
+		if err == nil {
+			target := out
+			var processed *byteBuffer
+			if e.emails[name] || len(e.postProcessors) > 0 && !partial {
+				processed = getBuffer()
+				defer putBuffer(processed)
+				target = processed
+			}
+			if key, ok := ctx.Value(cacheKey{}).(pageKey); ok && e.pages != nil && !partial {
+				err = e.renderCached(ctx, key, tmpl, target, page, data)
+			} else {
+				err = e.executeTemplate(ctx, tmpl, target, page, data, partial)
+			}
+			if err == nil && processed != nil {
+				err = e.postProcess(ctx, out, name, processed.b, partial)
+			}
+		}
+		return err
+	})
+	e.stats.observeRender(name, err)
+	if err != nil {
+		for _, fn := range e.onRenderError {
+			fn(name, err)
+		}
+	}
+	if e.metrics != nil {
+		e.metrics.ObserveRender(name, time.Since(start), err)
+	}
+	if err != nil {
+		e.event(ctx, slog.LevelError, "views: render failed", slog.String("template", name), slog.Duration("duration", time.Since(start)), slog.Any("error", err), slog.Any("binding", redactedBinding{e, binding}))
+	} else {
+		e.event(ctx, slog.LevelDebug, "views: render finished", slog.String("template", name), slog.Duration("duration", time.Since(start)))
+	}
+	if span != nil {
+		span.SetAttribute("cache_hit", hit)
+		span.SetAttribute("bytes", cw.n)
+		span.End(err)
+	}
+	if len(e.onRender) > 0 {
+		info := RenderInfo{
+			Template: name,
+			Page:     page,
+			Partial:  partial,
+			Duration: time.Since(start),
+			Err:      err,
+		}
+		if experiment, ok := e.published().variants[name]; ok {
+			info.Variant = variantKey(ctx)
+			if _, ok := experiment[info.Variant]; !ok {
+				info.Variant = ""
+			}
+		}
+		for _, fn := range e.onRender {
+			fn(ctx, info)
+		}
+	}
+	return err
+}
+
+// executeTemplate runs tmpl, or a pooled copy bound to ctx if the
+// templates use context funcs.
+func (e *Engine) executeTemplate(ctx context.Context, tmpl *template.Template, out io.Writer, name string, binding interface{}, partial bool) error {
+	if pool := e.renderVersion(ctx).pools[tmpl]; pool != nil {
+		p, err := e.acquire(pool, tmpl)
+		if err != nil {
+			return err
+		}
+		p.state.ctx = ctx
+		// The root frame collects the keys of the fragments of the page
+		if frame, ok := ctx.Value(fragmentKey{}).(*fragmentFrame); ok {
+			frame.tmpl, frame.state = p.tmpl, p.state
+		} else if e.fragments != nil {
+			p.state.ctx = context.WithValue(ctx, fragmentKey{}, &fragmentFrame{tmpl: p.tmpl, state: p.state})
+		}
+		if names := wrappersOf(ctx); len(names) > 0 && !partial {
+			p.state.ctx = context.WithValue(p.state.ctx, wrapKey{}, &wrapFrame{tmpl: p.tmpl, names: names})
+		}
+		defer func() {
+			p.state.ctx = nil
+			pool.Put(p)
+		}()
+		tmpl = p.tmpl
+	}
+	if partial && tmpl.Lookup(name) != nil {
+		return tmpl.ExecuteTemplate(out, name, binding)
 	}
 	return tmpl.Execute(out, binding)
 }
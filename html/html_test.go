@@ -177,4 +177,50 @@ func Test_Reload(t *testing.T) {
 	if expect != result {
 		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
 	}
-}
\ No newline at end of file
+}
+
+func Test_MaxTemplateSize(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.MaxTemplateSize(64)
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	err := engine.Load()
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum size of 64 bytes") {
+		t.Fatalf("Expected size error\nResult:\n%v\n", err)
+	}
+
+	engine = NewFileSystem(http.Dir("./views"), ".html")
+	engine.MaxTemplateSize(4096)
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+}
+
+func Test_SetDirectory(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "admin", map[string]interface{}{"User": "admin"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+
+	engine.SetDirectory("./testdata/reconfigure").SetExtension(".tmpl").SetDelims("[[", "]]")
+	buf.Reset()
+	if err := engine.Render(&buf, "index", map[string]interface{}{"Title": "Moved"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<p>Moved</p>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if engine.Template("admin") != nil {
+		t.Fatalf("Expected templates of the old folder to be dropped\n")
+	}
+}
@@ -0,0 +1,93 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// Fragment is a template rendered without layout as part of a htmx response
+type Fragment struct {
+	// template name
+	Template string
+	// values passed to the template
+	Binding interface{}
+	// hx-swap-oob value, defaults to "true"
+	Swap string
+}
+
+// RenderOOB renders the main fragment followed by the out-of-band fragments.
+// The hx-swap-oob attribute is injected into the root element of each
+// out-of-band fragment, so the same partials can be used for regular swaps,
+// root elements setting it themselves keep their own.
+func (e *Engine) RenderOOB(out io.Writer, main Fragment, oob ...Fragment) error {
+	if err := e.RenderPartial(out, main.Template, main.Binding); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, fragment := range oob {
+		buf.Reset()
+		if err := e.RenderPartial(&buf, fragment.Template, fragment.Binding); err != nil {
+			return err
+		}
+		swap := fragment.Swap
+		if swap == "" {
+			swap = "true"
+		}
+		result, err := injectAttr(buf.Bytes(), "hx-swap-oob", swap)
+		if err != nil {
+			return fmt.Errorf("htmx: template %s: %v", fragment.Template, err)
+		}
+		if _, err = out.Write(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectAttr adds the attribute name="value" to the first element in src,
+// skipping comments. An element that already has the attribute is left as
+// it is.
+func injectAttr(src []byte, name, value string) ([]byte, error) {
+	for i := 0; i < len(src)-1; i++ {
+		if src[i] != '<' {
+			continue
+		}
+		if bytes.HasPrefix(src[i:], []byte("<!--")) {
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 2
+			continue
+		}
+		// Skip doctype and closing tags
+		c := src[i+1]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			continue
+		}
+		// Find the end of the tag name
+		j := i + 1
+		for j < len(src) && src[j] != ' ' && src[j] != '>' && src[j] != '/' && src[j] != '\t' && src[j] != '\n' && src[j] != '\r' {
+			j++
+		}
+		end := bytes.IndexByte(src[j:], '>')
+		if end < 0 {
+			end = len(src) - j
+		}
+		if hasAttr(src[i:j+end], name) {
+			return src, nil
+		}
+		result := make([]byte, 0, len(src)+len(name)+len(value)+4)
+		result = append(result, src[:j]...)
+		result = append(result, ' ')
+		result = append(result, name...)
+		result = append(result, `="`...)
+		result = append(result, template.HTMLEscapeString(value)...)
+		result = append(result, '"')
+		result = append(result, src[j:]...)
+		return result, nil
+	}
+	return nil, fmt.Errorf("no element to inject attribute into")
+}
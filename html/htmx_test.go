@@ -0,0 +1,66 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_RenderOOB(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	var buf bytes.Buffer
+	err := engine.RenderOOB(&buf, Fragment{
+		Template: "admin",
+		Binding: map[string]interface{}{
+			"User": "admin",
+		},
+	}, Fragment{
+		Template: "errors/404",
+		Binding: map[string]interface{}{
+			"Error": "404 Not Found!",
+		},
+	}, Fragment{
+		Template: "errors/404",
+		Binding: map[string]interface{}{
+			"Error": "Gone",
+		},
+		Swap: "outerHTML:#error",
+	})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h1>Hello, Admin!</h1><h1 hx-swap-oob="true">404 Not Found!</h1><h1 hx-swap-oob="outerHTML:#error">Gone</h1>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_InjectAttr(t *testing.T) {
+	tests := []struct {
+		src    string
+		expect string
+	}{
+		{`<div>x</div>`, `<div hx-swap-oob="true">x</div>`},
+		{`<!-- <p>note</p> --><div>x</div>`, `<!-- <p>note</p> --><div hx-swap-oob="true">x</div>`},
+		{"<!DOCTYPE html>\n<tr><td>x</td></tr>", "<!DOCTYPE html>\n<tr hx-swap-oob=\"true\"><td>x</td></tr>"},
+		{`<div hx-swap-oob="beforeend:#list">x</div>`, `<div hx-swap-oob="beforeend:#list">x</div>`},
+		{`<div class="a" HX-SWAP-OOB>x</div>`, `<div class="a" HX-SWAP-OOB>x</div>`},
+		{`<div>x</div><p hx-swap-oob="true">y</p>`, `<div hx-swap-oob="true">x</div><p hx-swap-oob="true">y</p>`},
+	}
+	for _, test := range tests {
+		result, err := injectAttr([]byte(test.src), "hx-swap-oob", "true")
+		if err != nil {
+			t.Fatalf("inject: %v\n", err)
+		}
+		if string(result) != test.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", test.expect, result)
+		}
+	}
+	if _, err := injectAttr([]byte(`<!-- <div>x</div> -->`), "hx-swap-oob", "true"); err == nil {
+		t.Fatalf("Expected error without element outside comments\n")
+	}
+}
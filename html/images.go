@@ -0,0 +1,132 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	htmlpkg "html"
+	"image"
+	_ "image/gif"  // register gif for AssetSizes
+	_ "image/jpeg" // register jpeg for AssetSizes
+	_ "image/png"  // register png for AssetSizes
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	imgTag  = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	srcAttr = regexp.MustCompile(`(?i)\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// ImageSizes returns the width and height of the image at src, ok is false
+// when they are unknown
+type ImageSizes func(src string) (width, height int, ok bool)
+
+// ImageAttributes returns a processor adding loading="lazy" and
+// decoding="async" to the <img> tags of the page, and their width and
+// height from sizes, so the browser reserves their space before they load.
+// Attributes already set on a tag are kept, an image given loading="eager"
+// stays eager. Nil sizes adds no dimensions.
+func ImageAttributes(sizes ImageSizes) PostProcessor {
+	return func(ctx context.Context, name string, html []byte) ([]byte, error) {
+		if !bytes.Contains(html, []byte("<img")) && !bytes.Contains(html, []byte("<IMG")) {
+			return html, nil
+		}
+		return imgTag.ReplaceAllFunc(html, func(tag []byte) []byte {
+			return imageAttributes(tag, sizes)
+		}), nil
+	}
+}
+
+// imageAttributes returns tag with the missing attributes added.
+func imageAttributes(tag []byte, sizes ImageSizes) []byte {
+	var attrs strings.Builder
+	if !hasAttr(tag, "loading") {
+		attrs.WriteString(` loading="lazy"`)
+	}
+	if !hasAttr(tag, "decoding") {
+		attrs.WriteString(` decoding="async"`)
+	}
+	if sizes != nil && !hasAttr(tag, "width") && !hasAttr(tag, "height") {
+		if m := srcAttr.FindSubmatch(tag); m != nil {
+			src := string(bytes.Join(m[1:], nil))
+			if width, height, ok := sizes(src); ok {
+				attrs.WriteString(` width="` + strconv.Itoa(width) + `" height="` + strconv.Itoa(height) + `"`)
+			}
+		}
+	}
+	if attrs.Len() == 0 {
+		return tag
+	}
+	end := len(tag) - 1
+	if end > 0 && tag[end-1] == '/' {
+		end--
+	}
+	for end > 0 && tag[end-1] == ' ' {
+		end--
+	}
+	out := make([]byte, 0, len(tag)+attrs.Len())
+	out = append(out, tag[:end]...)
+	out = append(out, attrs.String()...)
+	return append(out, tag[end:]...)
+}
+
+// hasAttr reports whether tag sets the attribute name.
+func hasAttr(tag []byte, name string) bool {
+	lower := bytes.ToLower(tag)
+	for i := 0; ; {
+		j := bytes.Index(lower[i:], []byte(name))
+		if j < 0 {
+			return false
+		}
+		at := i + j
+		after := at + len(name)
+		if at > 0 && isSpace(lower[at-1]) && (after == len(lower) || lower[after] == '=' || isSpace(lower[after]) || lower[after] == '>' || lower[after] == '/') {
+			return true
+		}
+		i = after
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// AssetSizes returns an ImageSizes reading the dimensions of the gif, jpeg
+// and png images of fs, the src of an image is its path in fs. Sources
+// with a scheme, protocol relative ones and data URIs are unknown. The
+// dimensions of the images found are cached by path, missing ones are
+// looked up again.
+func AssetSizes(fs http.FileSystem) ImageSizes {
+	type size struct {
+		width, height int
+	}
+	var cache sync.Map
+	return func(src string) (int, int, bool) {
+		// The attribute value is HTML escaped, e.g. &amp; in the query
+		src = htmlpkg.UnescapeString(src)
+		if strings.Contains(src, ":") || strings.HasPrefix(src, "//") {
+			return 0, 0, false
+		}
+		if i := strings.IndexAny(src, "?#"); i >= 0 {
+			src = src[:i]
+		}
+		src = "/" + strings.TrimPrefix(src, "/")
+		if s, ok := cache.Load(src); ok {
+			return s.(size).width, s.(size).height, true
+		}
+		f, err := fs.Open(src)
+		if err != nil {
+			return 0, 0, false
+		}
+		defer f.Close()
+		config, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return 0, 0, false
+		}
+		cache.Store(src, size{config.Width, config.Height})
+		return config.Width, config.Height, true
+	}
+}
@@ -0,0 +1,49 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_ImageAttributes(t *testing.T) {
+	engine := New("./testdata/images", ".html")
+	engine.PostProcess(ImageAttributes(AssetSizes(http.Dir("./testdata/images"))))
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "page", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<body><img src="/img/logo.png" alt="Logo" loading="lazy" decoding="async" width="40" height="30">` +
+		`<img src="img/logo.png?v=2" loading="eager" width="80" decoding="async"/>` +
+		`<img src="https://cdn.example.com/a.jpg" loading="lazy" decoding="async"></body>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_AssetSizes(t *testing.T) {
+	dir := t.TempDir()
+	sizes := AssetSizes(http.Dir(dir))
+	if _, _, ok := sizes("/img/a&amp;b.png"); ok {
+		t.Fatalf("Expected no size of a missing image\n")
+	}
+	logo, err := os.ReadFile("./testdata/images/img/logo.png")
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "img"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v\n", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "img", "a&b.png"), logo, 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	// misses are not cached, the escaped src names the file
+	expect := "40x30"
+	w, h, _ := sizes("/img/a&amp;b.png")
+	if result := fmt.Sprintf("%dx%d", w, h); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
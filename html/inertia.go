@@ -0,0 +1,94 @@
+//go:build !noos
+
+package html
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InertiaPage is the page object exchanged with the Inertia.js client
+type InertiaPage struct {
+	Component string                 `json:"component"`
+	Props     map[string]interface{} `json:"props"`
+	URL       string                 `json:"url"`
+	Version   string                 `json:"version"`
+}
+
+// Inertia speaks the Inertia.js protocol on top of the engine
+type Inertia struct {
+	engine Renderer
+	// template rendered on the first visit
+	root string
+	// asset version, a mismatch forces a full page reload
+	version string
+}
+
+// Inertia returns an adapter that renders the root template on the first
+// visit and answers X-Inertia requests with the page object as JSON.
+// The root template receives the serialized page object as .Page, which
+// is meant to be placed in the data-page attribute of the app element.
+func (e *Engine) Inertia(root string) *Inertia {
+	return NewInertia(e, root)
+}
+
+// NewInertia returns an Inertia adapter rendering with any Renderer.
+func NewInertia(r Renderer, root string) *Inertia {
+	return &Inertia{
+		engine: r,
+		root:   root,
+	}
+}
+
+// Version sets the asset version sent to the client.
+func (i *Inertia) Version(version string) *Inertia {
+	i.version = version
+	return i
+}
+
+// Render responds with the given component and props.
+func (i *Inertia) Render(c *fiber.Ctx, component string, props fiber.Map) error {
+	page := InertiaPage{
+		Component: component,
+		Props:     props,
+		URL:       c.OriginalURL(),
+		Version:   i.version,
+	}
+	if page.Props == nil {
+		page.Props = fiber.Map{}
+	}
+	c.Vary("X-Inertia")
+	if c.Get("X-Inertia") != "true" {
+		buf, err := json.Marshal(page)
+		if err != nil {
+			return err
+		}
+		c.Type("html", "utf-8")
+		return i.engine.RenderContext(c.UserContext(), c, i.root, fiber.Map{
+			"Page":      string(buf),
+			"Component": component,
+			"Props":     page.Props,
+		})
+	}
+	// Stale assets, ask the client to do a full visit
+	if c.Method() == http.MethodGet && c.Get("X-Inertia-Version") != i.version {
+		c.Set("X-Inertia-Location", c.OriginalURL())
+		return c.SendStatus(http.StatusConflict)
+	}
+	// Partial reload, only send requested props
+	if only := c.Get("X-Inertia-Partial-Data"); only != "" && c.Get("X-Inertia-Partial-Component") == component {
+		partial := make(fiber.Map)
+		for _, key := range strings.Split(only, ",") {
+			key = strings.TrimSpace(key)
+			if value, ok := page.Props[key]; ok {
+				partial[key] = value
+			}
+		}
+		page.Props = partial
+	}
+	c.Set("X-Inertia", "true")
+	return c.JSON(page)
+}
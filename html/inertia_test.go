@@ -0,0 +1,68 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Inertia(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	inertia := engine.Inertia("app").Version("1")
+
+	app := fiber.New()
+	app.Get("/users", func(c *fiber.Ctx) error {
+		return inertia.Render(c, "Users/Index", fiber.Map{
+			"Users": []string{"john"},
+			"Total": 1,
+		})
+	})
+
+	// First visit
+	resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><div id="app" data-page="{&#34;component&#34;:&#34;Users/Index&#34;,&#34;props&#34;:{&#34;Total&#34;:1,&#34;Users&#34;:[&#34;john&#34;]},&#34;url&#34;:&#34;/users&#34;,&#34;version&#34;:&#34;1&#34;}"></div></body></html>`
+	result := trim(string(body))
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Inertia visit
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("X-Inertia", "true")
+	req.Header.Set("X-Inertia-Version", "1")
+	req.Header.Set("X-Inertia-Partial-Data", "Total")
+	req.Header.Set("X-Inertia-Partial-Component", "Users/Index")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	expect = `{"component":"Users/Index","props":{"Total":1},"url":"/users","version":"1"}`
+	if expect != string(body) || resp.Header.Get("X-Inertia") != "true" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, body)
+	}
+
+	// Stale version
+	req = httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("X-Inertia", "true")
+	req.Header.Set("X-Inertia-Version", "0")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	if resp.StatusCode != 409 || resp.Header.Get("X-Inertia-Location") != "/users" {
+		t.Fatalf("Expected: 409\nResult: %d\n", resp.StatusCode)
+	}
+}
@@ -0,0 +1,106 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// jsonldRequired are the properties required by the schema.org types
+// checked by ValidateJSONLD
+var jsonldRequired = map[string][]string{
+	"Article":        {"headline"},
+	"NewsArticle":    {"headline"},
+	"BlogPosting":    {"headline"},
+	"Product":        {"name"},
+	"BreadcrumbList": {"itemListElement"},
+	"ListItem":       {"position"},
+}
+
+// JSONLDFunc registers {{jsonld .Schema}}, which renders a struct or map as
+// a <script type="application/ld+json"> block. With validate the Article,
+// Product and BreadcrumbList values missing required properties fail the
+// render.
+func (e *Engine) JSONLDFunc(validate bool) *Engine {
+	if !validate {
+		return e.AddFunc("jsonld", JSONLD)
+	}
+	return e.AddFunc("jsonld", func(v interface{}) (template.HTML, error) {
+		if err := ValidateJSONLD(v); err != nil {
+			return "", err
+		}
+		return JSONLD(v)
+	})
+}
+
+// JSONLD returns v marshaled in a <script type="application/ld+json">
+// block, <, > and & are escaped so the data cannot close the script.
+func JSONLD(v interface{}) (template.HTML, error) {
+	// json.Marshal escapes <, >, &, U+2028 and U+2029
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("jsonld: %w", err)
+	}
+	return template.HTML(`<script type="application/ld+json">` + string(buf) + `</script>`), nil
+}
+
+// ValidateJSONLD checks that v and the nodes it holds have a @type and
+// the properties required by Article, NewsArticle, BlogPosting, Product,
+// BreadcrumbList and ListItem. The other types are not checked.
+func ValidateJSONLD(v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("jsonld: %w", err)
+	}
+	var doc interface{}
+	if err = json.Unmarshal(buf, &doc); err != nil {
+		return fmt.Errorf("jsonld: %w", err)
+	}
+	if node, ok := doc.(map[string]interface{}); ok {
+		if _, ok := node["@context"]; !ok {
+			return fmt.Errorf("jsonld: missing @context")
+		}
+		if graph, ok := node["@graph"]; ok {
+			doc = graph
+		}
+	}
+	return validateJSONLD(doc, "")
+}
+
+// validateJSONLD checks the node v found at path.
+func validateJSONLD(v interface{}, path string) error {
+	switch v := v.(type) {
+	case []interface{}:
+		for i, item := range v {
+			if err := validateJSONLD(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		kind, _ := v["@type"].(string)
+		if path == "" && kind == "" {
+			return fmt.Errorf("jsonld: missing @type")
+		}
+		for _, prop := range jsonldRequired[kind] {
+			if value, ok := v[prop]; !ok || value == nil || value == "" {
+				if path != "" {
+					kind += " at " + path
+				}
+				return fmt.Errorf("jsonld: %s: missing %s", kind, prop)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := validateJSONLD(v[key], strings.TrimPrefix(path+"."+key, ".")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_JSONLD(t *testing.T) {
+	engine := New("./testdata/jsonld", ".html")
+	engine.JSONLDFunc(true)
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "page", map[string]interface{}{
+		"Schema": map[string]interface{}{
+			"@context": "https://schema.org",
+			"@type":    "Article",
+			"headline": "Fish & Chips</script>",
+		},
+	})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<head><script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","headline":"Fish \u0026 Chips\u003c/script\u003e"}</script></head>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	err = ValidateJSONLD(map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "BreadcrumbList",
+		"itemListElement": []map[string]interface{}{
+			{"@type": "ListItem", "position": 1, "name": "Home"},
+			{"@type": "ListItem", "name": "Shop"},
+		},
+	})
+	expectErr := "jsonld: ListItem at itemListElement[1]: missing position"
+	if err == nil || err.Error() != expectErr {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expectErr, err)
+	}
+	if err = engine.Render(&buf, "page", map[string]interface{}{
+		"Schema": map[string]interface{}{"@context": "https://schema.org", "@type": "Product"},
+	}); err == nil {
+		t.Fatalf("Expected error for a Product without name\n")
+	}
+}
@@ -0,0 +1,30 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"text/template/parse"
+)
+
+// checkLayout returns an error if the layout has no template or block
+// action the pages could fill, it would render them as empty shells.
+func (e *Engine) checkLayout(layout string, buf []byte, funcmap map[string]interface{}) error {
+	tmpl, err := template.New(layout).Delims(e.left, e.right).Funcs(funcmap).Parse(string(buf))
+	if err != nil {
+		return err
+	}
+	found := false
+	err = surround(tmpl, func(tree *parse.Tree, node parse.Node) (begin, end parse.Node, err error) {
+		if _, ok := node.(*parse.TemplateNode); ok {
+			found = true
+		}
+		return nil, nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("views: layout %s%s does not include the page, add a {{block \"content\" .}}{{end}} or {{template}} action", layout, e.extension)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package html
+
+import (
+	"testing"
+)
+
+func Test_Layout_Embed(t *testing.T) {
+	engine := New("./testdata/layout", ".html")
+	engine.Layout("layouts/shell")
+	err := engine.Load()
+	expect := `views: layout layouts/shell.html does not include the page, add a {{block "content" .}}{{end}} or {{template}} action`
+	if err == nil || err.Error() != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expect, err)
+	}
+}
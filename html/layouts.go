@@ -0,0 +1,52 @@
+package html
+
+import (
+	"context"
+	"fmt"
+)
+
+// layoutKey is the context key of the layout of a render
+type layoutKey struct{}
+
+// Layouts adds layouts the pages are parsed with along with the one set by
+// Layout, e.g. "layouts/print", so a render can pick one with UseLayout
+// or the layout argument of Render. Alternate layouts render the views of
+// the selected theme too, which may override the layout file itself.
+func (e *Engine) Layouts(names ...string) *Engine {
+	e.mutex.Lock()
+	e.layouts = append(e.layouts, names...)
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// UseLayout returns a copy of ctx rendering with the layout name, which
+// must be the default layout or one added by Layouts.
+func UseLayout(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, layoutKey{}, name)
+}
+
+// layoutOf returns the layout chosen for the render of ctx, empty for the
+// default layout.
+func layoutOf(ctx context.Context) string {
+	name, _ := ctx.Value(layoutKey{}).(string)
+	return name
+}
+
+// laidOut returns the templates of theme parsed with layout.
+func (v *templateVersion) laidOut(theme, layout string) (*templateSet, error) {
+	set := v.layoutSets[layoutSetKey(theme, layout)]
+	if set == nil {
+		return nil, fmt.Errorf("render: layout %s does not exist", layout)
+	}
+	return set, nil
+}
+
+// layoutSetKey returns the key of the templates of theme parsed with
+// layout, the views folder if theme is empty.
+func layoutSetKey(theme, layout string) string {
+	if theme == "" {
+		return layout
+	}
+	return theme + "\x00" + layout
+}
@@ -0,0 +1,164 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"text/template/parse"
+)
+
+// Lazy is a binding value computed only if the template uses its key, e.g.
+// sidebar data a handler passes to every page. Bindings may also hold a
+// func() (interface{}, error) for the same effect.
+type Lazy func() (interface{}, error)
+
+// lazyRefs are the binding keys referenced by a template
+type lazyRefs struct {
+	keys map[string]bool
+	// the whole binding is used, e.g. passed to a func
+	all bool
+}
+
+// resolveLazy returns binding with the lazy values of the keys
+// referenced by tmpl computed, once per render. The keys are read from the
+// templates, those inside a branch that does not run are computed too.
+// Only the values of map[string]interface{} and Map bindings are
+// resolved.
+func resolveLazy(set *templateVersion, tmpl *template.Template, binding interface{}) (interface{}, error) {
+	data, ok := bindingMap(binding)
+	if !ok {
+		return binding, nil
+	}
+	var refs *lazyRefs
+	var resolved map[string]interface{}
+	for key, value := range data {
+		var fn func() (interface{}, error)
+		switch v := value.(type) {
+		case Lazy:
+			fn = v
+		case func() (interface{}, error):
+			fn = v
+		default:
+			continue
+		}
+		if refs == nil {
+			refs = set.templateRefs(tmpl)
+		}
+		if !refs.all && !refs.keys[key] {
+			continue
+		}
+		if resolved == nil {
+			// The handler's map is left as is
+			resolved = make(map[string]interface{}, len(data))
+			for k, v := range data {
+				resolved[k] = v
+			}
+		}
+		v, err := fn()
+		if err != nil {
+			return nil, fmt.Errorf("render: binding %s: %v", key, err)
+		}
+		resolved[key] = v
+	}
+	if resolved == nil {
+		return binding, nil
+	}
+	return resolved, nil
+}
+
+// templateRefs returns the keys referenced by tmpl and the templates it
+// defines.
+func (v *templateVersion) templateRefs(tmpl *template.Template) *lazyRefs {
+	if refs, ok := v.lazyRefs.Load(tmpl); ok {
+		return refs.(*lazyRefs)
+	}
+	refs := &lazyRefs{keys: make(map[string]bool)}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			refs.list(t.Tree.Root)
+		}
+	}
+	v.lazyRefs.Store(tmpl, refs)
+	return refs
+}
+
+func (r *lazyRefs) list(list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			// {{$x := .}} only names the dot
+			r.pipe(n.Pipe, len(n.Pipe.Decl) > 0)
+		case *parse.IfNode:
+			r.pipe(n.Pipe, false)
+			r.list(n.List)
+			r.list(n.ElseList)
+		case *parse.RangeNode:
+			// Ranging over the binding reads all of its values
+			r.pipe(n.Pipe, false)
+			r.list(n.List)
+			r.list(n.ElseList)
+		case *parse.WithNode:
+			r.pipe(n.Pipe, true)
+			r.list(n.List)
+			r.list(n.ElseList)
+		case *parse.TemplateNode:
+			r.pipe(n.Pipe, true)
+		case *parse.ListNode:
+			r.list(n)
+		}
+	}
+}
+
+// pipe adds the keys of pipe, passes tells whether a lone dot or variable
+// only becomes the dot of a block, whose references are read as well.
+func (r *lazyRefs) pipe(pipe *parse.PipeNode, passes bool) {
+	if pipe == nil {
+		return
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch n := arg.(type) {
+			case *parse.DotNode:
+				if !passes || len(pipe.Cmds) > 1 || len(cmd.Args) > 1 {
+					r.all = true
+				}
+			case *parse.VariableNode:
+				if len(n.Ident) == 1 && (!passes || len(pipe.Cmds) > 1 || len(cmd.Args) > 1) {
+					r.all = true
+				}
+				r.field(n.Ident[1:])
+			default:
+				r.arg(arg)
+			}
+		}
+	}
+}
+
+func (r *lazyRefs) arg(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		r.field(n.Ident)
+	case *parse.ChainNode:
+		r.arg(n.Node)
+		r.field(n.Field)
+	case *parse.PipeNode:
+		r.pipe(n, false)
+	case *parse.DotNode:
+		r.all = true
+	case *parse.VariableNode:
+		if len(n.Ident) == 1 {
+			r.all = true
+		}
+		r.field(n.Ident[1:])
+	}
+}
+
+// field adds the idents of a field chain, nested fields are added as well
+// since the dot of a block may be the binding.
+func (r *lazyRefs) field(idents []string) {
+	for _, ident := range idents {
+		r.keys[ident] = true
+	}
+}
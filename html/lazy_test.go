@@ -0,0 +1,42 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_LazyBinding(t *testing.T) {
+	engine := New("./testdata/lazy", ".html")
+	calls := map[string]int{}
+	lazy := func(key string, value interface{}) Lazy {
+		return func() (interface{}, error) {
+			calls[key]++
+			return value, nil
+		}
+	}
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "page", map[string]interface{}{
+		"Title":   lazy("Title", "Hello"),
+		"User":    func() (interface{}, error) { return map[string]string{"Name": "Ann"}, nil },
+		"Sidebar": lazy("Sidebar", "expensive"),
+	})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h1>Hello</h1><p>Ann</p>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if calls["Title"] != 1 || calls["Sidebar"] != 0 {
+		t.Fatalf("Expected Title computed once and Sidebar never, got %v\n", calls)
+	}
+
+	err = engine.Render(&buf, "page", map[string]interface{}{
+		"Title": Lazy(func() (interface{}, error) { return nil, errors.New("timeout") }),
+	})
+	expect = `render: binding Title: timeout`
+	if err == nil || err.Error() != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expect, err)
+	}
+}
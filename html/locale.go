@@ -0,0 +1,119 @@
+package html
+
+import (
+	"context"
+	"html/template"
+	"strings"
+)
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx selecting the locale, such as fr-CA,
+// for the renders using it.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale returns the locale selected by ctx.
+func Locale(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// DefaultLocale sets the locale used when the render context selects none,
+// it also ends every fallback chain.
+func (e *Engine) DefaultLocale(locale string) *Engine {
+	e.mutex.Lock()
+	e.defaultLocale = locale
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// LocaleFallback sets the locales tried after locale, replacing the default
+// chain made of its parent locales: fr-CA falls back to fr.
+func (e *Engine) LocaleFallback(locale string, fallbacks ...string) *Engine {
+	e.mutex.Lock()
+	if e.fallbacks == nil {
+		e.fallbacks = make(map[string][]string)
+	}
+	e.fallbacks[locale] = fallbacks
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// localeChain returns the locales tried for the locale of ctx, in order.
+func (e *Engine) localeChain(ctx context.Context) []string {
+	s := e.published()
+	locale := Locale(ctx)
+	if locale == "" {
+		locale = s.defaultLocale
+	}
+	if locale == "" {
+		return nil
+	}
+	chain := []string{locale}
+	fallbacks, ok := s.fallbacks[locale]
+	if ok {
+		chain = append(chain, fallbacks...)
+	} else {
+		// fr-CA -> fr
+		for parent := locale; strings.LastIndex(parent, "-") > 0; {
+			parent = parent[:strings.LastIndex(parent, "-")]
+			chain = append(chain, parent)
+		}
+	}
+	if s.defaultLocale != "" {
+		for _, l := range chain {
+			if l == s.defaultLocale {
+				return chain
+			}
+		}
+		chain = append(chain, s.defaultLocale)
+	}
+	return chain
+}
+
+// localized returns the name of the locale variant of the template name
+// (index.fr-CA, index.fr) and its template, or the template itself.
+func (e *Engine) localized(ctx context.Context, templates map[string]*template.Template, name string) (string, *template.Template) {
+	for _, locale := range e.localeChain(ctx) {
+		if tmpl := templates[name+"."+locale]; tmpl != nil {
+			return name + "." + locale, tmpl
+		}
+	}
+	return name, templates[name]
+}
+
+// Translator looks up translated messages, ok is false if key has no
+// translation in locale.
+type Translator interface {
+	Translate(locale, key string, args ...interface{}) (message string, ok bool)
+}
+
+// TranslatorFunc adapts a func to the Translator interface
+type TranslatorFunc func(locale, key string, args ...interface{}) (string, bool)
+
+// Translate calls f(locale, key, args...).
+func (f TranslatorFunc) Translate(locale, key string, args ...interface{}) (string, bool) {
+	return f(locale, key, args...)
+}
+
+// Translator sets the translator and registers the t func, which looks up
+// {{t "key" args...}} through the same fallback chain as the templates
+// and returns the key if no locale has a translation.
+func (e *Engine) Translator(t Translator) *Engine {
+	e.translator = t
+	return e.AddContextFunc("t", e.translate)
+}
+
+// translate returns the message key in the locale of ctx.
+func (e *Engine) translate(ctx context.Context, key string, args ...interface{}) string {
+	for _, locale := range e.localeChain(ctx) {
+		if message, ok := e.translator.Translate(locale, key, args...); ok {
+			return message
+		}
+	}
+	return key
+}
@@ -0,0 +1,44 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+func Test_Locale(t *testing.T) {
+	messages := map[string]map[string]string{
+		"en": {"hello": "Hello, %s!"},
+		"fr": {"hello": "Bonjour, %s !"},
+	}
+	engine := New("./testdata/locale", ".html")
+	engine.DefaultLocale("en")
+	engine.LocaleFallback("pt-BR", "pt", "fr")
+	engine.Translator(TranslatorFunc(func(locale, key string, args ...interface{}) (string, bool) {
+		message, ok := messages[locale][key]
+		return fmt.Sprintf(message, args...), ok
+	}))
+
+	for _, tc := range []struct {
+		locale string
+		expect string
+	}{
+		{"", `<p>Hello, John!</p>`},
+		{"fr-CA", `<p lang="fr">Bonjour, John !</p>`},
+		{"pt-BR", `<p lang="fr">Bonjour, John !</p>`},
+		{"de", `<p>Hello, John!</p>`},
+	} {
+		var buf bytes.Buffer
+		ctx := WithLocale(context.Background(), tc.locale)
+		if err := engine.RenderContext(ctx, &buf, "index", map[string]interface{}{
+			"Name": "John",
+		}); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		result := trim(buf.String())
+		if tc.expect != result {
+			t.Fatalf("Locale %s\nExpected:\n%s\nResult:\n%s\n", tc.locale, tc.expect, result)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package html
+
+import "strings"
+
+// MaintenanceMode if set to true renders template in place of every
+// template but the ones allowed by MaintenanceAllow, and Respond answers
+// with 503 Service Unavailable. It can be flipped at runtime.
+func (e *Engine) MaintenanceMode(enabled bool, template string) *Engine {
+	e.mutex.Lock()
+	e.maintenance = ""
+	if enabled {
+		e.maintenance = template
+	}
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// MaintenanceAllow keeps rendering the named templates in maintenance
+// mode, names ending with / allow every template below them, e.g. "admin/".
+func (e *Engine) MaintenanceAllow(names ...string) *Engine {
+	e.mutex.Lock()
+	e.maintenanceAllow = append(e.maintenanceAllow, names...)
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// maintenanceFor returns the template rendered in place of name, empty
+// outside of maintenance mode or if name is allowed.
+func (e *Engine) maintenanceFor(name string) string {
+	s := e.published()
+	if s.maintenance == "" || name == s.maintenance {
+		return ""
+	}
+	for _, allowed := range s.maintenanceAllow {
+		if name == allowed || strings.HasSuffix(allowed, "/") && strings.HasPrefix(name, allowed) {
+			return ""
+		}
+	}
+	return s.maintenance
+}
@@ -0,0 +1,39 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_MaintenanceMode(t *testing.T) {
+	engine := New("./testdata/maintenance", ".html")
+	engine.MaintenanceAllow("admin/")
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return engine.Respond(c, "page", nil)
+	})
+	app.Get("/admin", func(c *fiber.Ctx) error {
+		return engine.Respond(c, "admin/page", nil)
+	})
+	check := func(path string, status int, expect string) {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if result := trim(string(body)); result != expect || resp.StatusCode != status {
+			t.Fatalf("Expected %d:\n%s\nResult %d:\n%s\n", status, expect, resp.StatusCode, result)
+		}
+	}
+	check("/", 200, "Page")
+	engine.MaintenanceMode(true, "maintenance")
+	check("/", 503, "Back soon")
+	check("/admin", 200, "Admin")
+	engine.MaintenanceMode(false, "")
+	check("/", 200, "Page")
+}
@@ -0,0 +1,63 @@
+package html
+
+import (
+	"reflect"
+)
+
+// Merge returns the keys of maps in a new map, a key set by a later map
+// takes precedence over the earlier ones, nil maps are skipped. The
+// engine layers bindings the same way: globals, then tenant globals,
+// then the binding of the handler.
+func Merge(maps ...Map) Map {
+	n := 0
+	for _, m := range maps {
+		n += len(m)
+	}
+	result := make(Map, n)
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// MergeStruct returns base with the exported fields of the struct v set
+// over its keys, e.g. a page view model over shared data. Embedded
+// structs add their fields, fields tagged view:"-" are skipped. v may
+// also be a map with string keys, nil adds nothing.
+func MergeStruct(base Map, v interface{}) Map {
+	result := Merge(base)
+	mergeValue(result, reflect.ValueOf(v))
+	return result
+}
+
+// mergeValue sets the keys or fields of v into dst.
+func mergeValue(dst Map, v reflect.Value) {
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			dst[iter.Key().String()] = iter.Value().Interface()
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get("view") == "-" {
+				continue
+			}
+			if f.Anonymous && indirect(v.Field(i)).Kind() == reflect.Struct {
+				mergeValue(dst, v.Field(i))
+				continue
+			}
+			if f.IsExported() {
+				dst[f.Name] = v.Field(i).Interface()
+			}
+		}
+	}
+}
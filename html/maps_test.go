@@ -0,0 +1,35 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+)
+
+type mergeBase struct {
+	Site string
+}
+
+type mergePage struct {
+	mergeBase
+	Title  string
+	Secret string `view:"-"`
+	hidden string
+}
+
+func Test_MergeMaps(t *testing.T) {
+	result := Merge(Map{"Title": "Global", "Site": "Shop"}, nil, Map{"Title": "Page"})
+	expect := `map[Site:Shop Title:Page]`
+	if s := fmt.Sprint(result); s != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, s)
+	}
+
+	base := Map{"Title": "Global", "User": "ann"}
+	result = MergeStruct(base, &mergePage{mergeBase: mergeBase{Site: "Blog"}, Title: "Post", Secret: "x", hidden: "y"})
+	expect = `map[Site:Blog Title:Post User:ann]`
+	if s := fmt.Sprint(result); s != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, s)
+	}
+	if base["Title"] != "Global" {
+		t.Fatalf("Expected base to be left as is\n")
+	}
+}
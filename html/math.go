@@ -0,0 +1,186 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// mathFuncs are the funcs registered by MathFuncs
+var mathFuncs = template.FuncMap{
+	"add": func(a, b interface{}, more ...interface{}) (interface{}, error) {
+		return fold("add", append([]interface{}{a, b}, more...), addInt, func(x, y float64) float64 { return x + y })
+	},
+	"sub": func(a, b interface{}) (interface{}, error) {
+		return fold("sub", []interface{}{a, b}, subInt, func(x, y float64) float64 { return x - y })
+	},
+	"mul": func(a, b interface{}, more ...interface{}) (interface{}, error) {
+		return fold("mul", append([]interface{}{a, b}, more...), mulInt, func(x, y float64) float64 { return x * y })
+	},
+	"div": func(a, b interface{}) (interface{}, error) {
+		if _, y, _, err := number("div", b); err != nil {
+			return nil, err
+		} else if y == 0 {
+			return nil, fmt.Errorf("div: division by zero")
+		}
+		return fold("div", []interface{}{a, b}, func(x, y int64) (int64, error) {
+			if x == math.MinInt64 && y == -1 {
+				return 0, fmt.Errorf("div: integer overflow")
+			}
+			return x / y, nil
+		}, func(x, y float64) float64 { return x / y })
+	},
+	"mod": func(a, b interface{}) (interface{}, error) {
+		return fold("mod", []interface{}{a, b}, func(x, y int64) (int64, error) {
+			if y == 0 {
+				return 0, fmt.Errorf("mod: division by zero")
+			}
+			return x % y, nil
+		}, math.Mod)
+	},
+	"min": func(a interface{}, more ...interface{}) (interface{}, error) {
+		return fold("min", append([]interface{}{a}, more...), func(x, y int64) (int64, error) {
+			if y < x {
+				return y, nil
+			}
+			return x, nil
+		}, math.Min)
+	},
+	"max": func(a interface{}, more ...interface{}) (interface{}, error) {
+		return fold("max", append([]interface{}{a}, more...), func(x, y int64) (int64, error) {
+			if y > x {
+				return y, nil
+			}
+			return x, nil
+		}, math.Max)
+	},
+	"round":       round,
+	"formatFloat": formatFloat,
+}
+
+// MathFuncs registers the arithmetic funcs for presentation values, such
+// as column widths and row totals:
+//   - {{add 1 2 3}}, {{sub .Total .Paid}}, {{mul .Price .Qty}}
+//   - {{div .Width 3}}, {{mod $i 2}}, {{min .A .B}}, {{max .A .B .C}}
+//   - {{round .Ratio 2}} rounds half away from zero
+//   - {{formatFloat .Price 2}} formats with fixed decimals, never in
+//     exponent notation
+//
+// They take any integer or float values, the result is an int64 when all
+// operands are integers and a float64 otherwise. Dividing by zero, integer
+// results or unsigned operands out of the int64 range and formatting NaN
+// and infinities fail the render.
+func (e *Engine) MathFuncs() *Engine {
+	for name, fn := range mathFuncs {
+		e.AddFunc(name, fn)
+	}
+	return e
+}
+
+// fold applies the int op, or the float op if one of values is a float, from
+// left to right.
+func fold(name string, values []interface{}, intOp func(x, y int64) (int64, error), floatOp func(x, y float64) float64) (interface{}, error) {
+	floats := make([]float64, len(values))
+	ints := make([]int64, len(values))
+	integers := true
+	for i, v := range values {
+		n, f, isInt, err := number(name, v)
+		if err != nil {
+			return nil, err
+		}
+		ints[i], floats[i] = n, f
+		integers = integers && isInt
+	}
+	if integers {
+		result := ints[0]
+		for _, y := range ints[1:] {
+			var err error
+			if result, err = intOp(result, y); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	result := floats[0]
+	for _, y := range floats[1:] {
+		result = floatOp(result, y)
+	}
+	return result, nil
+}
+
+// addInt returns x + y, or an error if it overflows.
+func addInt(x, y int64) (int64, error) {
+	s := x + y
+	if (s > x) != (y > 0) {
+		return 0, fmt.Errorf("add: integer overflow")
+	}
+	return s, nil
+}
+
+// subInt returns x - y, or an error if it overflows.
+func subInt(x, y int64) (int64, error) {
+	d := x - y
+	if (d < x) != (y > 0) {
+		return 0, fmt.Errorf("sub: integer overflow")
+	}
+	return d, nil
+}
+
+// mulInt returns x * y, or an error if it overflows.
+func mulInt(x, y int64) (int64, error) {
+	if x == 0 || y == 0 {
+		return 0, nil
+	}
+	p := x * y
+	if p/y != x || x == -1 && y == math.MinInt64 || y == -1 && x == math.MinInt64 {
+		return 0, fmt.Errorf("mul: integer overflow")
+	}
+	return p, nil
+}
+
+// number returns v as an int64 and a float64, and whether it is an integer.
+func number(name string, v interface{}) (int64, float64, bool, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), float64(rv.Int()), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, 0, false, fmt.Errorf("%s: %d is out of the int64 range", name, rv.Uint())
+		}
+		return int64(rv.Uint()), float64(rv.Uint()), true, nil
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float()), rv.Float(), false, nil
+	}
+	return 0, 0, false, fmt.Errorf("%s: %T is not a number", name, v)
+}
+
+// round returns v rounded to places decimals, none by default.
+func round(v interface{}, places ...int) (float64, error) {
+	_, f, _, err := number("round", v)
+	if err != nil {
+		return 0, err
+	}
+	scale := 1.0
+	if len(places) > 0 {
+		scale = math.Pow10(places[0])
+	}
+	return math.Round(f*scale) / scale, nil
+}
+
+// formatFloat returns v formatted with decimals digits after the point.
+func formatFloat(v interface{}, decimals int) (string, error) {
+	_, f, _, err := number("formatFloat", v)
+	if err != nil {
+		return "", err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("formatFloat: %v is not finite", f)
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	return strconv.FormatFloat(f, 'f', decimals, 64), nil
+}
@@ -0,0 +1,55 @@
+package html
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func Test_MathFuncs(t *testing.T) {
+	engine := New("./testdata/math", ".html")
+	engine.MathFuncs()
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "table", map[string]interface{}{"Total": 10, "Paid": uint8(4), "Price": 1.5})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<p>6 6 4.5 33 2.5 1 2 3 2.7 1.50</p>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	if _, err := mathFuncs["div"].(func(a, b interface{}) (interface{}, error))(1, 0); err == nil {
+		t.Fatalf("Expected error for a division by zero\n")
+	}
+	if _, err := formatFloat(math.NaN(), 2); err == nil {
+		t.Fatalf("Expected error for NaN\n")
+	}
+	if _, err := round("1.5"); err == nil {
+		t.Fatalf("Expected error for a string\n")
+	}
+
+	overflows := []struct {
+		name string
+		a, b interface{}
+	}{
+		{"add", int64(math.MaxInt64), 1},
+		{"sub", int64(math.MinInt64), 1},
+		{"mul", int64(math.MaxInt64/2 + 1), 2},
+		{"mul", int64(math.MinInt64), -1},
+		{"div", int64(math.MinInt64), -1},
+		{"add", uint64(math.MaxInt64 + 1), 0},
+	}
+	for _, tt := range overflows {
+		var err error
+		switch fn := mathFuncs[tt.name].(type) {
+		case func(a, b interface{}, more ...interface{}) (interface{}, error):
+			_, err = fn(tt.a, tt.b)
+		case func(a, b interface{}) (interface{}, error):
+			_, err = fn(tt.a, tt.b)
+		}
+		if err == nil {
+			t.Fatalf("Expected error for %s %v %v\n", tt.name, tt.a, tt.b)
+		}
+	}
+}
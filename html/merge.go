@@ -0,0 +1,111 @@
+package html
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MergePolicy decides which template wins when merged engines share a name
+type MergePolicy int
+
+const (
+	// MergeError fails the load when a template name is taken
+	MergeError MergePolicy = iota
+	// MergeKeep keeps the template already in the engine
+	MergeKeep
+	// MergeReplace replaces it with the template of the merged engine
+	MergeReplace
+)
+
+// mergedEngine is an engine merged into another
+type mergedEngine struct {
+	engine *Engine
+	policy MergePolicy
+}
+
+// mergeMutex serializes changes to the merge graph so two engines can't be
+// merged into each other at the same time.
+var mergeMutex sync.Mutex
+
+// Merge adds the templates of other to the engine, e.g. the embedded views
+// of each module of an app. Merged templates keep the layout and funcs of
+// their own engine and are merged again each time the engine loads, policy
+// decides what happens to names both engines define.
+func (e *Engine) Merge(other *Engine, policy MergePolicy) error {
+	if other == nil || other == e {
+		return errors.New("merge: invalid engine")
+	}
+	mergeMutex.Lock()
+	// Loading would wait on the engine's own lock through the cycle
+	if other.merges(e) {
+		mergeMutex.Unlock()
+		return errors.New("merge: engines would merge each other")
+	}
+	e.mutex.Lock()
+	e.merged = append(e.merged, mergedEngine{engine: other, policy: policy})
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	mergeMutex.Unlock()
+	if err := e.Load(); err != nil {
+		e.mutex.Lock()
+		e.merged = e.merged[:len(e.merged)-1]
+		e.loaded.Store(false)
+		e.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// merges reports whether target is merged into the engine, directly or
+// through the engines merged into it.
+func (e *Engine) merges(target *Engine) bool {
+	seen := map[*Engine]bool{}
+	queue := []*Engine{e}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == target {
+			return true
+		}
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		next.mutex.RLock()
+		for _, m := range next.merged {
+			queue = append(queue, m.engine)
+		}
+		next.mutex.RUnlock()
+	}
+	return false
+}
+
+// mergeInto adds the templates of the merged engines to set, their pools
+// to those of the load l.
+func (e *Engine) mergeInto(l *templateLoad, set *templateSet) error {
+	for _, m := range e.merged {
+		if err := m.engine.Load(); err != nil {
+			return fmt.Errorf("merge: %v", err)
+		}
+		m.engine.mutex.RLock()
+		for name, tmpl := range m.engine.Templates {
+			if _, ok := set.templates[name]; ok {
+				if m.policy == MergeKeep {
+					continue
+				}
+				if m.policy == MergeError {
+					m.engine.mutex.RUnlock()
+					return fmt.Errorf("merge: template %s already exists", name)
+				}
+			}
+			set.templates[name] = tmpl
+			set.preloads[name] = m.engine.preloads[name]
+			if pool := m.engine.pools[tmpl]; pool != nil {
+				l.pools[tmpl] = pool
+			}
+		}
+		m.engine.mutex.RUnlock()
+	}
+	return nil
+}
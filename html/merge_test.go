@@ -0,0 +1,85 @@
+package html
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_Merge(t *testing.T) {
+	newEngines := func() (*Engine, *Engine) {
+		engine := New("./views", ".html")
+		engine.Layout("layouts/main")
+		engine.AddFunc("isAdmin", func(user string) bool {
+			return user == "admin"
+		})
+		blog := New("./testdata/merge/blog", ".html")
+		blog.Layout("layouts/blog")
+		blog.AddFunc("upper", strings.ToUpper)
+		return engine, blog
+	}
+
+	// Merged templates keep their own layout and funcs
+	engine, blog := newEngines()
+	if err := engine.Merge(blog, MergeKeep); err != nil {
+		t.Fatalf("merge: %v\n", err)
+	}
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "post", map[string]interface{}{"Title": "Hello"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<article><h1>HELLO</h1></article>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	buf.Reset()
+	if err := engine.Render(&buf, "index", map[string]interface{}{"Title": "Hello"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := trim(buf.String()); !strings.Contains(result, "<h1>Hello</h1>") {
+		t.Fatalf("Expected the engine's own index\nResult:\n%s\n", result)
+	}
+
+	// Replace
+	engine, blog = newEngines()
+	if err := engine.Merge(blog, MergeReplace); err != nil {
+		t.Fatalf("merge: %v\n", err)
+	}
+	buf.Reset()
+	if err := engine.Render(&buf, "index", map[string]interface{}{"Title": "Hello"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<article><p>Hello</p></article>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Collisions fail and leave the engine as it was
+	engine, blog = newEngines()
+	if err := engine.Merge(blog, MergeError); err == nil || !strings.Contains(err.Error(), "index") {
+		t.Fatalf("Expected collision error, got %v\n", err)
+	}
+	if err := engine.Render(&buf, "post", nil); err == nil {
+		t.Fatalf("Expected post not to be merged\n")
+	}
+	if err := engine.Merge(engine, MergeKeep); err == nil {
+		t.Fatalf("Expected error merging the engine into itself\n")
+	}
+
+	// Cycles are rejected, directly or through another engine
+	engine, blog = newEngines()
+	if err := engine.Merge(blog, MergeKeep); err != nil {
+		t.Fatalf("merge: %v\n", err)
+	}
+	if err := blog.Merge(engine, MergeKeep); err == nil {
+		t.Fatalf("Expected error merging the engines into each other\n")
+	}
+	shop := New("./testdata/merge/blog", ".html")
+	shop.AddFunc("upper", strings.ToUpper)
+	if err := blog.Merge(shop, MergeReplace); err != nil {
+		t.Fatalf("merge: %v\n", err)
+	}
+	if err := shop.Merge(engine, MergeKeep); err == nil {
+		t.Fatalf("Expected error merging through another engine\n")
+	}
+}
@@ -0,0 +1,18 @@
+package html
+
+import "time"
+
+// Metrics receives measurements from the engine, implementations are
+// expected to forward them to a metrics system such as Prometheus.
+// Methods may be called concurrently.
+type Metrics interface {
+	// ObserveRender is called after each render of the template name,
+	// err is the render error if any
+	ObserveRender(name string, duration time.Duration, err error)
+	// ObserveLoad is called after the templates are parsed, which
+	// happens on every render in reload mode
+	ObserveLoad(duration time.Duration, err error)
+	// ObserveCache is called when the template name is looked up,
+	// hit is false if the templates had to be parsed first
+	ObserveCache(name string, hit bool)
+}
@@ -0,0 +1,68 @@
+package html
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	mutex   sync.Mutex
+	renders map[string]int
+	errors  int
+	loads   int
+	hits    int
+	misses  int
+}
+
+func (m *testMetrics) ObserveRender(name string, duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.renders[name]++
+	if err != nil {
+		m.errors++
+	}
+}
+
+func (m *testMetrics) ObserveLoad(duration time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.loads++
+}
+
+func (m *testMetrics) ObserveCache(name string, hit bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if hit {
+		m.hits++
+	} else {
+		m.misses++
+	}
+}
+
+func Test_Metrics(t *testing.T) {
+	metrics := &testMetrics{renders: make(map[string]int)}
+	engine := New("./views", ".html")
+	engine.Metrics(metrics)
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	var buf bytes.Buffer
+	for i := 0; i < 2; i++ {
+		if err := engine.Render(&buf, "home", nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+	}
+	if err := engine.Render(&buf, "missing", nil); err == nil {
+		t.Fatalf("Expected error for missing template\n")
+	}
+
+	if metrics.renders["home"] != 2 || metrics.renders["missing"] != 1 {
+		t.Fatalf("Expected:\n2 home, 1 missing\nResult:\n%v\n", metrics.renders)
+	}
+	if metrics.errors != 1 || metrics.loads != 1 || metrics.hits != 2 || metrics.misses != 1 {
+		t.Fatalf("Expected:\n1 error, 1 load, 2 hits, 1 miss\nResult:\n%d errors, %d loads, %d hits, %d misses\n", metrics.errors, metrics.loads, metrics.hits, metrics.misses)
+	}
+}
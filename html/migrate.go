@@ -0,0 +1,127 @@
+package html
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template/parse"
+)
+
+// MigrationNote is a construct of gofiber/template/html views that works
+// differently with this engine
+type MigrationNote struct {
+	// file:line:col of the construct
+	Location string
+	Message  string
+}
+
+func (n MigrationNote) String() string {
+	return n.Location + ": " + n.Message
+}
+
+// Migrate lists what needs attention when moving the views of fsys from
+// gofiber/template/html: layouts including the page with the embed func
+// (cfg.Layout is its name, embed by default), which only Compat supports,
+// and includes of other views, which fail since every view is parsed on
+// its own along with the layout.
+func Migrate(fsys fs.FS, cfg AnalyzeConfig) ([]MigrationNote, error) {
+	embed := cfg.Layout
+	if embed == "" {
+		embed = "embed"
+	}
+	cfg.Layout = ""
+	if cfg.Extension == "" {
+		cfg.Extension = ".html"
+	}
+	if cfg.Delims[0] == "" {
+		cfg.Delims = [2]string{"{{", "}}"}
+	}
+	views := make(map[string]bool)
+	var notes []MigrationNote
+	err := fs.WalkDir(fsys, ".", func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(file) != cfg.Extension {
+			return err
+		}
+		name := strings.TrimSuffix(file, cfg.Extension)
+		views[name] = true
+		buf, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		trees, err := analyzeParse(name, string(buf), cfg.Delims)
+		if err != nil {
+			return err
+		}
+		for _, tree := range trees {
+			if node := findCall(tree.Root, embed); node != nil {
+				notes = append(notes, MigrationNote{
+					Location: location(tree, node),
+					Message: fmt.Sprintf("layout %s includes the page with {{%s}}, render it with Compat or set Layout(%q) with a {{block \"content\" .}}{{end}} in place of {{%s}} and the pages in {{define \"content\"}}",
+						name, embed, name, embed),
+				})
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := Analyze(fsys, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range analysis.Missing {
+		if views[issue.Name] {
+			notes = append(notes, MigrationNote{
+				Location: issue.Location,
+				Message:  fmt.Sprintf("includes the view %s, views are parsed separately, define it in the layout instead", issue.Name),
+			})
+		}
+	}
+	return notes, nil
+}
+
+// findCall returns the first command of list calling the func name.
+func findCall(list *parse.ListNode, name string) parse.Node {
+	if list == nil {
+		return nil
+	}
+	for _, node := range list.Nodes {
+		var pipes []*parse.PipeNode
+		var lists []*parse.ListNode
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			pipes = append(pipes, n.Pipe)
+		case *parse.TemplateNode:
+			pipes = append(pipes, n.Pipe)
+		case *parse.IfNode:
+			pipes, lists = append(pipes, n.Pipe), append(lists, n.List, n.ElseList)
+		case *parse.RangeNode:
+			pipes, lists = append(pipes, n.Pipe), append(lists, n.List, n.ElseList)
+		case *parse.WithNode:
+			pipes, lists = append(pipes, n.Pipe), append(lists, n.List, n.ElseList)
+		case *parse.ListNode:
+			lists = append(lists, n)
+		}
+		for _, pipe := range pipes {
+			if pipe == nil {
+				continue
+			}
+			for _, cmd := range pipe.Cmds {
+				for _, arg := range cmd.Args {
+					if ident, ok := arg.(*parse.IdentifierNode); ok && ident.Ident == name {
+						return cmd
+					}
+				}
+			}
+		}
+		for _, l := range lists {
+			if found := findCall(l, name); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
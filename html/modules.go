@@ -0,0 +1,171 @@
+package html
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// modules are the views registered by RegisterModule
+var modules struct {
+	sync.RWMutex
+	views map[string]fs.FS
+}
+
+// RegisterModule registers the views of a library module under name, e.g.
+// html.RegisterModule("authkit", authkit.Views) from the init of the
+// library, for the engines of the apps to mount with MountModule.
+// Registering a name again replaces its views.
+func RegisterModule(name string, views fs.FS) {
+	modules.Lock()
+	if modules.views == nil {
+		modules.views = make(map[string]fs.FS)
+	}
+	modules.views[name] = views
+	modules.Unlock()
+}
+
+// Modules returns the sorted names of the registered modules.
+func Modules() []string {
+	modules.RLock()
+	defer modules.RUnlock()
+	names := make([]string, 0, len(modules.views))
+	for name := range modules.views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// moduleMount is a module mounted in the views folder
+type moduleMount struct {
+	module string
+	prefix string
+}
+
+// MountModule serves the views of the registered module under the folder
+// prefix of the views, the module name if empty: the authkit/login view of
+// the module renders with authkit/login. They are parsed like the views of
+// the app, with its layout, partials and funcs, and a file of the app at
+// the same path replaces the one of the module. Modules that are not
+// registered fail the load.
+func (e *Engine) MountModule(module, prefix string) *Engine {
+	if prefix == "" {
+		prefix = module
+	}
+	e.mutex.Lock()
+	e.mounts = append(e.mounts, moduleMount{module: module, prefix: strings.Trim(path.Clean("/"+prefix), "/")})
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// mountModules returns src with the mounted modules.
+func (e *Engine) mountModules(src fs.FS) (fs.FS, error) {
+	modules.RLock()
+	defer modules.RUnlock()
+	mfs := mountFS{base: src, mounts: make(map[string]fs.FS, len(e.mounts))}
+	for _, m := range e.mounts {
+		views, ok := modules.views[m.module]
+		if !ok {
+			return nil, fmt.Errorf("load: module %s is not registered", m.module)
+		}
+		mfs.mounts[m.prefix] = views
+	}
+	return mfs, nil
+}
+
+// mountFS serves the files of base and the file systems of mounts under
+// their prefix, base wins
+type mountFS struct {
+	base   fs.FS
+	mounts map[string]fs.FS
+}
+
+// Open opens name from base, or from the file system mounted at a prefix
+// of it.
+func (m mountFS) Open(name string) (fs.File, error) {
+	file, err := m.base.Open(name)
+	if err == nil {
+		return file, nil
+	}
+	if fsys, rel, ok := m.mounted(name); ok {
+		return fsys.Open(rel)
+	}
+	return nil, err
+}
+
+// ReadDir merges the entries of the directory name of base with those of
+// the mounted file systems, and the directories leading to their prefixes.
+func (m mountFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(m.base, name)
+	found := err == nil
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		seen[entry.Name()] = true
+	}
+	if fsys, rel, ok := m.mounted(name); ok {
+		mounted, merr := fs.ReadDir(fsys, rel)
+		if merr == nil {
+			found = true
+		}
+		for _, entry := range mounted {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	for prefix := range m.mounts {
+		rest := prefix
+		if name != "." {
+			if !strings.HasPrefix(prefix, name+"/") {
+				continue
+			}
+			rest = strings.TrimPrefix(prefix, name+"/")
+		}
+		dir, _, _ := strings.Cut(rest, "/")
+		found = true
+		if !seen[dir] {
+			seen[dir] = true
+			entries = append(entries, mountDir(dir))
+		}
+	}
+	if !found {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// mounted returns the file system mounted at a prefix of name and the
+// path of name in it.
+func (m mountFS) mounted(name string) (fs.FS, string, bool) {
+	for prefix, fsys := range m.mounts {
+		if name == prefix {
+			return fsys, ".", true
+		}
+		if rel := strings.TrimPrefix(name, prefix+"/"); rel != name {
+			return fsys, rel, true
+		}
+	}
+	return nil, "", false
+}
+
+// mountDir is a directory leading to a mount prefix
+type mountDir string
+
+func (d mountDir) Name() string               { return string(d) }
+func (d mountDir) IsDir() bool                { return true }
+func (d mountDir) Type() fs.FileMode          { return fs.ModeDir }
+func (d mountDir) Info() (fs.FileInfo, error) { return d, nil }
+func (d mountDir) Size() int64                { return 0 }
+func (d mountDir) Mode() fs.FileMode          { return fs.ModeDir | 0o555 }
+func (d mountDir) ModTime() time.Time         { return time.Time{} }
+func (d mountDir) Sys() interface{}           { return nil }
@@ -0,0 +1,49 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_MountModule(t *testing.T) {
+	RegisterModule("authkit", fstest.MapFS{
+		"login.html":        {Data: []byte(`{{define "content"}}<form>{{.}}</form>{{end}}`)},
+		"logout.html":       {Data: []byte(`{{define "content"}}<p>Signed out</p>{{end}}`)},
+		"mfa/setup.html":    {Data: []byte(`{{define "content"}}<p>MFA</p>{{end}}`)},
+		"layouts/main.html": {Data: []byte(`<html><nav>authkit</nav>{{block "content" .}}{{end}}</html>`)},
+	})
+	engine := New("./testdata/modules", ".html")
+	engine.Layout("layouts/main").MountModule("authkit", "account/auth")
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{"index", `<html><nav>app</nav><h1>Home</h1></html>`},
+		{"account/auth/login", `<html><nav>app</nav><form>Ann</form></html>`},
+		{"account/auth/mfa/setup", `<html><nav>app</nav><p>MFA</p></html>`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, tt.name, "Ann"); err != nil {
+			t.Fatalf("render %s: %v\n", tt.name, err)
+		}
+		if result := trim(buf.String()); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+
+	override := New("./testdata/modules", ".html")
+	override.Layout("layouts/main").MountModule("authkit", "")
+	var buf bytes.Buffer
+	if err := override.Render(&buf, "authkit/logout", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if expect, result := `<html><nav>app</nav><p>Signed out of the app</p></html>`, trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	if err := New("./testdata/modules", ".html").MountModule("billing", "").Load(); err == nil {
+		t.Fatalf("Expected error for a module that is not registered\n")
+	}
+}
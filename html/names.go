@@ -0,0 +1,37 @@
+package html
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// AllowPrefix restricts the templates that can be rendered to the names
+// starting with one of the prefixes, e.g. "pages/" when template names
+// are derived from user input.
+func (e *Engine) AllowPrefix(prefixes ...string) *Engine {
+	e.prefixes = append(e.prefixes, prefixes...)
+	return e
+}
+
+// checkName returns an error if name is not a clean relative template name
+// or does not match the allowed prefixes.
+func (e *Engine) checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("render: empty template name")
+	}
+	// Reject names that would escape or alias the views folder
+	if strings.ContainsAny(name, "\\\x00") || strings.HasPrefix(name, "/") || path.Clean(name) != name ||
+		name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("render: invalid template name %q", name)
+	}
+	if len(e.prefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range e.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("render: template %s is not allowed", name)
+}
@@ -0,0 +1,31 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_CheckName(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	var buf bytes.Buffer
+	for _, name := range []string{"", "../html", "/home", "errors/../home", "./home", "errors//404", "errors\\404", "home\x00"} {
+		if err := engine.Render(&buf, name, nil); err == nil {
+			t.Fatalf("Expected error for %q\n", name)
+		}
+	}
+	if err := engine.Render(&buf, "errors/404", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+
+	engine.AllowPrefix("errors/")
+	if err := engine.Render(&buf, "home", nil); err == nil {
+		t.Fatalf("Expected error for home\n")
+	}
+	if err := engine.Render(&buf, "errors/404", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+}
@@ -0,0 +1,100 @@
+package html
+
+import (
+	"io/fs"
+	"net/http"
+)
+
+// Option configures the engine built by NewWithOptions
+type Option func(e *Engine)
+
+// NewWithOptions returns a HTML render engine for Fiber configured by opts.
+// Views are read from ./views with the .html extension unless configured,
+// the chained setters can still be used on the result.
+func NewWithOptions(opts ...Option) *Engine {
+	engine := New("./views", ".html")
+	for _, opt := range opts {
+		opt(engine)
+	}
+	return engine
+}
+
+// WithDirectory sets the views folder, relative to the root of the file
+// system if one is set.
+func WithDirectory(directory string) Option {
+	return func(e *Engine) {
+		e.directory = directory
+	}
+}
+
+// WithExtension sets the views extension.
+func WithExtension(extension string) Option {
+	return func(e *Engine) {
+		e.extension = extension
+	}
+}
+
+// WithLayout sets the layout, see Engine.Layout.
+func WithLayout(layout string) Option {
+	return func(e *Engine) {
+		e.Layout(layout)
+	}
+}
+
+// WithFS reads the views from fsys, e.g. an embed.FS.
+func WithFS(fsys fs.FS) Option {
+	return func(e *Engine) {
+		e.fsys = fsys
+		if e.directory == "./views" {
+			e.directory = "."
+		}
+	}
+}
+
+// WithFileSystem reads the views from a http.FileSystem.
+func WithFileSystem(fileSystem http.FileSystem) Option {
+	return func(e *Engine) {
+		e.fileSystem = fileSystem
+		if e.directory == "./views" {
+			e.directory = "/"
+		}
+	}
+}
+
+// WithDelims sets the action delimiters, see Engine.Delims.
+func WithDelims(left, right string) Option {
+	return func(e *Engine) {
+		e.Delims(left, right)
+	}
+}
+
+// WithFuncs adds the functions to the template's function map.
+func WithFuncs(funcs map[string]interface{}) Option {
+	return func(e *Engine) {
+		for name, fn := range funcs {
+			e.AddFunc(name, fn)
+		}
+	}
+}
+
+// WithReload sets reload mode, see Engine.Reload.
+func WithReload(enabled bool) Option {
+	return func(e *Engine) {
+		e.Reload(enabled)
+	}
+}
+
+// WithIgnoreExtensionCase matches the extension case-insensitively, see
+// Engine.IgnoreExtensionCase.
+func WithIgnoreExtensionCase(enabled bool) Option {
+	return func(e *Engine) {
+		e.IgnoreExtensionCase(enabled)
+	}
+}
+
+// WithDebug sets debug mode, see Engine.Debug.
+func WithDebug(enabled bool) Option {
+	return func(e *Engine) {
+		e.Debug(enabled)
+	}
+}
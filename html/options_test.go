@@ -0,0 +1,39 @@
+package html
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func Test_NewWithOptions(t *testing.T) {
+	isAdmin := func(user string) bool {
+		return user == "admin"
+	}
+	for _, engine := range []*Engine{
+		NewWithOptions(
+			WithLayout("layouts/main"),
+			WithFuncs(map[string]interface{}{"isAdmin": isAdmin}),
+		),
+		NewWithOptions(
+			WithFS(os.DirFS(".")),
+			WithDirectory("views"),
+			WithExtension(".html"),
+			WithLayout("layouts/main"),
+			WithReload(true),
+			WithFuncs(map[string]interface{}{"isAdmin": isAdmin}),
+		),
+	} {
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, "index", map[string]interface{}{
+			"Title": "Hello, World!",
+		}); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+		result := trim(buf.String())
+		if expect != result {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
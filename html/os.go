@@ -0,0 +1,39 @@
+//go:build !noos
+
+package html
+
+import (
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// The engine reaches the operating system only through this file, built
+// without the noos tag. Building with -tags noos leaves it out for
+// runtimes without a file system, the views then come from fs.FS sources
+// such as embedded files.
+
+// debugLogger prints the engine events in debug mode
+var debugLogger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+// dirFS returns the folder dir of the host file system.
+func dirFS(dir string) (fs.FS, error) {
+	return os.DirFS(dir), nil
+}
+
+// openFile opens the file at path of the host file system.
+func openFile(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+// writeFile writes buf to the file name, a slash separated path, of the
+// folder dir, creating the missing folders.
+func writeFile(dir, name string, buf []byte) error {
+	file := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf, 0o644)
+}
@@ -0,0 +1,40 @@
+//go:build noos
+
+package html
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+)
+
+// errNoOS is returned by the features needing the host file system in
+// builds with the noos tag
+var errNoOS = errors.New("no host file system, built with the noos tag")
+
+// debugLogger prints the engine events in debug mode
+var debugLogger = slog.Default()
+
+// The host file system is reached through these vars in noos builds, the
+// tests running on a host set them to the os funcs.
+var (
+	dirFS     = noDirFS
+	openFile  = noOpenFile
+	writeFile = noWriteFile
+)
+
+// noDirFS fails, the views must be set with WithFS or SetFS.
+func noDirFS(dir string) (fs.FS, error) {
+	return nil, &fs.PathError{Op: "open", Path: dir, Err: errNoOS}
+}
+
+// noOpenFile fails, bundles must be read with ReadBundle.
+func noOpenFile(path string) (io.ReadCloser, error) {
+	return nil, &fs.PathError{Op: "open", Path: path, Err: errNoOS}
+}
+
+// noWriteFile fails, pages must be rendered to writers.
+func noWriteFile(dir, name string, buf []byte) error {
+	return &fs.PathError{Op: "write", Path: dir + "/" + name, Err: errNoOS}
+}
@@ -0,0 +1,71 @@
+//go:build noos
+
+package html
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// The suite reads its testdata from the host file system
+func init() {
+	dirFS = func(dir string) (fs.FS, error) {
+		return os.DirFS(dir), nil
+	}
+	openFile = func(path string) (io.ReadCloser, error) {
+		return os.Open(path)
+	}
+	writeFile = func(dir, name string, buf []byte) error {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(file, buf, 0o644)
+	}
+}
+
+// withoutOS runs fn with the host file system out of reach, as in the
+// noos builds.
+func withoutOS(fn func()) {
+	hostDirFS, hostOpenFile, hostWriteFile := dirFS, openFile, writeFile
+	dirFS, openFile, writeFile = noDirFS, noOpenFile, noWriteFile
+	defer func() {
+		dirFS, openFile, writeFile = hostDirFS, hostOpenFile, hostWriteFile
+	}()
+	fn()
+}
+
+func Test_NoOS(t *testing.T) {
+	withoutOS(func() {
+		if err := New("./views", ".html").Load(); !errors.Is(err, errNoOS) {
+			t.Fatalf("Expected:\n%v\nResult:\n%v\n", errNoOS, err)
+		}
+		if _, err := OpenBundle("views.zip"); err == nil || !strings.Contains(err.Error(), errNoOS.Error()) {
+			t.Fatalf("Expected:\n%v\nResult:\n%v\n", errNoOS, err)
+		}
+		fsys := fstest.MapFS{
+			"layouts/main.html": {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+			"index.html":        {Data: []byte(`{{define "content"}}<h1>{{.Title}}</h1>{{end}}`)},
+		}
+		engine := NewWithOptions(WithFS(fsys), WithLayout("layouts/main"))
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, "index", Map{"Title": "Home"}); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		expect := `<main><h1>Home</h1></main>`
+		if result := trim(buf.String()); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+		var static *StaticError
+		if err := engine.RenderAll(t.TempDir(), nil); !errors.As(err, &static) || !errors.Is(static.Pages[0].Err, errNoOS) {
+			t.Fatalf("Expected:\n%v\nResult:\n%v\n", errNoOS, err)
+		}
+	})
+}
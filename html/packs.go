@@ -0,0 +1,101 @@
+package html
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// HelperPack is a bundle of funcs and partials distributed together, such
+// as a forms or an SEO pack, added to an engine with Use
+type HelperPack interface {
+	// name of the pack, the folder of its partials
+	Name() string
+	// funcs registered on the engine, may be nil
+	Funcs() map[string]interface{}
+	// partials parsed into every page under the pack name, the file
+	// input.html of the forms pack as "forms/input", may be nil
+	Partials() fs.FS
+	// called once the funcs are registered, e.g. to call other setters
+	Init(e *Engine) error
+}
+
+// Pack is a HelperPack made of its fields
+type Pack struct {
+	PackName  string
+	FuncMap   map[string]interface{}
+	Templates fs.FS
+	Setup     func(e *Engine) error
+}
+
+// Name returns p.PackName.
+func (p Pack) Name() string { return p.PackName }
+
+// Funcs returns p.FuncMap.
+func (p Pack) Funcs() map[string]interface{} { return p.FuncMap }
+
+// Partials returns p.Templates.
+func (p Pack) Partials() fs.FS { return p.Templates }
+
+// Init calls p.Setup if set.
+func (p Pack) Init(e *Engine) error {
+	if p.Setup == nil {
+		return nil
+	}
+	return p.Setup(e)
+}
+
+// usedPack is the name and partials of a pack added with Use
+type usedPack struct {
+	name     string
+	partials fs.FS
+}
+
+// Use adds the packs to the engine: their funcs are registered, their
+// partials parsed into every page and their Init called. A pack used
+// twice is added once. An error of Init fails every load that follows,
+// the pack stays added.
+func (e *Engine) Use(packs ...HelperPack) *Engine {
+	for _, pack := range packs {
+		name := pack.Name()
+		e.mutex.Lock()
+		used := false
+		for _, p := range e.packs {
+			used = used || p.name == name
+		}
+		if used {
+			e.mutex.Unlock()
+			continue
+		}
+		for fn, impl := range pack.Funcs() {
+			e.funcmap[fn] = impl
+		}
+		e.packs = append(e.packs, usedPack{name: name, partials: pack.Partials()})
+		e.loaded.Store(false)
+		e.mutex.Unlock()
+		if err := pack.Init(e); err != nil {
+			e.mutex.Lock()
+			e.packErrs = append(e.packErrs, fmt.Errorf("load: pack %s: %w", name, err))
+			e.mutex.Unlock()
+		}
+	}
+	return e
+}
+
+// readPacks reads the partials of the packs, named under the pack name.
+func (e *Engine) readPacks() ([]partialFile, error) {
+	var partials []partialFile
+	for _, pack := range e.packs {
+		if pack.partials == nil {
+			continue
+		}
+		files, err := e.readShared(pack.partials, ".")
+		if err != nil {
+			return nil, fmt.Errorf("pack %s: %v", pack.name, err)
+		}
+		for _, file := range files {
+			file.name = pack.name + "/" + file.name
+			partials = append(partials, file)
+		}
+	}
+	return partials, nil
+}
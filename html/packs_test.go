@@ -0,0 +1,69 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_Use(t *testing.T) {
+	forms := Pack{
+		PackName: "forms",
+		FuncMap:  map[string]interface{}{"label": title},
+		Templates: fstest.MapFS{
+			"field.html": {Data: []byte(`<label>{{label .}}</label><input name="{{.}}">`)},
+		},
+	}
+	inits := 0
+	forms.Setup = func(e *Engine) error {
+		inits++
+		return nil
+	}
+	engine := New("./testdata/packs", ".html")
+	engine.Use(forms, forms)
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "signup", "email"); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<form><label>Email</label><input name="email"></form>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if inits != 1 {
+		t.Fatalf("Expected:\n%d\nResult:\n%d\n", 1, inits)
+	}
+
+	broken := New("./testdata/packs", ".html")
+	broken.Use(Pack{PackName: "seo", Setup: func(e *Engine) error { return errors.New("no site name") }})
+	expectErr := "load: pack seo: no site name"
+	if err := broken.Load(); err == nil || err.Error() != expectErr {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expectErr, err)
+	}
+	// The error is not forgotten once reported
+	if err := broken.Load(); err == nil || err.Error() != expectErr {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expectErr, err)
+	}
+
+	// Concurrent uses of a pack add it once
+	var concurrent int32
+	forms.Setup = func(e *Engine) error {
+		atomic.AddInt32(&concurrent, 1)
+		return nil
+	}
+	engine = New("./testdata/packs", ".html")
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.Use(forms)
+		}()
+	}
+	wg.Wait()
+	if concurrent != 1 {
+		t.Fatalf("Expected:\n%d\nResult:\n%d\n", 1, concurrent)
+	}
+}
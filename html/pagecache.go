@@ -0,0 +1,97 @@
+package html
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// cacheKey is the context key of the page cache key
+type cacheKey struct{}
+
+// pageKey is the cache key and tags of a page
+type pageKey struct {
+	key  string
+	tags []string
+}
+
+// WithCacheKey returns a copy of ctx caching the output of the renders
+// using it under key, see RenderCache. Keys must tell apart whatever the
+// output depends on, e.g. the path and the signed in user. Invalidating
+// one of the tags, e.g. "product:42", purges the page.
+func WithCacheKey(ctx context.Context, key string, tags ...string) context.Context {
+	return context.WithValue(ctx, cacheKey{}, pageKey{key: key, tags: tags})
+}
+
+// RenderCache caches the output of the renders whose context carries a
+// cache key for ttl, 0 keeps it until invalidated. Past ttl the cached page
+// is served for up to stale more while a background render refreshes it,
+// so expensive pages never make a request wait, 0 renders expired pages in
+// the request. The background render reuses the binding of the request
+// serving the stale page, which must not refer to memory reused once the
+// request ends, such as the fiber.Ctx. Partial renders are not cached,
+// pages are flushed on each load and live in the CacheStore, failing
+// stores make the pages render uncached.
+func (e *Engine) RenderCache(ttl, stale time.Duration) *Engine {
+	e.pages = newRenderCache(e.store(), "page", e.cacheNamespace, ttl, stale)
+	e.onClose(e.pages.flush)
+	return e.AddContextFunc("cacheTag", cacheTag)
+}
+
+// cacheTag tags the page or fragment being cached from the template:
+// {{cacheTag "product:42"}} renders nothing.
+func cacheTag(ctx context.Context, tags ...string) string {
+	if frame, ok := ctx.Value(fragmentKey{}).(*fragmentFrame); ok {
+		frame.deps = append(frame.deps, tags...)
+	}
+	return ""
+}
+
+// renderCached writes the cached page key, rendering it if needed.
+func (e *Engine) renderCached(ctx context.Context, key pageKey, tmpl *template.Template, out io.Writer, page string, binding interface{}) error {
+	entry, revalidate, err := e.pages.get(ctx, key.key)
+	if err != nil {
+		if err != ErrCacheMiss {
+			e.event(ctx, slog.LevelError, "views: page cache failed", slog.String("key", key.key), slog.Any("error", err))
+		}
+		if entry, err = e.renderPage(ctx, key, tmpl, page, binding); err != nil {
+			return err
+		}
+	} else if revalidate {
+		go e.revalidate(context.WithoutCancel(ctx), key, tmpl, page, binding)
+	}
+	_, err = io.WriteString(out, string(entry.HTML))
+	return err
+}
+
+// renderPage renders page and caches it under key along with its tags and
+// the keys of its fragments.
+func (e *Engine) renderPage(ctx context.Context, key pageKey, tmpl *template.Template, page string, binding interface{}) (*cacheEntry, error) {
+	frame := &fragmentFrame{deps: append([]string(nil), key.tags...)}
+	buf := getBuffer()
+	err := e.executeTemplate(context.WithValue(ctx, fragmentKey{}, frame), tmpl, buf, page, binding, false)
+	content := template.HTML(buf.b)
+	putBuffer(buf)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := e.pages.set(ctx, key.key, content, frame.deps)
+	if err != nil {
+		e.event(ctx, slog.LevelError, "views: page cache failed", slog.String("key", key.key), slog.Any("error", err))
+	}
+	return entry, nil
+}
+
+// revalidate renders the stale page key again in the background.
+func (e *Engine) revalidate(ctx context.Context, key pageKey, tmpl *template.Template, page string, binding interface{}) {
+	defer e.pages.refreshed(key.key)
+	if err := e.life.enter(); err != nil {
+		return
+	}
+	defer e.life.leave()
+	if _, err := e.renderPage(ctx, key, tmpl, page, binding); err != nil {
+		e.event(ctx, slog.LevelError, "views: revalidation failed", slog.String("template", page), slog.String("key", key.key), slog.Any("error", err))
+	}
+}
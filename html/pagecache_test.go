@@ -0,0 +1,94 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_RenderCache(t *testing.T) {
+	var renders atomic.Int64
+	engine := New("./testdata/pagecache", ".html")
+	engine.RenderCache(20*time.Millisecond, time.Hour)
+	engine.AddFunc("count", func() int64 {
+		return renders.Add(1)
+	})
+	ctx := WithCacheKey(context.Background(), "/counter")
+	render := func(ctx context.Context) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.RenderContext(ctx, &buf, "counter", nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		return trim(buf.String())
+	}
+
+	if result := render(ctx); result != "<p>1</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>1</p>", result)
+	}
+	if result := render(ctx); result != "<p>1</p>" {
+		t.Fatalf("Expected cached page\nResult:\n%s\n", result)
+	}
+	if result := render(context.Background()); result != "<p>2</p>" {
+		t.Fatalf("Expected renders without a key not to be cached\nResult:\n%s\n", result)
+	}
+
+	// Stale pages are served while rendered again in the background
+	time.Sleep(30 * time.Millisecond)
+	if result := render(ctx); result != "<p>1</p>" {
+		t.Fatalf("Expected stale page\nResult:\n%s\n", result)
+	}
+	deadline := time.Now().Add(time.Second)
+	for render(ctx) != "<p>3</p>" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the page to be revalidated\n")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	engine.Invalidate("/counter")
+	if result := render(ctx); result != "<p>4</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>4</p>", result)
+	}
+}
+
+func Test_CacheTags(t *testing.T) {
+	var renders atomic.Int64
+	engine := New("./testdata/pagecache", ".html")
+	engine.RenderCache(0, 0)
+	engine.AddFunc("count", func() int64 {
+		return renders.Add(1)
+	})
+	render := func(ctx context.Context, name string, binding interface{}) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.RenderContext(ctx, &buf, name, binding); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		return trim(buf.String())
+	}
+	counter := WithCacheKey(context.Background(), "/counter", "home")
+	product := WithCacheKey(context.Background(), "/products/42")
+	render(counter, "counter", nil)
+	render(product, "product", map[string]interface{}{"ID": 42})
+
+	// Tags supplied at render time
+	engine.Invalidate("home")
+	if result := render(counter, "counter", nil); result != "<p>3</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>3</p>", result)
+	}
+	if result := render(product, "product", map[string]interface{}{"ID": 42}); result != "<p>42 2</p>" {
+		t.Fatalf("Expected the product page to stay cached\nResult:\n%s\n", result)
+	}
+
+	// Tags supplied by the template
+	engine.Invalidate("product:42")
+	if result := render(product, "product", map[string]interface{}{"ID": 42}); result != "<p>42 4</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>42 4</p>", result)
+	}
+	if result := render(counter, "counter", nil); result != "<p>3</p>" {
+		t.Fatalf("Expected the counter page to stay cached\nResult:\n%s\n", result)
+	}
+}
@@ -0,0 +1,70 @@
+package html
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"unicode"
+)
+
+// PanicError is a panic recovered by the engine, raised by a func, a hook
+// or a file system given to it, so a misbehaving one fails its render or
+// load instead of crashing the server
+type PanicError struct {
+	// operation that panicked, such as "render: index" or "load"
+	Op    string
+	Value interface{}
+	// stack of the goroutine when it panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: panic: %v", e.Op, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recovered calls fn and returns its error, or a *PanicError if it
+// panics.
+func recovered(op string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Op: op, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// checkFuncs returns an error for the first func of funcmap, by name, that
+// template.Funcs would panic on: a name that is not an identifier or a
+// value that is not a func returning a value and optionally an error.
+func checkFuncs(funcmap map[string]interface{}) error {
+	names := make([]string, 0, len(funcmap))
+	for name := range funcmap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for i, r := range name {
+			if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+				return fmt.Errorf("load: func %q is not a valid name", name)
+			}
+		}
+		t := reflect.TypeOf(funcmap[name])
+		if t == nil || t.Kind() != reflect.Func {
+			return fmt.Errorf("load: func %s is a %T, not a func", name, funcmap[name])
+		}
+		switch {
+		case t.NumOut() == 1:
+		case t.NumOut() == 2 && t.Out(1) == errorType:
+		default:
+			return fmt.Errorf("load: func %s must return a value and optionally an error", name)
+		}
+	}
+	return nil
+}
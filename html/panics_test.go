@@ -0,0 +1,37 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_RecoverPanics(t *testing.T) {
+	engine := New("./testdata/panics", ".html")
+	engine.Transform(func(name string, binding interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "index", nil)
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", "*PanicError", err)
+	}
+	if expect := "render: index: panic: boom"; err.Error() != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, err.Error())
+	}
+}
+
+func Test_CheckFuncs(t *testing.T) {
+	for name, fn := range map[string]interface{}{
+		"answer":   42,
+		"two-word": func() string { return "" },
+		"pair":     func() (string, string) { return "", "" },
+	} {
+		engine := New("./testdata/panics", ".html")
+		engine.AddFunc(name, fn)
+		if err := engine.Load(); err == nil || errors.As(err, new(*PanicError)) {
+			t.Fatalf("Expected load error for %s\nResult:\n%v\n", name, err)
+		}
+	}
+}
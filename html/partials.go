@@ -0,0 +1,71 @@
+package html
+
+import (
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// partialFile is a shared partial or wrapper parsed into every page
+type partialFile struct {
+	// template name, the path relative to the partials folder
+	name string
+	path string
+	buf  []byte
+}
+
+// Partials sets the folder of the shared partials, e.g. "partials". Each
+// file is parsed into every page under its path relative to the folder,
+// partials/header.html as "header", so the layout can include it with
+// {{template "header" .}}. A page overrides a partial by defining a
+// template of the same name, the others keep the shared one.
+func (e *Engine) Partials(dir string) *Engine {
+	e.mutex.Lock()
+	e.partials = strings.Trim(dir, "/")
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// readShared reads the templates of the folder dir of src, named by their
+// path relative to dir.
+func (e *Engine) readShared(src fs.FS, dir string) ([]partialFile, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	var partials []partialFile
+	err := fs.WalkDir(src, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != e.extension {
+			return err
+		}
+		buf, err := e.readFile(src, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(p, dir+"/"), e.extension)
+		partials = append(partials, partialFile{name: name, path: p, buf: buf})
+		return nil
+	})
+	return partials, err
+}
+
+// parsePartials adds the shared partials to tmpl, skipping the ones
+// already defined if missing is set.
+func (e *Engine) parsePartials(tmpl *template.Template, partials []partialFile, missing bool) error {
+	for _, p := range partials {
+		if missing && tmpl.Lookup(p.name) != nil {
+			continue
+		}
+		before := trees(tmpl)
+		if _, err := tmpl.New(p.name).Parse(string(p.buf)); err != nil {
+			return err
+		}
+		if e.audit {
+			if err := instrument(tmpl, before, strings.TrimSuffix(p.path, e.extension)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_Partials(t *testing.T) {
+	engine := New("./testdata/partials", ".html")
+	engine.Layout("layouts/main").Partials("partials")
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{"index", `<header><h1>Shop</h1></header><p>index</p>`},
+		{"custom", `<header><h1>Custom Shop</h1></header><p>custom</p>`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, tt.name, map[string]interface{}{"Title": "Shop"}); err != nil {
+			t.Fatalf("render %s: %v\n", tt.name, err)
+		}
+		if result := trim(buf.String()); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+}
+
+func Test_AnalyzePartials(t *testing.T) {
+	engine := New("./testdata/partials", ".html")
+	engine.Layout("layouts/main").Partials("partials")
+	analysis, err := engine.Analyze()
+	if err != nil {
+		t.Fatalf("analyze: %v\n", err)
+	}
+	if len(analysis.Missing) != 0 {
+		t.Fatalf("Expected the shared partials to be found, got %v\n", analysis.Missing)
+	}
+}
@@ -0,0 +1,44 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+)
+
+// PDFRenderer converts a rendered HTML page to PDF, e.g. with chromedp or
+// wkhtmltopdf
+type PDFRenderer interface {
+	RenderPDF(ctx context.Context, html []byte, out io.Writer) error
+}
+
+// PDFRendererFunc adapts a func to the PDFRenderer interface
+type PDFRendererFunc func(ctx context.Context, html []byte, out io.Writer) error
+
+// RenderPDF calls f.
+func (f PDFRendererFunc) RenderPDF(ctx context.Context, html []byte, out io.Writer) error {
+	return f(ctx, html, out)
+}
+
+// RenderPDF renders the template with the layout and writes the page
+// converted by renderer to out, e.g. to export invoices.
+func (e *Engine) RenderPDF(out io.Writer, name string, binding interface{}, renderer PDFRenderer) error {
+	return e.RenderPDFContext(context.Background(), out, name, binding, renderer)
+}
+
+// RenderPDFContext is like RenderPDF with the render context, which is
+// passed on to renderer.
+func (e *Engine) RenderPDFContext(ctx context.Context, out io.Writer, name string, binding interface{}, renderer PDFRenderer) error {
+	if renderer == nil {
+		return fmt.Errorf("pdf: no renderer")
+	}
+	var buf bytes.Buffer
+	if err := e.RenderContext(ctx, &buf, name, binding); err != nil {
+		return err
+	}
+	if err := renderer.RenderPDF(ctx, buf.Bytes(), out); err != nil {
+		return fmt.Errorf("pdf: %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func Test_RenderPDF(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	renderer := PDFRendererFunc(func(ctx context.Context, html []byte, out io.Writer) error {
+		_, err := out.Write(append([]byte("%PDF "), bytes.TrimSpace(html)[:15]...))
+		return err
+	})
+	var buf bytes.Buffer
+	if err := engine.RenderPDF(&buf, "index", map[string]interface{}{"Title": "Invoice"}, renderer); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `%PDF <!DOCTYPE html>`
+	if result := buf.String(); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	failing := PDFRendererFunc(func(ctx context.Context, html []byte, out io.Writer) error {
+		return errors.New("no browser")
+	})
+	if err := engine.RenderPDF(&buf, "index", nil, failing); err == nil || err.Error() != "pdf: index: no browser" {
+		t.Fatalf("Expected renderer error, got %v\n", err)
+	}
+}
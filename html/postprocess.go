@@ -0,0 +1,37 @@
+package html
+
+import (
+	"context"
+	"io"
+)
+
+// PostProcessor rewrites the output of the page name, e.g. to inline
+// critical CSS. It may modify and return html, which is only valid until
+// it returns.
+type PostProcessor func(ctx context.Context, name string, html []byte) ([]byte, error)
+
+// PostProcess adds processors run in order on the output of each render
+// with the layout, partial renders are written as is. The page is
+// buffered, errors fail the render before anything is written.
+func (e *Engine) PostProcess(processors ...PostProcessor) *Engine {
+	e.postProcessors = append(e.postProcessors, processors...)
+	return e
+}
+
+// postProcess runs the processors of the page name on html and writes
+// the result to out, emails get their CSS inlined first.
+func (e *Engine) postProcess(ctx context.Context, out io.Writer, name string, html []byte, partial bool) error {
+	if e.emails[name] {
+		html = []byte(InlineCSS(string(html)))
+	}
+	if !partial {
+		var err error
+		for _, p := range e.postProcessors {
+			if html, err = p(ctx, name, html); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := out.Write(html)
+	return err
+}
@@ -0,0 +1,140 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Preload is an asset referenced by a template
+type Preload struct {
+	// asset url
+	URL string
+	// request destination: style, script or font
+	As string
+}
+
+var (
+	preloadLinkRe   = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	preloadScriptRe = regexp.MustCompile(`(?is)<script\s[^>]*>`)
+	preloadURLRe    = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")]+\.(?:woff2?|ttf|otf)(?:[?#][^'")]*)?)['"]?\s*\)`)
+	preloadAttrRe   = regexp.MustCompile(`(?is)\b(rel|href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	preloadFontRe   = regexp.MustCompile(`(?i)\.(?:woff2?|ttf|otf)(?:[?#].*)?$`)
+)
+
+// Preloads returns the stylesheets, scripts and fonts referenced by the
+// template name and the layout, in document order.
+func (e *Engine) Preloads(name string) ([]Preload, error) {
+	return e.PreloadsContext(context.Background(), name)
+}
+
+// PreloadsContext returns the assets of the page rendered for name in ctx,
+// which selects the theme, layout, locale, device class and variant.
+func (e *Engine) PreloadsContext(ctx context.Context, name string) ([]Preload, error) {
+	if err := e.checkName(name); err != nil {
+		return nil, err
+	}
+	// Not a render, the template cache counters are left alone
+	if err := e.LoadContext(ctx); err != nil {
+		return nil, err
+	}
+	_, page, _, pages, err := e.resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return pages.preloads[page], nil
+}
+
+// LinkHeader returns the Link header value preloading the assets of the template name.
+func (e *Engine) LinkHeader(name string) (string, error) {
+	return e.LinkHeaderContext(context.Background(), name)
+}
+
+// LinkHeaderContext returns the Link header value preloading the assets
+// of the page rendered for name in ctx.
+func (e *Engine) LinkHeaderContext(ctx context.Context, name string) (string, error) {
+	preloads, err := e.PreloadsContext(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	links := make([]string, 0, len(preloads))
+	for _, p := range preloads {
+		link := fmt.Sprintf("<%s>; rel=preload; as=%s", p.URL, p.As)
+		// Fonts are always fetched in cors mode
+		if p.As == "font" {
+			link += "; crossorigin"
+		}
+		links = append(links, link)
+	}
+	return strings.Join(links, ", "), nil
+}
+
+// scanPreloads extracts the static asset references from src.
+func (e *Engine) scanPreloads(src []byte, preloads []Preload) []Preload {
+	seen := make(map[string]bool, len(preloads))
+	for _, p := range preloads {
+		seen[p.URL] = true
+	}
+	add := func(url, as string) {
+		// Skip urls built by template actions
+		if url == "" || seen[url] || strings.Contains(url, e.left) {
+			return
+		}
+		seen[url] = true
+		preloads = append(preloads, Preload{URL: url, As: as})
+	}
+	type match struct {
+		pos     int
+		url, as string
+	}
+	var matches []match
+	for _, loc := range preloadLinkRe.FindAllIndex(src, -1) {
+		attrs := preloadAttrs(src[loc[0]:loc[1]])
+		href := attrs["href"]
+		switch {
+		case preloadFontRe.MatchString(href):
+			matches = append(matches, match{loc[0], href, "font"})
+		case hasToken(attrs["rel"], "stylesheet"):
+			matches = append(matches, match{loc[0], href, "style"})
+		}
+	}
+	for _, loc := range preloadScriptRe.FindAllIndex(src, -1) {
+		if url := preloadAttrs(src[loc[0]:loc[1]])["src"]; url != "" {
+			matches = append(matches, match{loc[0], url, "script"})
+		}
+	}
+	for _, loc := range preloadURLRe.FindAllSubmatchIndex(src, -1) {
+		matches = append(matches, match{loc[0], string(src[loc[2]:loc[3]]), "font"})
+	}
+	// Keep document order
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].pos < matches[j-1].pos; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+	for _, m := range matches {
+		add(m.url, m.as)
+	}
+	return preloads
+}
+
+// preloadAttrs returns the rel, href and src attributes of tag.
+func preloadAttrs(tag []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range preloadAttrRe.FindAllSubmatch(tag, -1) {
+		value := string(m[2]) + string(m[3]) + string(m[4])
+		attrs[strings.ToLower(string(m[1]))] = value
+	}
+	return attrs
+}
+
+// hasToken reports whether the space separated list contains token.
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(list) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+//go:build !noos
+
+package html
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetLinkHeader adds the preload Link header of the template name to the
+// response, for the page Respond renders for the request.
+func (e *Engine) SetLinkHeader(c *fiber.Ctx, name string) error {
+	link, err := e.LinkHeaderContext(e.requestContext(c), name)
+	if err != nil {
+		return err
+	}
+	if link != "" {
+		c.Append(fiber.HeaderLink, link)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package html
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_Preloads(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/assets")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	link, err := engine.LinkHeader("assets")
+	if err != nil {
+		t.Fatalf("link header: %v\n", err)
+	}
+	expect := `</css/app.css>; rel=preload; as=style, </fonts/inter.woff2>; rel=preload; as=font; crossorigin, </js/app.js>; rel=preload; as=script, </css/page.css>; rel=preload; as=style, </js/page.js>; rel=preload; as=script`
+	if expect != link {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, link)
+	}
+
+	if _, err := engine.Preloads("missing"); err == nil {
+		t.Fatalf("Expected error for missing template\n")
+	}
+}
+
+func Test_PreloadsContext(t *testing.T) {
+	engine := New("", ".html")
+	engine.SetFS(fstest.MapFS{
+		"index.html":   {Data: []byte(`<link rel="stylesheet" href="/index.css">`)},
+		"index_b.html": {Data: []byte(`<link rel="stylesheet" href="/index_b.css">`)},
+	})
+	engine.Variant("index", map[string]string{"B": "index_b"})
+	engine.Themes(map[string]fs.FS{
+		"brand": fstest.MapFS{
+			"index.html": {Data: []byte(`<link rel="stylesheet" href="/brand.css">`)},
+		},
+	})
+	for _, test := range []struct {
+		ctx    context.Context
+		expect string
+	}{
+		{context.Background(), "/index.css"},
+		{WithVariant(context.Background(), "B"), "/index_b.css"},
+		{WithTheme(context.Background(), "brand"), "/brand.css"},
+	} {
+		preloads, err := engine.PreloadsContext(test.ctx, "index")
+		if err != nil {
+			t.Fatalf("preloads: %v\n", err)
+		}
+		if len(preloads) != 1 || preloads[0].URL != test.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%v\n", test.expect, preloads)
+		}
+	}
+	// Preloads are no renders
+	if stats := engine.Stats(); stats.CacheHits != 0 || stats.CacheMisses != 0 {
+		t.Fatalf("Expected no template cache lookups, got %d hits %d misses\n", stats.CacheHits, stats.CacheMisses)
+	}
+}
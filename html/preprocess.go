@@ -0,0 +1,34 @@
+package html
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PreProcessor rewrites the source of the template file name, its path
+// in the views folder without extension, before it is parsed, e.g. to
+// strip build-time comments or inject design tokens.
+type PreProcessor func(name string, src []byte) ([]byte, error)
+
+// PreProcess adds processors run in order on each file of the views when
+// they are loaded: pages, layouts, partials and wrappers.
+func (e *Engine) PreProcess(processors ...PreProcessor) *Engine {
+	e.mutex.Lock()
+	e.preProcessors = append(e.preProcessors, processors...)
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// preProcess runs the processors on the source of the file at path.
+func (e *Engine) preProcess(file string, src []byte) ([]byte, error) {
+	name := strings.TrimSuffix(file, path.Ext(file))
+	for _, p := range e.preProcessors {
+		var err error
+		if src, err = p(name, src); err != nil {
+			return nil, fmt.Errorf("load: preprocess %s: %w", name, err)
+		}
+	}
+	return src, nil
+}
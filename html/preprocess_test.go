@@ -0,0 +1,42 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func Test_PreProcess(t *testing.T) {
+	buildComment := regexp.MustCompile(`\{\{/\* build:.*?\*/\}\}`)
+	var names []string
+	engine := New("./testdata/preprocess", ".html")
+	engine.Layout("layouts/main").PreProcess(
+		func(name string, src []byte) ([]byte, error) {
+			names = append(names, name)
+			return buildComment.ReplaceAll(src, nil), nil
+		},
+		func(name string, src []byte) ([]byte, error) {
+			return bytes.ReplaceAll(src, []byte("$brand"), []byte("teal")), nil
+		},
+	)
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<html style="color: teal"><p>teal</p></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if len(names) != 2 || names[0] != "layouts/main" || names[1] != "index" {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", "[layouts/main index]", names)
+	}
+
+	failing := New("./testdata/preprocess", ".html")
+	failing.PreProcess(func(name string, src []byte) ([]byte, error) {
+		return nil, errors.New("bad token")
+	})
+	if err := failing.Load(); err == nil {
+		t.Fatalf("Expected error from the pre-processor\n")
+	}
+}
@@ -0,0 +1,13 @@
+package html
+
+// PrintLayout sets the layout of the print version of the pages, e.g.
+// without navigation and with simplified CSS, rendered by Respond when
+// the query parameter param is set to 1 or true, print if empty. The
+// layout is added to Layouts, the page templates are the same.
+func (e *Engine) PrintLayout(layout, param string) *Engine {
+	if param == "" {
+		param = "print"
+	}
+	e.printLayout, e.printParam = layout, param
+	return e.Layouts(layout)
+}
@@ -0,0 +1,30 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PrintMiddleware selects the print layout for the requests asking for
+// it, for the adapters other than Respond.
+func (e *Engine) PrintMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(e.withPrint(c.UserContext(), c))
+		return c.Next()
+	}
+}
+
+// withPrint returns ctx rendering with the print layout if c asks for it.
+func (e *Engine) withPrint(ctx context.Context, c *fiber.Ctx) context.Context {
+	if e.printLayout == "" {
+		return ctx
+	}
+	switch c.Query(e.printParam) {
+	case "1", "true":
+		return UseLayout(ctx, e.printLayout)
+	}
+	return ctx
+}
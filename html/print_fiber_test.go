@@ -0,0 +1,38 @@
+//go:build !noos
+
+package html
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_PrintLayout(t *testing.T) {
+	engine := New("./testdata/print", ".html")
+	engine.Layout("layouts/main").PrintLayout("layouts/print", "")
+	app := fiber.New()
+	app.Get("/invoice", func(c *fiber.Ctx) error {
+		return engine.Respond(c, "invoice", nil)
+	})
+	tests := []struct {
+		path   string
+		expect string
+	}{
+		{"/invoice", `<html><nav>menu</nav><h1>Invoice</h1></html>`},
+		{"/invoice?print=1", `<html class="print"><h1>Invoice</h1></html>`},
+		{"/invoice?print=0", `<html><nav>menu</nav><h1>Invoice</h1></html>`},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if result := trim(string(body)); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+}
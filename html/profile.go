@@ -0,0 +1,192 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"text/template/parse"
+	"time"
+)
+
+// profileFunc is the func marking the boundaries of profiled nodes
+const profileFunc = "_htmlProfile"
+
+// Profile if set to true instruments the templates to time the funcs,
+// sub-templates and range loops of the renders whose context comes from
+// WithProfile. Every func becomes a context func, so it is meant for
+// development.
+func (e *Engine) Profile(enabled bool) *Engine {
+	e.profile = enabled
+	return e
+}
+
+// ProfileEntry is the time spent in a node of the templates
+type ProfileEntry struct {
+	// func, template or range
+	Kind string
+	// func name, template name or the location of the range loop
+	Name  string
+	Calls int
+	// time spent including the nested nodes
+	Total time.Duration
+}
+
+type profileKey struct{}
+
+// profile collects the timings of a render
+type profile struct {
+	mutex   sync.Mutex
+	entries map[[2]string]*ProfileEntry
+	// start times of the enclosing nodes
+	stack []time.Time
+}
+
+// WithProfile returns a copy of ctx collecting the timings of the renders
+// using it, which requires the engine to be in profile mode.
+func WithProfile(ctx context.Context) context.Context {
+	return context.WithValue(ctx, profileKey{}, &profile{entries: make(map[[2]string]*ProfileEntry)})
+}
+
+// ProfileReport returns the timings collected in ctx, slowest first.
+func ProfileReport(ctx context.Context) []ProfileEntry {
+	p, ok := ctx.Value(profileKey{}).(*profile)
+	if !ok {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	report := make([]ProfileEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		report = append(report, *entry)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Total != report[j].Total {
+			return report[i].Total > report[j].Total
+		}
+		return report[i].Kind+report[i].Name < report[j].Kind+report[j].Name
+	})
+	return report
+}
+
+func (p *profile) observe(kind, name string, d time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	entry := p.entries[[2]string{kind, name}]
+	if entry == nil {
+		entry = &ProfileEntry{Kind: kind, Name: name}
+		p.entries[[2]string{kind, name}] = entry
+	}
+	entry.Calls++
+	entry.Total += d
+}
+
+// profileMark starts or ends the timing of a node in the profile of ctx.
+func profileMark(ctx context.Context, begin bool, kind, name string) bool {
+	p, ok := ctx.Value(profileKey{}).(*profile)
+	if !ok {
+		return false
+	}
+	p.mutex.Lock()
+	if begin {
+		p.stack = append(p.stack, time.Now())
+		p.mutex.Unlock()
+		return false
+	}
+	// A failed node leaves its start behind, the render is aborted anyway
+	if len(p.stack) == 0 {
+		p.mutex.Unlock()
+		return false
+	}
+	start := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	p.mutex.Unlock()
+	p.observe(kind, name, time.Since(start))
+	return false
+}
+
+// profileFuncs returns the funcs and context funcs timed in the profile
+// of the render context, along with the marking func.
+func profileFuncs(funcmap, ctxfuncs map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(funcmap)+len(ctxfuncs)+1)
+	for name, fn := range funcmap {
+		if t := reflect.TypeOf(fn); t != nil && t.Kind() == reflect.Func {
+			result[name] = timeFunc(name, fn, false)
+		}
+	}
+	for name, fn := range ctxfuncs {
+		result[name] = timeFunc(name, fn, true)
+	}
+	result[profileFunc] = profileMark
+	return result
+}
+
+// timeFunc returns a context func timing fn, which takes the context first
+// if hasContext is set.
+func timeFunc(name string, fn interface{}, hasContext bool) interface{} {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	in := make([]reflect.Type, 0, t.NumIn()+1)
+	if !hasContext {
+		in = append(in, contextType)
+	}
+	for i := 0; i < t.NumIn(); i++ {
+		in = append(in, t.In(i))
+	}
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return reflect.MakeFunc(reflect.FuncOf(in, out, t.IsVariadic()), func(args []reflect.Value) []reflect.Value {
+		ctx := args[0].Interface().(context.Context)
+		if !hasContext {
+			args = args[1:]
+		}
+		start := time.Now()
+		var results []reflect.Value
+		if t.IsVariadic() {
+			results = v.CallSlice(args)
+		} else {
+			results = v.Call(args)
+		}
+		if p, ok := ctx.Value(profileKey{}).(*profile); ok {
+			p.observe("func", name, time.Since(start))
+		}
+		return results
+	}).Interface()
+}
+
+// instrumentProfile surrounds the sub-template calls and range loops of
+// the templates of tmpl with profile marks.
+func instrumentProfile(tmpl *template.Template) error {
+	return surround(tmpl, func(tree *parse.Tree, node parse.Node) (begin, end parse.Node, err error) {
+		var kind, name string
+		switch n := node.(type) {
+		case *parse.TemplateNode:
+			kind, name = "template", n.Name
+		case *parse.RangeNode:
+			kind, name = "range", location(tree, n)
+		default:
+			return nil, nil, nil
+		}
+		if begin, err = profileNode(true, kind, name); err != nil {
+			return nil, nil, err
+		}
+		end, err = profileNode(false, kind, name)
+		return begin, end, err
+	})
+}
+
+// profileNode returns a node marking the begin or end of a profiled node.
+func profileNode(begin bool, kind, name string) (parse.Node, error) {
+	// {{if mark ...}}{{end}} leaves no output in any context
+	src := fmt.Sprintf("{{if %s %t %s %s}}{{end}}", profileFunc, begin, strconv.Quote(kind), strconv.Quote(name))
+	parsed, err := parse.Parse("profile", src, "{{", "}}", map[string]interface{}{profileFunc: fmt.Sprint})
+	if err != nil {
+		return nil, err
+	}
+	return parsed["profile"].Root.Nodes[0], nil
+}
@@ -0,0 +1,38 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_Profile(t *testing.T) {
+	engine := New("./testdata/profile", ".html")
+	engine.Profile(true)
+	engine.AddFunc("upper", strings.ToUpper)
+	engine.AddContextFunc("count", func(ctx context.Context, items []string) int {
+		return len(items)
+	})
+	ctx := WithProfile(context.Background())
+	var buf bytes.Buffer
+	if err := engine.RenderContext(ctx, &buf, "page", map[string]interface{}{"Items": []string{"a", "b"}}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `AB<b>2</b>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	calls := make(map[string]int)
+	for _, entry := range ProfileReport(ctx) {
+		calls[entry.Kind+" "+entry.Name] = entry.Calls
+	}
+	if calls["func upper"] != 2 || calls["func count"] != 1 || calls["template item"] != 1 || calls["range page:1:8"] != 1 {
+		t.Fatalf("Unexpected profile %v\n", calls)
+	}
+
+	// Renders without a profile context are not recorded
+	if err := engine.Render(&buf, "page", map[string]interface{}{"Items": []string{"a"}}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+}
@@ -0,0 +1,113 @@
+package html
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+)
+
+// Redacted replaces the binding values whose key matches a redact pattern
+const Redacted = "[REDACTED]"
+
+// defaultRedact matches the keys redacted when no pattern is set
+var defaultRedact = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)pass(word|wd)?|secret|token|api_?key|private_?key|authorization|cookie|session`),
+}
+
+// maxRedactDepth limits the traversal of nested bindings
+const maxRedactDepth = 8
+
+// Redact sets the patterns matched against map keys and struct field names
+// of bindings written to debug output. Matching values are replaced with
+// [REDACTED]. Defaults to common secret names such as password and token.
+func (e *Engine) Redact(patterns ...*regexp.Regexp) *Engine {
+	e.redactPatterns = patterns
+	return e
+}
+
+// redactedBinding formats a binding for logging, only when the event is logged
+type redactedBinding struct {
+	engine  *Engine
+	binding interface{}
+}
+
+// LogValue returns the redacted binding.
+func (r redactedBinding) LogValue() slog.Value {
+	return slog.AnyValue(r.engine.redact(r.binding))
+}
+
+// redact returns a copy of binding made of maps and slices with the
+// secret values replaced.
+func (e *Engine) redact(binding interface{}) interface{} {
+	patterns := e.redactPatterns
+	if patterns == nil {
+		patterns = defaultRedact
+	}
+	return redactValue(reflect.ValueOf(binding), patterns, 0)
+}
+
+func redactValue(v reflect.Value, patterns []*regexp.Regexp, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > maxRedactDepth {
+		return "..."
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), patterns, depth+1)
+	case reflect.Map:
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if matchAny(patterns, key) {
+				result[key] = Redacted
+			} else {
+				result[key] = redactValue(iter.Value(), patterns, depth+1)
+			}
+		}
+		return result
+	case reflect.Struct:
+		result := make(map[string]interface{}, v.NumField())
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if matchAny(patterns, field.Name) {
+				result[field.Name] = Redacted
+			} else {
+				result[field.Name] = redactValue(v.Field(i), patterns, depth+1)
+			}
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = redactValue(v.Index(i), patterns, depth+1)
+		}
+		return result
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.Type().String()
+	}
+	return v.Interface()
+}
+
+// matchAny reports whether s matches one of the patterns.
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
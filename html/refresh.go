@@ -0,0 +1,140 @@
+package html
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// Versioned is implemented by view sources able to tell their version
+// cheaply, such as the ETag of a remote bundle or a revision column.
+// RefreshEvery uses it in place of scanning the files.
+type Versioned interface {
+	Version(ctx context.Context) (string, error)
+}
+
+// RefreshEvery checks the version of the views every interval in the
+// background and loads the templates again when it changed, firing the
+// OnReload hooks, or the OnError hooks if they fail to load. Sources
+// implementing Versioned report their version, others are scanned for
+// changed names, sizes and modification times. Close stops the checks, as
+// does the next call to RefreshEvery.
+func (e *Engine) RefreshEvery(interval time.Duration) *Engine {
+	version, err := e.sourceVersion(context.Background())
+	if err != nil {
+		e.event(context.Background(), slog.LevelError, "views: refresh failed", slog.Any("error", err))
+	}
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	e.mutex.Lock()
+	if e.refreshStop != nil {
+		close(e.refreshStop)
+	}
+	e.refreshStop = stop
+	e.mutex.Unlock()
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.life.done:
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+				version = e.refresh(context.Background(), version)
+			}
+		}
+	}()
+	// Close waits for a refresh in progress
+	e.onClose(func(ctx context.Context) error {
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	return e
+}
+
+// refresh loads the templates again if the version of the views is not
+// the given one and returns the version loaded.
+func (e *Engine) refresh(ctx context.Context, loaded string) string {
+	version, err := e.sourceVersion(ctx)
+	if err != nil {
+		e.event(ctx, slog.LevelError, "views: refresh failed", slog.Any("error", err))
+		return loaded
+	}
+	if version == loaded {
+		return loaded
+	}
+	// Renders keep the current set until the new one is published
+	if err := e.loadContext(ctx, true); err != nil {
+		// Try again on the next tick
+		return loaded
+	}
+	return version
+}
+
+// sourceVersion returns the version of the views and themes.
+func (e *Engine) sourceVersion(ctx context.Context) (string, error) {
+	// The sources may be swapped at runtime, scan the current ones
+	e.mutex.RLock()
+	fsys, fileSystem := e.fsys, e.fileSystem
+	src, err := e.source()
+	themes := make(map[string]fs.FS, len(e.themes))
+	for theme, fsys := range e.themes {
+		themes[theme] = fsys
+	}
+	e.mutex.RUnlock()
+	if v, ok := fsys.(Versioned); ok {
+		return v.Version(ctx)
+	}
+	if v, ok := fileSystem.(Versioned); ok {
+		return v.Version(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	if err := hashTree(hash, src); err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(themes))
+	for theme := range themes {
+		names = append(names, theme)
+	}
+	sort.Strings(names)
+	for _, theme := range names {
+		fmt.Fprintf(hash, "theme %s\n", theme)
+		if err := hashTree(hash, themes[theme]); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashTree writes the names, sizes and modification times of the files
+// of src to w.
+func hashTree(w interface{ Write([]byte) (int, error) }, src fs.FS) error {
+	return fs.WalkDir(src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return err
+	})
+}
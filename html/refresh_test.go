@@ -0,0 +1,147 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// versionedFS reports the version of a views folder
+type versionedFS struct {
+	fs.FS
+	version string
+}
+
+func (v *versionedFS) Version(ctx context.Context) (string, error) {
+	return v.version, nil
+}
+
+func Test_RefreshEvery(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(file, []byte("before"), 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	engine := New(dir, ".html")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	reloaded := make(chan struct{}, 1)
+	engine.OnReload(func(ctx context.Context, stats Stats) {
+		reloaded <- struct{}{}
+	})
+	engine.RefreshEvery(5 * time.Millisecond)
+	if err := os.WriteFile(file, []byte("after refresh"), 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the changed views to be loaded again\n")
+	}
+
+	var buf bytes.Buffer
+	if err := engine.Template("index").Execute(&buf, nil); err != nil {
+		t.Fatalf("execute: %v\n", err)
+	}
+	if result := buf.String(); result != "after refresh" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "after refresh", result)
+	}
+	if err := engine.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v\n", err)
+	}
+}
+
+func Test_RefreshVersioned(t *testing.T) {
+	src := &versionedFS{FS: os.DirFS("./views"), version: "1"}
+	engine := NewWithOptions(WithFS(src))
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	loads := engine.Stats().Loads
+	if version := engine.refresh(context.Background(), "1"); version != "1" || engine.Stats().Loads != loads {
+		t.Fatalf("Expected no load for the same version\n")
+	}
+	src.version = "2"
+	if version := engine.refresh(context.Background(), "1"); version != "2" || engine.Stats().Loads != loads+1 {
+		t.Fatalf("Expected a load for a new version\n")
+	}
+}
+
+// slowFS blocks the opening of files while slow is set
+type slowFS struct {
+	fs.FS
+	slow    atomic.Bool
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowFS) Open(name string) (fs.File, error) {
+	if s.slow.Load() && name != "." {
+		s.slow.Store(false)
+		close(s.started)
+		<-s.release
+	}
+	return s.FS.Open(name)
+}
+
+func Test_RefreshInBackground(t *testing.T) {
+	src := &slowFS{
+		FS:      fstest.MapFS{"index.html": {Data: []byte("current")}},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	engine := NewWithOptions(WithFS(src))
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	loads := engine.Stats().Loads
+	src.slow.Store(true)
+	refreshed := make(chan string)
+	go func() {
+		refreshed <- engine.refresh(context.Background(), "stale")
+	}()
+	<-src.started
+
+	// Renders go on with the current set, none starts a load of its own
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := buf.String(); result != "current" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "current", result)
+	}
+	close(src.release)
+	<-refreshed
+	if n := engine.Stats().Loads; n != loads+1 {
+		t.Fatalf("Expected a single load, got %d\n", n-loads)
+	}
+}
+
+func Test_RefreshSwap(t *testing.T) {
+	engine := NewWithOptions(WithFS(fstest.MapFS{"index.html": {Data: []byte("v1")}}))
+	defer engine.Close(context.Background())
+	engine.RefreshEvery(time.Millisecond)
+	first := engine.refreshStop
+	engine.RefreshEvery(time.Millisecond)
+	select {
+	case <-first:
+	default:
+		t.Fatalf("Expected the first refresh to stop\n")
+	}
+	// The refresh goroutine scans the sources swapped meanwhile
+	for i := 0; i < 20; i++ {
+		engine.SetFS(fstest.MapFS{"index.html": {Data: []byte("v2")}})
+		engine.SetDirectory(".")
+		time.Sleep(time.Millisecond)
+	}
+}
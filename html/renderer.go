@@ -0,0 +1,70 @@
+package html
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Renderer is the interface of the engines the adapters of this package
+// work with, Engine implements it and so can custom engines wrapping other
+// template syntaxes by embedding Base. Setters such as Layout and AddFunc
+// stay on the concrete engines, they return the engine to chain calls.
+type Renderer interface {
+	// Load parses the templates
+	Load() error
+	// Render executes the named template, it implements fiber.Views
+	Render(out io.Writer, name string, binding interface{}, layout ...string) error
+	// RenderContext executes the named template with the render context
+	RenderContext(ctx context.Context, out io.Writer, name string, binding interface{}, layout ...string) error
+	// RenderPartialContext executes the named template without the layout
+	RenderPartialContext(ctx context.Context, out io.Writer, name string, binding interface{}) error
+}
+
+// Engine implements Renderer
+var _ Renderer = (*Engine)(nil)
+
+// ErrNotImplemented is returned by the methods of Base without a func
+var ErrNotImplemented = errors.New("views: not implemented")
+
+// Base implements Renderer on top of two funcs, custom engines embed it
+// and either set the funcs or override the methods they need.
+type Base struct {
+	// LoadFunc parses the templates, Load does nothing when nil
+	LoadFunc func() error
+	// RenderFunc executes the named template, partial skips the layout
+	RenderFunc func(ctx context.Context, out io.Writer, name string, binding interface{}, partial bool) error
+}
+
+// Load parses the templates.
+func (b *Base) Load() error {
+	if b.LoadFunc == nil {
+		return nil
+	}
+	return b.LoadFunc()
+}
+
+// Render executes the named template, layouts are chosen by the engine.
+func (b *Base) Render(out io.Writer, name string, binding interface{}, layout ...string) error {
+	return b.RenderContext(context.Background(), out, name, binding, layout...)
+}
+
+// RenderContext executes the named template with the render context.
+func (b *Base) RenderContext(ctx context.Context, out io.Writer, name string, binding interface{}, layout ...string) error {
+	if len(layout) > 0 {
+		return fmt.Errorf("render: layout argument is not supported")
+	}
+	if b.RenderFunc == nil {
+		return ErrNotImplemented
+	}
+	return b.RenderFunc(ctx, out, name, binding, false)
+}
+
+// RenderPartialContext executes the named template without the layout.
+func (b *Base) RenderPartialContext(ctx context.Context, out io.Writer, name string, binding interface{}) error {
+	if b.RenderFunc == nil {
+		return ErrNotImplemented
+	}
+	return b.RenderFunc(ctx, out, name, binding, true)
+}
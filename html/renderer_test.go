@@ -0,0 +1,73 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// echoEngine is a custom engine writing the template name and binding
+type echoEngine struct {
+	Base
+	loads int
+}
+
+func newEchoEngine() *echoEngine {
+	e := &echoEngine{}
+	e.LoadFunc = func() error {
+		e.loads++
+		return nil
+	}
+	e.RenderFunc = func(ctx context.Context, out io.Writer, name string, binding interface{}, partial bool) error {
+		_, err := fmt.Fprintf(out, "%s:%s", name, binding.(fiber.Map)["Page"])
+		return err
+	}
+	return e
+}
+
+func Test_Renderer(t *testing.T) {
+	engine := newEchoEngine()
+	inertia := NewInertia(engine, "app")
+
+	app := fiber.New(fiber.Config{Views: engine})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.Render("index", fiber.Map{"Page": "home"})
+	})
+	app.Get("/users", func(c *fiber.Ctx) error {
+		return inertia.Render(c, "Users/Index", nil)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	expect := `index:home`
+	if result := string(body); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if engine.loads != 1 {
+		t.Fatalf("Expected fiber to load the custom engine once, got %d\n", engine.loads)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/users", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	if result := string(body); !strings.HasPrefix(result, `app:{"component":"Users/Index"`) {
+		t.Fatalf("Expected inertia to render with the custom engine\nResult:\n%s\n", result)
+	}
+
+	if err := (&Base{}).RenderPartialContext(context.Background(), io.Discard, "index", nil); err != ErrNotImplemented {
+		t.Fatalf("Expected ErrNotImplemented, got %v\n", err)
+	}
+}
@@ -0,0 +1,190 @@
+//go:build !noos
+
+package html
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResponseCache returns a middleware caching the HTML responses of GET
+// and HEAD requests for ttl in the CacheStore, keyed by method, host, path,
+// query and the request headers named by vary, which must tell apart
+// whatever the output depends on, e.g. Cookie. The headers the engine
+// varies on, such as those of the device class, are part of the key too.
+// Cached responses carry an ETag and are answered with 304 Not Modified
+// when the client has them, Invalidate purges the responses of a path,
+// loads purge them all. Only 200 responses whose type is text/html are
+// cached, and not those setting cookies, marked private or no-store, or
+// varying on a header missing from the key.
+//
+// Changes of the runtime settings, such as the maintenance mode, the sites
+// or the default theme and locale, leave the cached responses behind. In
+// maintenance mode only the responses of the templates still rendered are
+// served, provided Respond rendered them. The middlewares of several calls
+// share the cache and the ttl of the first.
+func (e *Engine) ResponseCache(ttl time.Duration, vary ...string) fiber.Handler {
+	e.mutex.Lock()
+	if e.responses == nil {
+		e.responses = newRenderCache(e.store(), "response", e.cacheNamespace, ttl, 0)
+		e.onClose(e.responses.flush)
+	}
+	responses := e.responses
+	e.mutex.Unlock()
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+		ctx := c.UserContext()
+		headers := e.varyHeaders(vary)
+		key := responseKey(c, e.published().generation, headers)
+		if len(vary) > 0 {
+			c.Vary(vary...)
+		}
+		entry, _, err := responses.get(ctx, key)
+		if err == nil && e.maintenanceFor(entry.Template) != "" {
+			// Rendered again in place of the maintenance template
+			err = ErrCacheMiss
+		}
+		if err == nil {
+			for name, values := range entry.Header {
+				c.Response().Header.Del(name)
+				for _, value := range values {
+					c.Response().Header.Add(name, value)
+				}
+			}
+			etag := responseETag([]byte(entry.HTML))
+			c.Set(fiber.HeaderETag, etag)
+			c.Set("X-Cache", "HIT")
+			if etagMatch(c.Get(fiber.HeaderIfNoneMatch), etag) {
+				return c.SendStatus(fiber.StatusNotModified)
+			}
+			if len(entry.Header[fiber.HeaderContentType]) == 0 {
+				c.Type("html", "utf-8")
+			}
+			return c.SendString(string(entry.HTML))
+		}
+		if err != ErrCacheMiss {
+			e.event(ctx, slog.LevelError, "views: response cache failed", slog.String("key", key), slog.Any("error", err))
+		}
+		if err = c.Next(); err != nil {
+			return err
+		}
+		header, ok := cacheableResponse(c, headers)
+		if !ok {
+			return nil
+		}
+		body := c.Response().Body()
+		// Fiber reuses the memory of the path once the request ends
+		entry = &cacheEntry{HTML: template.HTML(body), Deps: []string{strings.Clone(c.Path())}, Header: header}
+		entry.Template, _ = c.Locals(respondKey{}).(string)
+		if err = responses.setEntry(ctx, key, entry); err != nil {
+			e.event(ctx, slog.LevelError, "views: response cache failed", slog.String("key", key), slog.Any("error", err))
+		}
+		c.Set(fiber.HeaderETag, responseETag(body))
+		c.Set("X-Cache", "MISS")
+		return nil
+	}
+}
+
+// varyHeaders returns the request headers keying the cached responses:
+// vary and the headers the engine varies its output on.
+func (e *Engine) varyHeaders(vary []string) []string {
+	headers := append([]string{"X-Inertia"}, vary...)
+	if e.deviceSelector != nil || len(e.deviceLayouts) > 0 {
+		headers = append(headers, "Sec-CH-UA-Mobile", fiber.HeaderUserAgent)
+	}
+	return headers
+}
+
+// responseKey returns the cache key of the request of c under the
+// settings generation.
+func responseKey(c *fiber.Ctx, generation uint64, headers []string) string {
+	var b strings.Builder
+	b.WriteString(strconv.FormatUint(generation, 10))
+	b.WriteByte(' ')
+	b.WriteString(c.Method())
+	b.WriteByte(' ')
+	b.WriteString(strings.ToLower(c.Hostname()))
+	b.WriteString(c.Path())
+	if query := c.Request().URI().QueryString(); len(query) > 0 {
+		b.WriteByte('?')
+		b.Write(query)
+	}
+	for _, header := range headers {
+		b.WriteByte(0)
+		b.WriteString(c.Get(header))
+	}
+	return b.String()
+}
+
+// uncachedHeaders are the response headers not restored on cache hits
+var uncachedHeaders = map[string]bool{
+	fiber.HeaderContentLength: true, fiber.HeaderDate: true, fiber.HeaderServer: true,
+	fiber.HeaderConnection: true, fiber.HeaderTransferEncoding: true, fiber.HeaderETag: true,
+	fiber.HeaderVary: true, fiber.HeaderSetCookie: true, "X-Cache": true, "Server-Timing": true,
+}
+
+// cacheableResponse returns the headers stored along with the response of
+// c, ok is false if the response must not be cached.
+func cacheableResponse(c *fiber.Ctx, headers []string) (header map[string][]string, ok bool) {
+	resp := c.Response()
+	if resp.StatusCode() != fiber.StatusOK || !strings.HasPrefix(string(resp.Header.ContentType()), fiber.MIMETextHTML) {
+		return nil, false
+	}
+	if len(resp.Header.Peek(fiber.HeaderSetCookie)) > 0 {
+		return nil, false
+	}
+	for _, directive := range strings.Split(strings.ToLower(string(resp.Header.Peek(fiber.HeaderCacheControl))), ",") {
+		if directive = strings.TrimSpace(directive); directive == "private" || directive == "no-store" || strings.HasPrefix(directive, "private=") {
+			return nil, false
+		}
+	}
+	// A header the key does not tell apart would serve one variant to all
+	for _, name := range strings.Split(string(resp.Header.Peek(fiber.HeaderVary)), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		known := false
+		for _, h := range headers {
+			known = known || strings.EqualFold(h, name)
+		}
+		if !known {
+			return nil, false
+		}
+	}
+	// Headers such as Link may repeat
+	header = make(map[string][]string)
+	resp.Header.VisitAll(func(k, v []byte) {
+		if name := string(k); !uncachedHeaders[name] {
+			header[name] = append(header[name], string(v))
+		}
+	})
+	return header, true
+}
+
+// etagMatch reports whether the If-None-Match header value lists etag,
+// comparing weakly as the header requires.
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag != "" && strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseETag returns the strong ETag of body.
+func responseETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
@@ -0,0 +1,172 @@
+//go:build !noos
+
+package html
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_ResponseCache(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	renders := 0
+	app := fiber.New()
+	app.Use(engine.ResponseCache(time.Minute, "Accept-Language"))
+	app.Get("/", func(c *fiber.Ctx) error {
+		renders++
+		return engine.Respond(c, "index", fiber.Map{"Title": "Hello, World!"})
+	})
+	get := func(etag string) (int, string, string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.Header.Get("Vary") != "Accept-Language" {
+			t.Fatalf("Unexpected Vary %q\n", resp.Header.Get("Vary"))
+		}
+		return resp.StatusCode, resp.Header.Get("ETag"), trim(string(body))
+	}
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+	_, etag, result := get("")
+	if result != expect || etag == "" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if _, cached, result := get(""); result != expect || cached != etag || renders != 1 {
+		t.Fatalf("Expected a cached response, got %d renders\n", renders)
+	}
+	if status, _, _ := get(etag); status != fiber.StatusNotModified {
+		t.Fatalf("Expected 304, got %d\n", status)
+	}
+	if err := engine.Invalidate("/"); err != nil {
+		t.Fatalf("invalidate: %v\n", err)
+	}
+	if get(""); renders != 2 {
+		t.Fatalf("Expected the invalidated response to render again, got %d renders\n", renders)
+	}
+}
+
+func Test_ResponseCacheKeys(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	renders := 0
+	app := fiber.New()
+	app.Use(engine.ResponseCache(time.Minute))
+	app.Get("/", func(c *fiber.Ctx) error {
+		renders++
+		c.Set("Content-Language", "en")
+		return engine.Respond(c, "index", fiber.Map{"Title": c.Hostname()})
+	})
+	app.Get("/private", func(c *fiber.Ctx) error {
+		renders++
+		c.Cookie(&fiber.Cookie{Name: "session", Value: "42"})
+		return engine.Respond(c, "index", fiber.Map{"Title": "Private"})
+	})
+	get := func(host, path, etag string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = host
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		return resp
+	}
+	get("a.example.com", "/", "")
+	resp := get("b.example.com", "/", "")
+	body, _ := ioutil.ReadAll(resp.Body)
+	if renders != 2 || !strings.Contains(string(body), "b.example.com") {
+		t.Fatalf("Expected hosts to be cached apart, got %d renders:\n%s\n", renders, body)
+	}
+	resp = get("b.example.com", "/", "")
+	if renders != 2 || resp.Header.Get("Content-Language") != "en" || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected the cached headers, got %d renders: %v\n", renders, resp.Header)
+	}
+	if resp = get("b.example.com", "/", `"other", W/`+resp.Header.Get("ETag")); resp.StatusCode != fiber.StatusNotModified {
+		t.Fatalf("Expected 304, got %d\n", resp.StatusCode)
+	}
+	get("a.example.com", "/private", "")
+	get("a.example.com", "/private", "")
+	if renders != 4 {
+		t.Fatalf("Expected responses setting cookies to render each time, got %d renders\n", renders)
+	}
+}
+
+func Test_ResponseCacheSettings(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	renders := 0
+	app := fiber.New()
+	app.Use(engine.ResponseCache(time.Minute))
+	app.Get("/", func(c *fiber.Ctx) error {
+		renders++
+		c.Append("Link", "</a.css>; rel=preload")
+		c.Response().Header.Add("Link", "</b.js>; rel=preload")
+		return engine.Respond(c, "index", fiber.Map{"Title": "Home"})
+	})
+	get := func() *http.Response {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatalf("request: %v\n", err)
+		}
+		return resp
+	}
+	get()
+	if resp := get(); renders != 1 || len(resp.Header.Values("Link")) != 2 {
+		t.Fatalf("Expected the cached Link headers, got %d renders: %v\n", renders, resp.Header.Values("Link"))
+	}
+	// Maintenance mode bypasses the cached pages it does not allow
+	engine.MaintenanceMode(true, "home")
+	if resp := get(); resp.StatusCode != fiber.StatusServiceUnavailable || resp.Header.Get("X-Cache") == "HIT" {
+		t.Fatalf("Expected 503, got %d %s\n", resp.StatusCode, resp.Header.Get("X-Cache"))
+	}
+	engine.MaintenanceMode(false, "")
+	if get(); renders != 3 {
+		t.Fatalf("Expected a render after the settings changed, got %d renders\n", renders)
+	}
+	if resp := get(); renders != 3 || resp.Header.Get("X-Cache") != "HIT" {
+		t.Fatalf("Expected a cached response, got %d renders\n", renders)
+	}
+	engine.DefaultTheme("")
+	if get(); renders != 4 {
+		t.Fatalf("Expected a render after the default theme changed, got %d renders\n", renders)
+	}
+}
+
+func Test_ETagMatch(t *testing.T) {
+	for header, expect := range map[string]bool{
+		`"a"`:          true,
+		`W/"a"`:        true,
+		`"b", "a"`:     true,
+		`*`:            true,
+		`"b"`:          false,
+		``:             false,
+		`"b",W/"c"`:    false,
+		` "b" , W/"a"`: true,
+	} {
+		if result := etagMatch(header, `"a"`); result != expect {
+			t.Fatalf("%s: Expected %v, got %v\n", header, expect, result)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package html
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Func categories used to tag funcs with AddTaggedFunc
+const (
+	// reads or writes files
+	TagIO = "io"
+	// makes network requests
+	TagNetwork = "network"
+	// runs processes
+	TagExec = "exec"
+)
+
+// FuncPolicy restricts the funcs available to templates in strict mode
+type FuncPolicy struct {
+	// names of the funcs templates may call, including the builtin call
+	Allow []string
+	// func categories rejected even if allowed, defaults to io, network and exec
+	Deny []string
+}
+
+// Sandbox enables strict mode, meant for platforms rendering templates
+// uploaded by customers. Load fails if a registered func is not allowed
+// or is tagged with a denied category, and the builtin call is disabled
+// unless allowed, since it invokes any func found in the binding.
+func (e *Engine) Sandbox(policy FuncPolicy) *Engine {
+	if policy.Deny == nil {
+		policy.Deny = []string{TagIO, TagNetwork, TagExec}
+	}
+	e.policy = &policy
+	return e
+}
+
+// AddTaggedFunc adds the function to the template's function map along
+// with the categories describing what it does, see Sandbox.
+func (e *Engine) AddTaggedFunc(name string, fn interface{}, tags ...string) *Engine {
+	e.mutex.Lock()
+	e.funcmap[name] = fn
+	if e.functags == nil {
+		e.functags = make(map[string][]string)
+	}
+	e.functags[name] = tags
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// sandboxFuncs returns the funcs to parse the templates with, or an error
+// if they violate the sandbox policy.
+func (e *Engine) sandboxFuncs() (map[string]interface{}, error) {
+	if e.policy == nil {
+		return e.funcmap, nil
+	}
+	allowed := make(map[string]bool, len(e.policy.Allow))
+	for _, name := range e.policy.Allow {
+		allowed[name] = true
+	}
+	denied := make(map[string]bool, len(e.policy.Deny))
+	for _, tag := range e.policy.Deny {
+		denied[tag] = true
+	}
+	names := make([]string, 0, len(e.funcmap)+len(e.ctxfuncs))
+	for name := range e.funcmap {
+		names = append(names, name)
+	}
+	for name := range e.ctxfuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	funcmap := make(map[string]interface{}, len(e.funcmap)+1)
+	for _, name := range names {
+		if !allowed[name] {
+			return nil, fmt.Errorf("sandbox: func %s is not allowed", name)
+		}
+		for _, tag := range e.functags[name] {
+			if denied[tag] {
+				return nil, fmt.Errorf("sandbox: func %s is tagged %s which is denied", name, tag)
+			}
+		}
+		if fn, ok := e.funcmap[name]; ok {
+			funcmap[name] = fn
+		}
+	}
+	if !allowed["call"] {
+		funcmap["call"] = func(fn interface{}, args ...interface{}) (interface{}, error) {
+			return nil, fmt.Errorf("sandbox: func call is not allowed")
+		}
+	}
+	return funcmap, nil
+}
@@ -0,0 +1,52 @@
+package html
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func Test_Sandbox(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Sandbox(FuncPolicy{Allow: []string{"isAdmin", "readFile"}})
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.AddTaggedFunc("readFile", func(name string) (string, error) {
+		buf, err := ioutil.ReadFile(name)
+		return string(buf), err
+	}, TagIO)
+	err := engine.Load()
+	if err == nil || err.Error() != "sandbox: func readFile is tagged io which is denied" {
+		t.Fatalf("Expected denied error\nResult:\n%v\n", err)
+	}
+
+	engine = New("./views", ".html")
+	engine.Sandbox(FuncPolicy{Allow: []string{"isAdmin"}})
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.AddFunc("upper", strings.ToUpper)
+	err = engine.Load()
+	if err == nil || err.Error() != "sandbox: func upper is not allowed" {
+		t.Fatalf("Expected not allowed error\nResult:\n%v\n", err)
+	}
+
+	engine = New("./views", ".html")
+	engine.Sandbox(FuncPolicy{Allow: []string{"isAdmin"}})
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "admin", map[string]interface{}{
+		"User": "admin",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h1>Hello, Admin!</h1>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
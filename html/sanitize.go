@@ -0,0 +1,77 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Sanitizer turns untrusted HTML into safe HTML, *bluemonday.Policy
+// satisfies this interface
+type Sanitizer interface {
+	Sanitize(html string) string
+}
+
+// SanitizerFunc adapts a func to the Sanitizer interface
+type SanitizerFunc func(html string) string
+
+// Sanitize calls f(html).
+func (f SanitizerFunc) Sanitize(html string) string {
+	return f(html)
+}
+
+// basicTags are the formatting tags kept by BasicPolicy
+var basicTags = []string{"b", "i", "em", "strong", "u", "p", "br", "ul", "ol", "li", "code", "pre", "blockquote"}
+
+// basicReplacer restores the escaped basic tags
+var basicReplacer = func() *strings.Replacer {
+	var pairs []string
+	for _, tag := range basicTags {
+		pairs = append(pairs,
+			"&lt;"+tag+"&gt;", "<"+tag+">",
+			"&lt;/"+tag+"&gt;", "</"+tag+">",
+			"&lt;"+tag+"/&gt;", "<"+tag+"/>",
+		)
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// BasicPolicy escapes all markup except basic formatting tags without
+// attributes, such as <b>, <em>, <p> and <li>.
+var BasicPolicy = SanitizerFunc(func(html string) string {
+	return basicReplacer.Replace(template.HTMLEscapeString(html))
+})
+
+// Sanitizer sets the default policy and registers the sanitize func, which
+// renders untrusted content as safe HTML: {{sanitize .UserBio}}.
+// A named policy is selected with {{sanitize .Comment "comments"}}.
+func (e *Engine) Sanitizer(policy Sanitizer) *Engine {
+	return e.SanitizerPolicy("", policy)
+}
+
+// SanitizerPolicy sets the policy name, the empty name is the default policy.
+func (e *Engine) SanitizerPolicy(name string, policy Sanitizer) *Engine {
+	e.mutex.Lock()
+	if e.sanitizers == nil {
+		e.sanitizers = make(map[string]Sanitizer)
+	}
+	e.sanitizers[name] = policy
+	e.funcmap["sanitize"] = e.sanitize
+	e.loaded.Store(false)
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// sanitize returns content cleaned by the policy name, or the default policy.
+func (e *Engine) sanitize(content string, name ...string) (template.HTML, error) {
+	policyName := ""
+	if len(name) > 0 {
+		policyName = name[0]
+	}
+	policy := e.published().sanitizers[policyName]
+	if policy == nil {
+		return "", fmt.Errorf("sanitize: policy %q does not exist", policyName)
+	}
+	return template.HTML(policy.Sanitize(content)), nil
+}
@@ -0,0 +1,27 @@
+package html
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func Test_Sanitize(t *testing.T) {
+	engine := New("./testdata/sanitize", ".html")
+	engine.Sanitizer(BasicPolicy)
+	engine.SanitizerPolicy("text", SanitizerFunc(func(html string) string {
+		return regexp.MustCompile(`<[^>]*>`).ReplaceAllString(html, "")
+	}))
+
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "bio", map[string]interface{}{
+		"Bio": `<p>Hi, I am <b>John</b></p><script>alert(1)</script><b onclick="x()">!</b>`,
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<div><p>Hi, I am<b>John</b></p>&lt;script&gt;alert(1)&lt;/script&gt;&lt;b onclick=&#34;x()&#34;&gt;!</b></div><div>Hi, I am Johnalert(1)!</div>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
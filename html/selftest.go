@@ -0,0 +1,79 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// SelfTestOptions configures SelfTest
+type SelfTestOptions struct {
+	// bindings by template name, the others render with nil
+	Samples map[string]interface{}
+	// path.Match patterns of the templates not rendered, such as the ones
+	// meant to be rendered partially
+	Skip []string
+	// stop at the first failure
+	FailFast bool
+}
+
+// SelfTestError holds the templates that failed the self-test
+type SelfTestError struct {
+	Pages []PageError
+}
+
+func (e *SelfTestError) Error() string {
+	msgs := make([]string, len(e.Pages))
+	for i, page := range e.Pages {
+		msgs[i] = fmt.Sprintf("selftest: %s: %v", page.Name, page.Err)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// selfTestKey marks the renders of the self-test, which do not count as
+// rendered for Unused
+type selfTestKey struct{}
+
+// SelfTest renders every template with its sample binding into io.Discard,
+// so broken field references and funcs fail at startup instead of when a
+// user hits the page. The failures are returned together as a
+// *SelfTestError.
+func (e *Engine) SelfTest(opts SelfTestOptions) error {
+	return e.SelfTestContext(context.Background(), opts)
+}
+
+// SelfTestContext is like SelfTest, the renders use ctx.
+func (e *Engine) SelfTestContext(ctx context.Context, opts SelfTestOptions) error {
+	if err := e.LoadContext(ctx); err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, selfTestKey{}, true)
+	var failed []PageError
+	for _, name := range e.Names() {
+		if skipped(name, opts.Skip) {
+			continue
+		}
+		if err := e.RenderContext(ctx, io.Discard, name, opts.Samples[name]); err != nil {
+			failed = append(failed, PageError{Name: name, Err: err})
+			if opts.FailFast {
+				break
+			}
+		}
+	}
+	if failed != nil {
+		return &SelfTestError{Pages: failed}
+	}
+	return nil
+}
+
+// skipped reports whether name matches one of patterns.
+func skipped(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
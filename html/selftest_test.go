@@ -0,0 +1,29 @@
+package html
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_SelfTest(t *testing.T) {
+	type user struct{ Name string }
+	engine := New("./testdata/selftest", ".html")
+	err := engine.SelfTest(SelfTestOptions{
+		Samples: map[string]interface{}{"profile": map[string]interface{}{"User": user{"Ann"}}},
+		Skip:    []string{"rows/*"},
+	})
+	var failed *SelfTestError
+	if !errors.As(err, &failed) {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", "*SelfTestError", err)
+	}
+	if len(failed.Pages) != 1 || failed.Pages[0].Name != "profile" {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", "profile", err)
+	}
+	if unused := engine.Unused(); len(unused) != 3 {
+		t.Fatalf("Expected:\n%d\nResult:\n%v\n", 3, unused)
+	}
+
+	if err := engine.SelfTest(SelfTestOptions{Skip: []string{"profile", "rows/*"}}); err != nil {
+		t.Fatalf("selftest: %v\n", err)
+	}
+}
@@ -0,0 +1,81 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// SEO holds the meta tags of a page, rendered by the meta func
+type SEO struct {
+	Title       string
+	Description string
+	// absolute URL of the page
+	Canonical string
+	// absolute URL of the share image
+	Image string
+	// og:type, defaults to website
+	Type     string
+	SiteName string
+	// twitter:card, defaults to summary_large_image with an Image and
+	// summary otherwise
+	TwitterCard string
+	// twitter:site handle, such as @gofiber
+	TwitterSite string
+}
+
+// MetaFunc registers {{meta .SEO}}, which renders the title, description,
+// canonical, og:* and twitter:* tags of an SEO or *SEO value.
+func (e *Engine) MetaFunc() *Engine {
+	return e.AddFunc("meta", Meta)
+}
+
+// Meta returns the meta tags of seo, empty fields are left out.
+func Meta(seo interface{}) (template.HTML, error) {
+	var s SEO
+	switch v := seo.(type) {
+	case SEO:
+		s = v
+	case *SEO:
+		if v != nil {
+			s = *v
+		}
+	case nil:
+	default:
+		return "", fmt.Errorf("meta: %T is not an SEO", seo)
+	}
+	var b strings.Builder
+	tag := func(attr, key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "<meta %s=\"%s\" content=\"%s\">\n", attr, key, template.HTMLEscapeString(value))
+		}
+	}
+	if s.Title != "" {
+		fmt.Fprintf(&b, "<title>%s</title>\n", template.HTMLEscapeString(s.Title))
+	}
+	tag("name", "description", s.Description)
+	if s.Canonical != "" {
+		fmt.Fprintf(&b, "<link rel=\"canonical\" href=\"%s\">\n", template.HTMLEscapeString(s.Canonical))
+	}
+	if s.Type == "" {
+		s.Type = "website"
+	}
+	tag("property", "og:type", s.Type)
+	tag("property", "og:title", s.Title)
+	tag("property", "og:description", s.Description)
+	tag("property", "og:url", s.Canonical)
+	tag("property", "og:image", s.Image)
+	tag("property", "og:site_name", s.SiteName)
+	if s.TwitterCard == "" {
+		s.TwitterCard = "summary"
+		if s.Image != "" {
+			s.TwitterCard = "summary_large_image"
+		}
+	}
+	tag("name", "twitter:card", s.TwitterCard)
+	tag("name", "twitter:site", s.TwitterSite)
+	tag("name", "twitter:title", s.Title)
+	tag("name", "twitter:description", s.Description)
+	tag("name", "twitter:image", s.Image)
+	return template.HTML(b.String()), nil
+}
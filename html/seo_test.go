@@ -0,0 +1,31 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_Meta(t *testing.T) {
+	engine := New("./testdata/seo", ".html")
+	engine.MetaFunc()
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "page", map[string]interface{}{
+		"SEO": &SEO{
+			Title:       "Fish & Chips",
+			Description: "The best",
+			Canonical:   "https://example.com/fish",
+			Image:       "https://example.com/fish.png",
+		},
+	})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<head><title>Fish &amp; Chips</title><meta name="description" content="The best"><link rel="canonical" href="https://example.com/fish"><meta property="og:type" content="website"><meta property="og:title" content="Fish &amp; Chips"><meta property="og:description" content="The best"><meta property="og:url" content="https://example.com/fish"><meta property="og:image" content="https://example.com/fish.png"><meta name="twitter:card" content="summary_large_image"><meta name="twitter:title" content="Fish &amp; Chips"><meta name="twitter:description" content="The best"><meta name="twitter:image" content="https://example.com/fish.png"></head>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	if _, err := Meta("title"); err == nil {
+		t.Fatalf("Expected error for a string\n")
+	}
+}
@@ -0,0 +1,67 @@
+package html
+
+// renderSettings is the runtime configuration read by renders. It is not
+// modified once published, the setters publish a new copy so renders read
+// it without the lock.
+type renderSettings struct {
+	// template rendered in place of the others and the ones still rendered
+	maintenance      string
+	maintenanceAllow []string
+	// data merged into map bindings
+	globals map[string]interface{}
+	// tenants by name
+	tenants map[string]*Tenant
+	// configured locale fallback chains
+	fallbacks map[string][]string
+	// sanitize policies by name
+	sanitizers map[string]Sanitizer
+	// sites selected by request host
+	hosts []host
+	// variant templates by experiment template and variant key
+	variants map[string]map[string]string
+	// theme and locale used when the render context selects none
+	defaultTheme  string
+	defaultLocale string
+	// counts the publications, cached responses are keyed on it
+	generation uint64
+}
+
+// publish replaces the settings read by renders with the current
+// configuration, the caller holds the lock.
+func (e *Engine) publish() {
+	e.generation++
+	s := &renderSettings{
+		maintenance:      e.maintenance,
+		maintenanceAllow: append(e.maintenanceAllow[:0:0], e.maintenanceAllow...),
+		globals:          copyMap(e.globals),
+		tenants:          make(map[string]*Tenant, len(e.tenants)),
+		fallbacks:        make(map[string][]string, len(e.fallbacks)),
+		sanitizers:       make(map[string]Sanitizer, len(e.sanitizers)),
+		hosts:            append(e.hosts[:0:0], e.hosts...),
+		variants:         make(map[string]map[string]string, len(e.variants)),
+		defaultTheme:     e.defaultTheme,
+		defaultLocale:    e.defaultLocale,
+		generation:       e.generation,
+	}
+	for name, t := range e.tenants {
+		s.tenants[name] = t
+	}
+	for locale, fallbacks := range e.fallbacks {
+		s.fallbacks[locale] = fallbacks
+	}
+	for name, policy := range e.sanitizers {
+		s.sanitizers[name] = policy
+	}
+	for name, variants := range e.variants {
+		s.variants[name] = variants
+	}
+	e.settings.Store(s)
+}
+
+// published returns the settings read by renders.
+func (e *Engine) published() *renderSettings {
+	if s := e.settings.Load(); s != nil {
+		return s
+	}
+	return &renderSettings{}
+}
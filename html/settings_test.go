@@ -0,0 +1,42 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_RenderWithoutLock(t *testing.T) {
+	engine := New("./testdata/tenant", ".html")
+	engine.Globals(map[string]interface{}{"Site": "Shared"})
+	engine.Tenant("acme").
+		AddFunc("brandColor", func() string { return "red" }).
+		Globals(map[string]interface{}{"Site": "Acme"})
+	engine.MaintenanceAllow("index")
+	engine.LocaleFallback("fr-CA", "fr")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	// A slow load holds the lock, renders of the published set go on
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+	done := make(chan error, 1)
+	var buf bytes.Buffer
+	go func() {
+		ctx := WithLocale(WithTenant(context.Background(), "acme"), "fr-CA")
+		done <- engine.RenderContext(ctx, &buf, "index", map[string]interface{}{"Title": "Home"})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("render blocked by the lock\n")
+	}
+	expect := `<h1 style="color: red">Acme Home</h1>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,155 @@
+package html
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// SignatureFile is the default name of the signature of a views bundle
+const SignatureFile = "views.sig"
+
+// Verifier checks the signature of the digest of a views bundle
+type Verifier interface {
+	Verify(digest, signature []byte) error
+}
+
+// VerifierFunc adapts a func to the Verifier interface
+type VerifierFunc func(digest, signature []byte) error
+
+// Verify calls f.
+func (f VerifierFunc) Verify(digest, signature []byte) error {
+	return f(digest, signature)
+}
+
+// errBadSignature is returned by the verifiers for signatures not matching
+var errBadSignature = errors.New("signature: bundle signature mismatch")
+
+// Ed25519Verifier verifies ed25519 signatures made with the private key of
+// pub over the bundle digest.
+func Ed25519Verifier(pub ed25519.PublicKey) Verifier {
+	return VerifierFunc(func(digest, signature []byte) error {
+		if !ed25519.Verify(pub, digest, signature) {
+			return errBadSignature
+		}
+		return nil
+	})
+}
+
+// HMACVerifier verifies HMAC-SHA256 signatures of the bundle digest.
+func HMACVerifier(key []byte) Verifier {
+	return VerifierFunc(func(digest, signature []byte) error {
+		if !hmac.Equal(signature, SignHMAC(key, digest)) {
+			return errBadSignature
+		}
+		return nil
+	})
+}
+
+// SignHMAC returns the HMAC-SHA256 signature of digest.
+func SignHMAC(key, digest []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(digest)
+	return mac.Sum(nil)
+}
+
+// VerifySignature makes Load verify the views folder before parsing it,
+// the base64 signature of its BundleDigest is read from the file file of
+// the folder, SignatureFile if empty. The themes, the partials of the packs
+// and the merged engines are signed on their own, each with the file at
+// its root. A views store that was tampered with fails to load instead of
+// injecting markup into the renders.
+func (e *Engine) VerifySignature(v Verifier, file string) *Engine {
+	if file == "" {
+		file = SignatureFile
+	}
+	e.verifier = v
+	e.signatureFile = file
+	e.loaded.Store(false)
+	return e
+}
+
+// BundleDigest returns the sha256 digest signed by the signature of a views
+// bundle: the paths and contents of its files in lexical order, leaving out
+// the signature file.
+func BundleDigest(fsys fs.FS, signatureFile string) ([]byte, error) {
+	hash := sha256.New()
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path == signatureFile {
+			return err
+		}
+		buf, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", path, len(buf))
+		hash.Write(buf)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// verifySources checks the signatures of every file system parsed for o:
+// its views folder src, themes, packs and merged engines. The caller holds
+// the lock of o.
+func (e *Engine) verifySources(o *Engine, src fs.FS) error {
+	if err := e.verify(src); err != nil {
+		return err
+	}
+	themes := make([]string, 0, len(o.themes))
+	for theme := range o.themes {
+		themes = append(themes, theme)
+	}
+	sort.Strings(themes)
+	for _, theme := range themes {
+		if err := e.verify(o.themes[theme]); err != nil {
+			return fmt.Errorf("theme %s: %v", theme, err)
+		}
+	}
+	for _, pack := range o.packs {
+		if pack.partials == nil {
+			continue
+		}
+		if err := e.verify(pack.partials); err != nil {
+			return fmt.Errorf("pack %s: %v", pack.name, err)
+		}
+	}
+	for _, m := range o.merged {
+		m.engine.mutex.RLock()
+		src, err := m.engine.source()
+		if err == nil {
+			err = e.verifySources(m.engine, src)
+		}
+		m.engine.mutex.RUnlock()
+		if err != nil {
+			return fmt.Errorf("merge: %v", err)
+		}
+	}
+	return nil
+}
+
+// verify checks the signature of the file system.
+func (e *Engine) verify(src fs.FS) error {
+	encoded, err := fs.ReadFile(src, e.signatureFile)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return fmt.Errorf("signature: %s: %v", e.signatureFile, err)
+	}
+	digest, err := BundleDigest(src, e.signatureFile)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	return e.verifier.Verify(digest, signature)
+}
@@ -0,0 +1,107 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_VerifySignature(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<p>signed</p>"), 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	digest, err := BundleDigest(os.DirFS(dir), SignatureFile)
+	if err != nil {
+		t.Fatalf("digest: %v\n", err)
+	}
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	sign := func(signature []byte) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, SignatureFile), []byte(base64.StdEncoding.EncodeToString(signature)), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+
+	sign(ed25519.Sign(priv, digest))
+	engine := New(dir, ".html").VerifySignature(Ed25519Verifier(pub), "")
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := buf.String(); result != "<p>signed</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>signed</p>", result)
+	}
+
+	key := []byte("secret")
+	sign(SignHMAC(key, digest))
+	engine = New(dir, ".html").VerifySignature(HMACVerifier(key), "")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+
+	// Tampered bundle
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<script>evil()</script>"), 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	engine = New(dir, ".html").VerifySignature(HMACVerifier(key), "")
+	if err := engine.Load(); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Fatalf("Expected signature mismatch, got %v\n", err)
+	}
+	if engine.Template("index") != nil {
+		t.Fatalf("Expected the tampered bundle not to be parsed\n")
+	}
+}
+
+func Test_VerifySignatureSources(t *testing.T) {
+	key := []byte("secret")
+	signed := func(files map[string]string) fstest.MapFS {
+		t.Helper()
+		fsys := fstest.MapFS{}
+		for name, content := range files {
+			fsys[name] = &fstest.MapFile{Data: []byte(content)}
+		}
+		digest, err := BundleDigest(fsys, SignatureFile)
+		if err != nil {
+			t.Fatalf("digest: %v\n", err)
+		}
+		fsys[SignatureFile] = &fstest.MapFile{Data: []byte(base64.StdEncoding.EncodeToString(SignHMAC(key, digest)))}
+		return fsys
+	}
+	views := signed(map[string]string{"promo.html": `views`})
+	newEngine := func() *Engine {
+		return NewWithOptions(WithFS(views)).VerifySignature(HMACVerifier(key), "")
+	}
+
+	// Every file system parsed is verified
+	unsigned := fstest.MapFS{"promo.html": {Data: []byte(`<script>evil()</script>`)}}
+	theme := newEngine().Themes(map[string]fs.FS{"dark": unsigned})
+	if err := theme.Load(); err == nil || !strings.Contains(err.Error(), "theme dark") {
+		t.Fatalf("Expected the unsigned theme to fail, got %v\n", err)
+	}
+	pack := newEngine().Use(Pack{PackName: "forms", Templates: unsigned})
+	if err := pack.Load(); err == nil || !strings.Contains(err.Error(), "pack forms") {
+		t.Fatalf("Expected the unsigned pack to fail, got %v\n", err)
+	}
+	merged := newEngine()
+	if err := merged.Merge(NewWithOptions(WithFS(unsigned)), MergeKeep); err == nil || !strings.Contains(err.Error(), "merge") {
+		t.Fatalf("Expected the unsigned merged engine to fail, got %v\n", err)
+	}
+
+	// Signed ones load
+	theme = newEngine().Themes(map[string]fs.FS{"dark": signed(map[string]string{"promo.html": `dark`})})
+	var buf bytes.Buffer
+	if err := theme.RenderContext(WithTheme(context.Background(), "dark"), &buf, "promo", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := buf.String(); result != "dark" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "dark", result)
+	}
+}
@@ -0,0 +1,105 @@
+package html
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Query parameters added by signedURL
+const (
+	signedURLExpires   = "expires"
+	signedURLSignature = "signature"
+)
+
+// ErrExpiredURL is returned by VerifyURL for links past their expiry
+var ErrExpiredURL = errors.New("signed url: expired")
+
+// ErrInvalidURL is returned by VerifyURL for links without a valid signature
+var ErrInvalidURL = errors.New("signed url: invalid signature")
+
+// URLSigner sets the key signing the links of the signedURL func, which
+// appends the query parameters given as pairs, an expiry ttl from now and
+// an HMAC signature: {{signedURL "/download" "file" .ID}}. Handlers check
+// the links with VerifyURL.
+func (e *Engine) URLSigner(key []byte, ttl time.Duration) *Engine {
+	e.urlKey = key
+	e.urlTTL = ttl
+	return e.AddFunc("signedURL", func(path string, pairs ...interface{}) (string, error) {
+		return SignURL(key, path, ttl, pairs...)
+	})
+}
+
+// VerifyURL checks the signature and expiry of a link made by signedURL,
+// e.g. e.VerifyURL(c.OriginalURL()) in a Fiber handler.
+func (e *Engine) VerifyURL(rawURL string) error {
+	if e.urlKey == nil {
+		return errors.New("signed url: no key")
+	}
+	return VerifyURL(e.urlKey, rawURL)
+}
+
+// SignURL returns path with the query parameters given as pairs, an expiry
+// ttl from now and a signature made with key, 0 never expires.
+func SignURL(key []byte, path string, ttl time.Duration, pairs ...interface{}) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("signed url: odd number of query parameters")
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("signed url: %v", err)
+	}
+	query := u.Query()
+	for i := 0; i < len(pairs); i += 2 {
+		query.Set(fmt.Sprint(pairs[i]), fmt.Sprint(pairs[i+1]))
+	}
+	if ttl > 0 {
+		query.Set(signedURLExpires, strconv.FormatInt(now().Add(ttl).Unix(), 10))
+	}
+	query.Del(signedURLSignature)
+	query.Set(signedURLSignature, urlSignature(key, u.Path, query))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// VerifyURL checks a link made by SignURL with key.
+func VerifyURL(key []byte, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidURL
+	}
+	query := u.Query()
+	signature := query.Get(signedURLSignature)
+	if signature == "" || !hmac.Equal([]byte(signature), []byte(urlSignature(key, u.Path, query))) {
+		return ErrInvalidURL
+	}
+	if expires := query.Get(signedURLExpires); expires != "" {
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return ErrInvalidURL
+		}
+		if now().Unix() > unix {
+			return ErrExpiredURL
+		}
+	}
+	return nil
+}
+
+// urlSignature returns the signature of path and query, the signature
+// parameter left out.
+func urlSignature(key []byte, path string, query url.Values) string {
+	signed := make(url.Values, len(query))
+	for k, v := range query {
+		if k != signedURLSignature {
+			signed[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path + "?" + signed.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,40 @@
+package html
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func Test_SignedURL(t *testing.T) {
+	pinned := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return pinned }
+	defer func() { now = time.Now }()
+
+	engine := New("./testdata/signedurl", ".html")
+	engine.URLSigner([]byte("secret"), time.Hour)
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "download", map[string]interface{}{"ID": 42}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	m := regexp.MustCompile(`href="([^"]+)"`).FindStringSubmatch(buf.String())
+	if m == nil {
+		t.Fatalf("Expected a link\nResult:\n%s\n", buf.String())
+	}
+	link := html.UnescapeString(m[1])
+	if matched, _ := regexp.MatchString(`^/download\?expires=1704891600&file=42&signature=[\w-]+$`, link); !matched {
+		t.Fatalf("Unexpected link %s\n", link)
+	}
+	if err := engine.VerifyURL(link); err != nil {
+		t.Fatalf("verify: %v\n", err)
+	}
+	if err := engine.VerifyURL(regexp.MustCompile(`file=42`).ReplaceAllString(link, "file=43")); err != ErrInvalidURL {
+		t.Fatalf("Expected ErrInvalidURL for a tampered link, got %v\n", err)
+	}
+	now = func() time.Time { return pinned.Add(2 * time.Hour) }
+	if err := engine.VerifyURL(link); err != ErrExpiredURL {
+		t.Fatalf("Expected ErrExpiredURL, got %v\n", err)
+	}
+}
@@ -0,0 +1,108 @@
+package html
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SitemapURL is an entry of the sitemap, empty fields are left out
+type SitemapURL struct {
+	// path of the page, such as /posts/hello
+	Path       string
+	LastMod    time.Time
+	ChangeFreq string
+	Priority   float64
+}
+
+// SitemapEntries returns the entries of the pages rendered by a template,
+// e.g. one per post for the post template
+type SitemapEntries func(ctx context.Context) ([]SitemapURL, error)
+
+// sitemapPage is a template listed in the sitemap and its entries
+type sitemapPage struct {
+	name    string
+	entries SitemapEntries
+}
+
+// Sitemap lists the pages rendered by the template name in the sitemap,
+// entries returns them. Nil entries lists the single page at /name, / for
+// index. The templates must exist when the sitemap is written, so it does
+// not list pages whose views are gone.
+func (e *Engine) Sitemap(name string, entries SitemapEntries) *Engine {
+	e.mutex.Lock()
+	e.sitemap = append(e.sitemap, sitemapPage{name: name, entries: entries})
+	e.mutex.Unlock()
+	return e
+}
+
+// sitemap XML documents
+type (
+	sitemapURLSet struct {
+		XMLName xml.Name        `xml:"urlset"`
+		XMLNS   string          `xml:"xmlns,attr"`
+		URLs    []sitemapURLXML `xml:"url"`
+	}
+	sitemapURLXML struct {
+		Loc        string `xml:"loc"`
+		LastMod    string `xml:"lastmod,omitempty"`
+		ChangeFreq string `xml:"changefreq,omitempty"`
+		Priority   string `xml:"priority,omitempty"`
+	}
+)
+
+// WriteSitemap writes the sitemap.xml of the pages listed by Sitemap, in
+// the order they were listed, base is the scheme and host of their URLs.
+func (e *Engine) WriteSitemap(ctx context.Context, w io.Writer, base string) error {
+	if !e.loaded.Load() {
+		if err := e.LoadContext(ctx); err != nil {
+			return err
+		}
+	}
+	set := e.current.Load()
+	e.mutex.RLock()
+	pages := append([]sitemapPage(nil), e.sitemap...)
+	e.mutex.RUnlock()
+	doc := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	base = strings.TrimSuffix(base, "/")
+	for _, page := range pages {
+		if set == nil || set.templates[page.name] == nil {
+			return fmt.Errorf("sitemap: template %s does not exist", page.name)
+		}
+		urls := []SitemapURL{{Path: pagePath(page.name)}}
+		if page.entries != nil {
+			err := recovered("entries", func() (err error) {
+				urls, err = page.entries(ctx)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("sitemap: %s: %w", page.name, err)
+			}
+		}
+		for _, u := range urls {
+			entry := sitemapURLXML{Loc: base + u.Path, ChangeFreq: u.ChangeFreq}
+			if !u.LastMod.IsZero() {
+				entry.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+			}
+			if u.Priority > 0 {
+				entry.Priority = fmt.Sprintf("%.1f", u.Priority)
+			}
+			doc.URLs = append(doc.URLs, entry)
+		}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(doc)
+}
+
+// pagePath returns the path of the page rendered by the template name.
+func pagePath(name string) string {
+	if name == "index" || strings.HasSuffix(name, "/index") {
+		name = strings.TrimSuffix(name, "index")
+	}
+	return "/" + name
+}
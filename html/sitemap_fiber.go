@@ -0,0 +1,27 @@
+//go:build !noos
+
+package html
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SitemapHandler returns a handler serving the sitemap.xml of the pages
+// listed by Sitemap, base is the scheme and host of their URLs, the ones
+// of the request if empty.
+func (e *Engine) SitemapHandler(base string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		root := base
+		if root == "" {
+			root = c.BaseURL()
+		}
+		var buf strings.Builder
+		if err := e.WriteSitemap(c.UserContext(), &buf, root); err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+		return c.SendString(buf.String())
+	}
+}
@@ -0,0 +1,48 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Sitemap(t *testing.T) {
+	engine := New("./testdata/sitemap", ".html")
+	engine.Sitemap("index", nil).Sitemap("about", nil)
+	engine.Sitemap("posts/show", func(ctx context.Context) ([]SitemapURL, error) {
+		return []SitemapURL{
+			{Path: "/posts/hello", LastMod: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ChangeFreq: "weekly", Priority: 0.8},
+		}, nil
+	})
+	app := fiber.New()
+	app.Get("/sitemap.xml", engine.SitemapHandler(""))
+	resp, err := app.Test(httptest.NewRequest("GET", "http://example.com/sitemap.xml", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	expect := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<url><loc>http://example.com/</loc></url>` +
+		`<url><loc>http://example.com/about</loc></url>` +
+		`<url><loc>http://example.com/posts/hello</loc><lastmod>2024-05-01T12:00:00Z</lastmod><changefreq>weekly</changefreq><priority>0.8</priority></url>` +
+		`</urlset>`
+	if result := string(body); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	engine.Sitemap("contact", nil)
+	resp, err = app.Test(httptest.NewRequest("GET", "http://example.com/sitemap.xml", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("Expected:\n%d\nResult:\n%d\n", fiber.StatusInternalServerError, resp.StatusCode)
+	}
+}
@@ -0,0 +1,68 @@
+package html
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"path"
+	"sync"
+	"sync/atomic"
+)
+
+// Assets sets the file system holding the static assets and registers the
+// sri func, which renders the integrity and crossorigin attributes of an
+// asset: <script src="/js/app.js" {{sri "js/app.js"}}></script>
+func (e *Engine) Assets(fs http.FileSystem) *Engine {
+	e.assets = fs
+	e.AddFunc("sri", e.sri)
+	return e
+}
+
+// sriCache caches the integrity hashes by asset path
+type sriCache struct {
+	hashes sync.Map
+}
+
+// newSRIHashes returns the holder of an empty sriCache, renders read it
+// while loads replace it.
+func newSRIHashes() *atomic.Pointer[sriCache] {
+	p := &atomic.Pointer[sriCache]{}
+	p.Store(&sriCache{})
+	return p
+}
+
+// sri returns the integrity and crossorigin attributes of the asset name.
+func (e *Engine) sri(name string) (template.HTMLAttr, error) {
+	hash, err := e.integrity(name)
+	if err != nil {
+		return "", err
+	}
+	return template.HTMLAttr(fmt.Sprintf(`integrity="%s" crossorigin="anonymous"`, hash)), nil
+}
+
+// integrity returns the sha384 integrity value of the asset name.
+func (e *Engine) integrity(name string) (string, error) {
+	if e.assets == nil {
+		return "", fmt.Errorf("sri: no assets file system")
+	}
+	name = path.Clean("/" + name)
+	cache := e.sriHashes.Load()
+	if hash, ok := cache.hashes.Load(name); ok {
+		return hash.(string), nil
+	}
+	file, err := e.assets.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("sri: %v", err)
+	}
+	defer file.Close()
+	h := sha512.New384()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("sri: %s: %v", name, err)
+	}
+	hash := "sha384-" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+	cache.hashes.Store(name, hash)
+	return hash, nil
+}
@@ -0,0 +1,52 @@
+package html
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func Test_SRI(t *testing.T) {
+	engine := New("./testdata/sri", ".html")
+	engine.Assets(http.Dir("./testdata/assets"))
+
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "sri", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<script src="/app.js" integrity="sha384-Tc1KWaETWL9ZNn+TiVtHwsBd+yyFv2LCslp7PjwHT5aJCyBwpxnW7xtPA1RdtKbo" crossorigin="anonymous"></script>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_SRIDuringLoads(t *testing.T) {
+	engine := New("./testdata/sri", ".html")
+	engine.Assets(http.Dir("./testdata/assets"))
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	// Renders read the hashes while loads replace them, go test -race
+	// reports unsynchronized accesses
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := engine.Render(&bytes.Buffer{}, "sri", nil); err != nil {
+					t.Errorf("render: %v\n", err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		engine.SetDirectory("./testdata/sri")
+		if err := engine.Load(); err != nil {
+			t.Fatalf("load: %v\n", err)
+		}
+	}
+	wg.Wait()
+}
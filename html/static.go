@@ -0,0 +1,133 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// StaticOptions configures RenderAllContext
+type StaticOptions struct {
+	// number of templates rendered at once, GOMAXPROCS if 0
+	Workers int
+	// called after each template with the number of templates done, out
+	// of total, and the error of the template if it failed
+	Progress func(done, total int, name string, err error)
+}
+
+// StaticError lists the templates RenderAll failed to write
+type StaticError struct {
+	Pages []PageError
+}
+
+// PageError is the error of one template of RenderAll
+type PageError struct {
+	Name string
+	Err  error
+}
+
+func (e *StaticError) Error() string {
+	msgs := make([]string, len(e.Pages))
+	for i, page := range e.Pages {
+		msgs[i] = fmt.Sprintf("static: %s: %v", page.Name, page.Err)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// RenderAll renders every template with the layout to a file of outDir,
+// e.g. the index template to outDir/index.html and blog/post to
+// outDir/blog/post.html, for pre-rendering sections of a site at build
+// time. dataFn returns the binding of each template, nil binds nothing.
+func (e *Engine) RenderAll(outDir string, dataFn func(name string) interface{}) error {
+	return e.RenderAllContext(context.Background(), outDir, dataFn, StaticOptions{})
+}
+
+// RenderAllContext is like RenderAll but renders the templates on a pool
+// of workers, dataFn is called from several goroutines. The templates
+// that fail do not stop the others, their errors are returned together
+// as a *StaticError. Templates not started yet are skipped once ctx is
+// done.
+func (e *Engine) RenderAllContext(ctx context.Context, outDir string, dataFn func(name string) interface{}, opts StaticOptions) error {
+	if err := e.LoadContext(ctx); err != nil {
+		return err
+	}
+	names := e.Names()
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(names) {
+		workers = len(names)
+	}
+	jobs := make(chan string)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []PageError
+		done     atomic.Int64
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				err := e.renderStatic(ctx, outDir, name, dataFn)
+				if err != nil {
+					mu.Lock()
+					failures = append(failures, PageError{Name: name, Err: err})
+					mu.Unlock()
+				}
+				n := done.Add(1)
+				if opts.Progress != nil {
+					mu.Lock()
+					opts.Progress(int(n), len(names), name, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+feed:
+	for _, name := range names {
+		select {
+		case jobs <- name:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool {
+			return failures[i].Name < failures[j].Name
+		})
+		return &StaticError{Pages: failures}
+	}
+	return nil
+}
+
+// renderStatic writes the template name to its file of outDir.
+func (e *Engine) renderStatic(ctx context.Context, outDir, name string, dataFn func(name string) interface{}) error {
+	var binding interface{}
+	if dataFn != nil {
+		err := recovered("data", func() error {
+			binding = dataFn(name)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	buf, err := e.renderBytes(ctx, name, binding, false)
+	if err != nil {
+		return err
+	}
+	defer putBuffer(buf)
+	return writeFile(outDir, name+".html", buf.b)
+}
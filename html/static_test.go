@@ -0,0 +1,74 @@
+package html
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_RenderAll(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	dir := t.TempDir()
+	err := engine.RenderAll(dir, func(name string) interface{} {
+		return map[string]interface{}{"Title": "Static " + name}
+	})
+	if err != nil {
+		t.Fatalf("render all: %v\n", err)
+	}
+	buf, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Static index</h1><h2>Footer</h2></body></html>`
+	if result := trim(string(buf)); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	buf, err = os.ReadFile(filepath.Join(dir, "errors", "404.html"))
+	if err != nil {
+		t.Fatalf("read: %v\n", err)
+	}
+	if result := trim(string(buf)); !strings.Contains(result, "<title>Main</title>") {
+		t.Fatalf("Expected nested templates with the layout\nResult:\n%s\n", result)
+	}
+}
+
+func Test_RenderAllErrors(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	dir := t.TempDir()
+	// A file in place of the errors folder fails the nested templates
+	if err := os.WriteFile(filepath.Join(dir, "errors"), nil, 0o644); err != nil {
+		t.Fatalf("write: %v\n", err)
+	}
+	var progress []string
+	err := engine.RenderAllContext(context.Background(), dir, nil, StaticOptions{
+		Workers: 4,
+		Progress: func(done, total int, name string, err error) {
+			if done == total {
+				progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+			}
+		},
+	})
+	var static *StaticError
+	if !errors.As(err, &static) || len(static.Pages) != 1 || static.Pages[0].Name != "errors/404" {
+		t.Fatalf("Expected errors/404 to fail, got %v\n", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "index.html")); err != nil {
+		t.Fatalf("Expected the other templates to be written: %v\n", err)
+	}
+	expect := fmt.Sprintf("[%d/%d]", len(engine.Names()), len(engine.Names()))
+	if result := fmt.Sprint(progress); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
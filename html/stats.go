@@ -0,0 +1,136 @@
+package html
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the engine counters since startup
+type Stats struct {
+	// number of parsed templates
+	Templates int `json:"templates"`
+	// number of renders
+	Renders uint64 `json:"renders"`
+	// number of failed renders
+	Errors uint64 `json:"errors"`
+	// number of failed renders by template
+	TemplateErrors map[string]uint64 `json:"template_errors,omitempty"`
+	// time of the last failed render
+	LastError time.Time `json:"last_error"`
+	// lookups served from the parsed templates
+	CacheHits uint64 `json:"cache_hits"`
+	// lookups that had to parse the templates first
+	CacheMisses uint64 `json:"cache_misses"`
+	// number of loads, including reloads
+	Loads uint64 `json:"loads"`
+	// number of failed loads
+	LoadErrors uint64 `json:"load_errors"`
+	// time of the last successful load
+	LastLoad time.Time `json:"last_load"`
+	// templates changed by the last reload
+	Changes ReloadDiff `json:"changes"`
+}
+
+// engineStats holds the counters behind Stats
+type engineStats struct {
+	renders    atomic.Uint64
+	errors     atomic.Uint64
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	loads      atomic.Uint64
+	loadErrors atomic.Uint64
+	lastLoad   atomic.Int64
+	lastError  atomic.Int64
+	// names of the templates rendered at least once
+	rendered sync.Map
+	// *atomic.Uint64 error counters by template name
+	templateErrors sync.Map
+}
+
+func (s *engineStats) observeRender(name string, err error) {
+	s.renders.Add(1)
+	if err != nil {
+		s.errors.Add(1)
+		s.lastError.Store(time.Now().UnixNano())
+		counter, ok := s.templateErrors.Load(name)
+		if !ok {
+			counter, _ = s.templateErrors.LoadOrStore(name, new(atomic.Uint64))
+		}
+		counter.(*atomic.Uint64).Add(1)
+	}
+}
+
+func (s *engineStats) observeCache(hit bool) {
+	if hit {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+}
+
+func (s *engineStats) observeLoad(start time.Time, err error) {
+	s.loads.Add(1)
+	if err != nil {
+		s.loadErrors.Add(1)
+		return
+	}
+	s.lastLoad.Store(start.UnixNano())
+}
+
+// Stats returns the engine counters.
+func (e *Engine) Stats() Stats {
+	e.mutex.RLock()
+	templates := len(e.Templates)
+	changes := e.changes
+	e.mutex.RUnlock()
+	stats := Stats{
+		Templates:   templates,
+		Renders:     e.stats.renders.Load(),
+		Errors:      e.stats.errors.Load(),
+		CacheHits:   e.stats.hits.Load(),
+		CacheMisses: e.stats.misses.Load(),
+		Loads:       e.stats.loads.Load(),
+		LoadErrors:  e.stats.loadErrors.Load(),
+		Changes:     changes,
+	}
+	if nanos := e.stats.lastLoad.Load(); nanos != 0 {
+		stats.LastLoad = time.Unix(0, nanos)
+	}
+	if nanos := e.stats.lastError.Load(); nanos != 0 {
+		stats.LastError = time.Unix(0, nanos)
+	}
+	e.stats.templateErrors.Range(func(name, counter interface{}) bool {
+		if stats.TemplateErrors == nil {
+			stats.TemplateErrors = make(map[string]uint64)
+		}
+		stats.TemplateErrors[name.(string)] = counter.(*atomic.Uint64).Load()
+		return true
+	})
+	return stats
+}
+
+// Names returns the sorted names of the parsed templates.
+func (e *Engine) Names() []string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	names := make([]string, 0, len(e.Templates))
+	for name := range e.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unused returns the sorted names of the parsed templates that have not
+// been rendered since startup.
+func (e *Engine) Unused() []string {
+	var unused []string
+	for _, name := range e.Names() {
+		if _, ok := e.stats.rendered.Load(name); !ok {
+			unused = append(unused, name)
+		}
+	}
+	return unused
+}
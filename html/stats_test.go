@@ -0,0 +1,33 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_Unused(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	names := engine.Names()
+	if unused := engine.Unused(); len(unused) != len(names) {
+		t.Fatalf("Expected:\n%v\nResult:\n%v\n", names, unused)
+	}
+
+	var buf bytes.Buffer
+	engine.Render(&buf, "home", nil)
+	engine.Render(&buf, "missing", nil)
+	unused := engine.Unused()
+	if len(unused) != len(names)-1 {
+		t.Fatalf("Expected %d unused templates\nResult:\n%v\n", len(names)-1, unused)
+	}
+	for _, name := range unused {
+		if name == "home" {
+			t.Fatalf("Expected home to be used\n")
+		}
+	}
+}
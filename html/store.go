@@ -0,0 +1,193 @@
+package html
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss is returned by CacheStore.Get for missing or expired keys
+var ErrCacheMiss = errors.New("cache: miss")
+
+// CacheStore stores the cached pages and fragments, the default keeps
+// them in memory, adapters for Redis or memcache share them between the
+// instances of a deployment. Keys start with the CachePrefix and the
+// version of the templates, stores with a DeletePrefix(ctx, prefix) error
+// method drop the keys of the templates replaced by a load, the others
+// leave them to expire. The keys of the entries tagged with a key are
+// listed under a key updated by Get and Set, instances sharing a store may
+// lose concurrent updates of a list, which Invalidate then misses until
+// the entries expire.
+type CacheStore interface {
+	// Get returns the value of key or ErrCacheMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value under key for ttl, 0 keeps it until deleted
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key, missing keys are not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// defaultCacheSize is the capacity of the default store
+const defaultCacheSize = 10000
+
+// LRUStore is an in-memory CacheStore evicting the least recently used
+// keys once full
+type LRUStore struct {
+	mutex sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	// called with the keys evicted to make room
+	onEvict func(key string)
+}
+
+// lruItem is a value of the LRUStore
+type lruItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUStore returns an in-memory store holding up to size keys.
+func NewLRUStore(size int) *LRUStore {
+	return &LRUStore{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value of key or ErrCacheMiss.
+func (s *LRUStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	item := elem.Value.(*lruItem)
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		s.order.Remove(elem)
+		delete(s.items, key)
+		return nil, ErrCacheMiss
+	}
+	s.order.MoveToFront(elem)
+	return item.value, nil
+}
+
+// Set stores value under key for ttl, 0 keeps it until deleted or evicted.
+func (s *LRUStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	item := &lruItem{key: key, value: value}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+	s.mutex.Lock()
+	if elem, ok := s.items[key]; ok {
+		elem.Value = item
+		s.order.MoveToFront(elem)
+		s.mutex.Unlock()
+		return nil
+	}
+	s.items[key] = s.order.PushFront(item)
+	var evicted []string
+	for s.size > 0 && s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruItem).key)
+		evicted = append(evicted, oldest.Value.(*lruItem).key)
+	}
+	onEvict := s.onEvict
+	s.mutex.Unlock()
+	if onEvict != nil {
+		for _, key := range evicted {
+			onEvict(key)
+		}
+	}
+	return nil
+}
+
+// OnEvict sets the func called with each key evicted to make room, not
+// with the expired or deleted ones.
+func (s *LRUStore) OnEvict(fn func(key string)) *LRUStore {
+	s.mutex.Lock()
+	s.onEvict = fn
+	s.mutex.Unlock()
+	return s
+}
+
+// Delete removes key.
+func (s *LRUStore) Delete(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if elem, ok := s.items[key]; ok {
+		s.order.Remove(elem)
+		delete(s.items, key)
+	}
+	return nil
+}
+
+// DeletePrefix removes the keys starting with prefix.
+func (s *LRUStore) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, elem := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.order.Remove(elem)
+			delete(s.items, key)
+		}
+	}
+	return nil
+}
+
+// Flush removes all keys.
+func (s *LRUStore) Flush(ctx context.Context) error {
+	s.mutex.Lock()
+	s.order.Init()
+	s.items = make(map[string]*list.Element)
+	s.mutex.Unlock()
+	return nil
+}
+
+// Len returns the number of keys.
+func (s *LRUStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.order.Len()
+}
+
+// CacheStore sets the store of the page and fragment caches.
+func (e *Engine) CacheStore(store CacheStore) *Engine {
+	e.cacheStore = store
+	if e.fragments != nil {
+		e.fragments.store = store
+	}
+	if e.pages != nil {
+		e.pages.store = store
+	}
+	if e.responses != nil {
+		e.responses.store = store
+	}
+	return e
+}
+
+// CachePrefix sets the prefix of the keys of the engine in the CacheStore,
+// engines with different funcs or layouts sharing a store must use
+// different prefixes.
+func (e *Engine) CachePrefix(prefix string) *Engine {
+	e.cachePrefix = prefix
+	return e
+}
+
+// store returns the cache store, the default in-memory store unless set.
+func (e *Engine) store() CacheStore {
+	if e.cacheStore == nil {
+		e.cacheStore = NewLRUStore(defaultCacheSize).OnEvict(func(key string) {
+			e.event(context.Background(), slog.LevelDebug, "views: cache evicted", slog.String("key", key), slog.String("reason", "full"))
+		})
+	}
+	return e.cacheStore
+}
@@ -0,0 +1,132 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_LRUStore(t *testing.T) {
+	ctx := context.Background()
+	var evicted []string
+	store := NewLRUStore(2).OnEvict(func(key string) {
+		evicted = append(evicted, key)
+	})
+	store.Set(ctx, "a", []byte("1"), 0)
+	store.Set(ctx, "b", []byte("2"), 0)
+	store.Get(ctx, "a")
+	store.Set(ctx, "c", []byte("3"), 0)
+	if _, err := store.Get(ctx, "b"); err != ErrCacheMiss {
+		t.Fatalf("Expected the least recently used key to be evicted, got %v\n", err)
+	}
+	if value, err := store.Get(ctx, "a"); err != nil || string(value) != "1" {
+		t.Fatalf("Expected a to be kept, got %q %v\n", value, err)
+	}
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("Expected b to be reported evicted, got %v\n", evicted)
+	}
+
+	store.Set(ctx, "d", []byte("4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, err := store.Get(ctx, "d"); err != ErrCacheMiss {
+		t.Fatalf("Expected d to expire, got %v\n", err)
+	}
+	store.Delete(ctx, "a")
+	if store.Len() != 0 {
+		t.Fatalf("Expected no keys, got %d\n", store.Len())
+	}
+}
+
+func Test_CacheStore(t *testing.T) {
+	// Instances sharing a store share the cached pages
+	var renders atomic.Int64
+	store := NewLRUStore(100)
+	newEngine := func() *Engine {
+		engine := New("./testdata/pagecache", ".html")
+		engine.CacheStore(store).RenderCache(0, 0)
+		engine.AddFunc("count", func() int64 {
+			return renders.Add(1)
+		})
+		if err := engine.Load(); err != nil {
+			t.Fatalf("load: %v\n", err)
+		}
+		return engine
+	}
+	first, second := newEngine(), newEngine()
+	ctx := WithCacheKey(context.Background(), "/counter", "home")
+	var buf bytes.Buffer
+	if err := first.RenderContext(ctx, &buf, "counter", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if err := second.RenderContext(ctx, &buf, "counter", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<p>1</p><p>1</p>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Tags invalidate across instances
+	if err := second.Invalidate("home"); err != nil {
+		t.Fatalf("invalidate: %v\n", err)
+	}
+	buf.Reset()
+	if err := first.RenderContext(ctx, &buf, "counter", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := trim(buf.String()); result != "<p>2</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>2</p>", result)
+	}
+}
+
+func Test_CacheNamespaces(t *testing.T) {
+	store := NewLRUStore(2000)
+	ctx := context.Background()
+	other := New("./testdata/pagecache", ".html").CacheStore(store).CachePrefix("other:").RenderCache(0, 0)
+	other.AddFunc("count", func() int64 { return 0 })
+	if err := other.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	if _, err := other.pages.set(ctx, "/kept", "kept", nil); err != nil {
+		t.Fatalf("set: %v\n", err)
+	}
+
+	// A load flushes the keys of its engine only
+	engine := New("./testdata/pagecache", ".html").CacheStore(store).RenderCache(time.Minute, 0)
+	engine.AddFunc("count", func() int64 { return 0 })
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	engine.pages.set(ctx, "/flushed", "flushed", nil)
+	engine.SetDirectory("./testdata/pagecache")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	if _, _, err := engine.pages.get(ctx, "/flushed"); err != ErrCacheMiss {
+		t.Fatalf("Expected the page of the replaced templates to be flushed, got %v\n", err)
+	}
+	if entry, _, err := other.pages.get(ctx, "/kept"); err != nil || entry.HTML != "kept" {
+		t.Fatalf("Expected the page of the other engine to be kept, got %v\n", err)
+	}
+
+	// Tag lists expire with their entries and stay bounded
+	for i := 0; i <= maxTagKeys; i++ {
+		engine.pages.set(ctx, fmt.Sprintf("/page/%d", i), "page", []string{"tag"})
+	}
+	keys, err := engine.pages.dependents(ctx, "tag")
+	if err != nil || len(keys) != maxTagKeys {
+		t.Fatalf("Expected %d tagged keys, got %d %v\n", maxTagKeys, len(keys), err)
+	}
+	if _, _, err := engine.pages.get(ctx, "/page/0"); err != ErrCacheMiss {
+		t.Fatalf("Expected the entry dropped from the tag list to be removed, got %v\n", err)
+	}
+	store.mutex.Lock()
+	expires := store.items[engine.pages.tagKey(ctx, "tag")].Value.(*lruItem).expires
+	store.mutex.Unlock()
+	if expires.IsZero() {
+		t.Fatalf("Expected the tag list to expire\n")
+	}
+}
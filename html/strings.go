@@ -0,0 +1,141 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// stringFuncs are the funcs registered by StringFuncs
+var stringFuncs = template.FuncMap{
+	"title":      title,
+	"upperFirst": upperFirst,
+	"camel":      camel,
+	"snake": func(s string) string {
+		return joinWords(s, "_")
+	},
+	"kebab": func(s string) string {
+		return joinWords(s, "-")
+	},
+	"padLeft": func(width int, pad string, v interface{}) string {
+		return padString(width, pad, v, true)
+	},
+	"padRight": func(width int, pad string, v interface{}) string {
+		return padString(width, pad, v, false)
+	},
+	"trimPrefix": func(prefix, s string) string {
+		return strings.TrimPrefix(s, prefix)
+	},
+	"trimSuffix": func(suffix, s string) string {
+		return strings.TrimSuffix(s, suffix)
+	},
+}
+
+// StringFuncs registers the string case and formatting funcs, which take
+// the string last so they chain in pipelines:
+//   - {{title "hello wörld"}} is Hello Wörld, {{upperFirst "élan"}} is Élan
+//   - {{camel "user_id"}} is userId, {{snake "UserID"}} is user_id and
+//     {{kebab "HTTPServer"}} is http-server
+//   - {{.N | padLeft 5 "0"}} pads to 5 characters, {{padRight 8 "." .Name}}
+//     pads on the right, values longer than width are left as is
+//   - {{.Path | trimPrefix "/"}} and {{trimSuffix ".html" .File}}
+//
+// Widths count characters, not bytes, and words are split on spaces,
+// punctuation and case changes in any script.
+func (e *Engine) StringFuncs() *Engine {
+	for name, fn := range stringFuncs {
+		e.AddFunc(name, fn)
+	}
+	return e
+}
+
+// title returns s with the first letter of each word in title case and the
+// others in lower case.
+func title(s string) string {
+	// a Caser is not safe for concurrent use
+	return cases.Title(language.Und).String(s)
+}
+
+// upperFirst returns s with its first letter in title case.
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToTitle(r)) + s[size:]
+}
+
+// camel returns the words of s joined in lower camel case.
+func camel(s string) string {
+	var b strings.Builder
+	for i, word := range words(s) {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = upperFirst(word)
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// joinWords returns the words of s in lower case joined by sep.
+func joinWords(s, sep string) string {
+	ws := words(s)
+	for i, word := range ws {
+		ws[i] = strings.ToLower(word)
+	}
+	return strings.Join(ws, sep)
+}
+
+// words splits s on the runes other than letters and digits, and before
+// an upper case letter following a lower case one or starting a word after
+// an acronym: HTTPServerID is HTTP, Server and ID.
+func words(s string) []string {
+	var ws []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				ws = append(ws, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			acronymEnd := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || acronymEnd {
+				ws = append(ws, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		ws = append(ws, string(runes[start:]))
+	}
+	return ws
+}
+
+// padString returns v printed and padded with pad up to width characters,
+// on the left or on the right.
+func padString(width int, pad string, v interface{}, left bool) string {
+	s := fmt.Sprint(v)
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 || pad == "" {
+		return s
+	}
+	padding := []rune(strings.Repeat(pad, n/utf8.RuneCountInString(pad)+1))[:n]
+	if left {
+		return string(padding) + s
+	}
+	return s + string(padding)
+}
@@ -0,0 +1,33 @@
+package html
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_StringFuncs(t *testing.T) {
+	engine := New("./testdata/strings", ".html")
+	engine.StringFuncs()
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "page", map[string]interface{}{"N": 42}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<p>Hello Wörld|Élan|userId|user_id|http-server|größe_straße|00042|ab····|docs|index</p>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_Words(t *testing.T) {
+	for s, expect := range map[string]string{
+		"HTTPServerID":   "HTTP Server ID",
+		"userId2Factor":  "user Id2 Factor",
+		"  snake_case  ": "snake case",
+		"ÉtéChaud":       "Été Chaud",
+	} {
+		if result := strings.Join(words(s), " "); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
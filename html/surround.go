@@ -0,0 +1,73 @@
+package html
+
+import (
+	"html/template"
+	"text/template/parse"
+)
+
+// surroundFunc returns the nodes to put before and after node, nil to
+// leave it as is
+type surroundFunc func(tree *parse.Tree, node parse.Node) (begin, end parse.Node, err error)
+
+// surround puts the nodes returned by fn around the nodes of the templates
+// of tmpl, including the nodes nested in if, range and with actions.
+func surround(tmpl *template.Template, fn surroundFunc) error {
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if err := surroundList(t.Tree, t.Tree.Root, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func surroundList(tree *parse.Tree, list *parse.ListNode, fn surroundFunc) error {
+	if list == nil {
+		return nil
+	}
+	nodes := make([]parse.Node, 0, len(list.Nodes))
+	for _, node := range list.Nodes {
+		var err error
+		switch n := node.(type) {
+		case *parse.IfNode:
+			err = surroundBranch(tree, &n.BranchNode, fn)
+		case *parse.RangeNode:
+			err = surroundBranch(tree, &n.BranchNode, fn)
+		case *parse.WithNode:
+			err = surroundBranch(tree, &n.BranchNode, fn)
+		case *parse.ListNode:
+			err = surroundList(tree, n, fn)
+		}
+		if err != nil {
+			return err
+		}
+		begin, end, err := fn(tree, node)
+		if err != nil {
+			return err
+		}
+		if begin != nil {
+			nodes = append(nodes, begin)
+		}
+		nodes = append(nodes, node)
+		if end != nil {
+			nodes = append(nodes, end)
+		}
+	}
+	list.Nodes = nodes
+	return nil
+}
+
+func surroundBranch(tree *parse.Tree, branch *parse.BranchNode, fn surroundFunc) error {
+	if err := surroundList(tree, branch.List, fn); err != nil {
+		return err
+	}
+	return surroundList(tree, branch.ElseList, fn)
+}
+
+// location returns the file:line:col location of node.
+func location(tree *parse.Tree, node parse.Node) string {
+	location, _ := tree.ErrorContext(node)
+	return location
+}
@@ -0,0 +1,47 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// Template returns the parsed template of the given name, nil if the
+// templates are not loaded yet or none has that name.
+func (e *Engine) Template(name string) *template.Template {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.Templates[name]
+}
+
+// SetTemplate adds or replaces the template of the given name. The template
+// is rendered as is, without the layout or context funcs of the engine, and
+// is kept when the views are loaded again.
+func (e *Engine) SetTemplate(name string, tmpl *template.Template) error {
+	if err := e.checkName(name); err != nil {
+		return err
+	}
+	if tmpl == nil {
+		return fmt.Errorf("template: %s is nil", name)
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.overrides == nil {
+		e.overrides = make(map[string]*template.Template)
+	}
+	e.overrides[name] = tmpl
+	// Copy on write, clones may share the parsed templates
+	if e.loaded.Load() {
+		templates := make(map[string]*template.Template, len(e.Templates)+1)
+		for k, v := range e.Templates {
+			templates[k] = v
+		}
+		templates[name] = tmpl
+		e.Templates = templates
+		if set := e.current.Load(); set != nil {
+			published := *set
+			published.templates = templates
+			e.current.Store(&published)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package html
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+)
+
+func Test_Template(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	if engine.Template("index") != nil {
+		t.Fatalf("Expected no template before load\n")
+	}
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	if engine.Template("index") == nil || engine.Template("missing") != nil {
+		t.Fatalf("Expected template lookup by name\n")
+	}
+
+	clone := engine.Clone()
+	tmpl := template.Must(template.New("custom").Parse(`<b>{{.}}</b>`))
+	if err := clone.SetTemplate("custom", tmpl); err != nil {
+		t.Fatalf("set template: %v\n", err)
+	}
+	if err := clone.SetTemplate("../custom", tmpl); err == nil {
+		t.Fatalf("Expected invalid name error\n")
+	}
+	if engine.Template("custom") != nil {
+		t.Fatalf("Expected the original engine to be left alone\n")
+	}
+
+	// Kept across loads
+	clone.Reload(true)
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		if err := clone.Render(&buf, "custom", "set"); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		expect := `<b>set</b>`
+		if result := buf.String(); result != expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+		}
+	}
+}
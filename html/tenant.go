@@ -0,0 +1,198 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Tenant holds the funcs and global data added on top of the shared ones
+// for a tenant, which is selected per render with WithTenant
+type Tenant struct {
+	engine *Engine
+	name   string
+	// replaced on each change, renders read it without the lock
+	data atomic.Pointer[tenantData]
+}
+
+// tenantData is the funcs and globals of a tenant
+type tenantData struct {
+	funcs   map[string]interface{}
+	globals map[string]interface{}
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx selecting the tenant name for the renders using it.
+func WithTenant(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, name)
+}
+
+// Tenant returns the tenant name, creating it if needed.
+func (e *Engine) Tenant(name string) *Tenant {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.tenants == nil {
+		e.tenants = make(map[string]*Tenant)
+	}
+	t := e.tenants[name]
+	if t == nil {
+		t = &Tenant{engine: e, name: name}
+		t.data.Store(&tenantData{})
+		e.tenants[name] = t
+		e.publish()
+	}
+	return t
+}
+
+// AddFunc adds the function for the tenant only, overriding a shared func
+// of the same name. Templates rendered for other tenants get an error
+// when calling it.
+func (t *Tenant) AddFunc(name string, fn interface{}) *Tenant {
+	t.engine.mutex.Lock()
+	d := t.data.Load()
+	funcs := copyMap(d.funcs)
+	if funcs == nil {
+		funcs = make(map[string]interface{})
+	}
+	funcs[name] = fn
+	t.data.Store(&tenantData{funcs: funcs, globals: d.globals})
+	t.engine.mutex.Unlock()
+	t.engine.AddContextFunc(name, t.engine.tenantFunc(name))
+	return t
+}
+
+// Globals adds data merged into the bindings rendered for the tenant, on
+// top of the shared globals.
+func (t *Tenant) Globals(data map[string]interface{}) *Tenant {
+	t.engine.mutex.Lock()
+	d := t.data.Load()
+	globals := copyMap(d.globals)
+	if globals == nil {
+		globals = make(map[string]interface{})
+	}
+	for k, v := range data {
+		globals[k] = v
+	}
+	t.data.Store(&tenantData{funcs: d.funcs, globals: globals})
+	t.engine.mutex.Unlock()
+	return t
+}
+
+// Globals adds data merged into every map binding, the handler data takes
+// precedence over tenant globals which take precedence over these.
+func (e *Engine) Globals(data map[string]interface{}) *Engine {
+	e.mutex.Lock()
+	if e.globals == nil {
+		e.globals = make(map[string]interface{})
+	}
+	for k, v := range data {
+		e.globals[k] = v
+	}
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+// tenant returns the tenant selected by ctx, or nil.
+func (e *Engine) tenant(ctx context.Context) *Tenant {
+	name, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return e.published().tenants[name]
+}
+
+// tenantFunc returns the context func calling the func name of the tenant
+// selected by the render.
+func (e *Engine) tenantFunc(name string) func(ctx context.Context, args ...interface{}) (interface{}, error) {
+	return func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return e.callTenantFunc(ctx, name, args)
+	}
+}
+
+// callTenantFunc calls the func name of the tenant selected by ctx, or the
+// shared func of the same name.
+func (e *Engine) callTenantFunc(ctx context.Context, name string, args []interface{}) (interface{}, error) {
+	fn := e.renderVersion(ctx).funcs[name]
+	if t := e.tenant(ctx); t != nil {
+		if tfn, ok := t.data.Load().funcs[name]; ok {
+			fn = tfn
+		}
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("func %s is not available for this tenant", name)
+	}
+	return callFunc(fn, args)
+}
+
+// withGlobals returns binding merged over the shared and tenant globals,
+// bindings that are not maps are returned as is.
+func (e *Engine) withGlobals(ctx context.Context, binding interface{}) interface{} {
+	globals := e.published().globals
+	var tenantGlobals map[string]interface{}
+	if t := e.tenant(ctx); t != nil {
+		tenantGlobals = t.data.Load().globals
+	}
+	if len(globals) == 0 && len(tenantGlobals) == 0 {
+		return binding
+	}
+	var data reflect.Value
+	if binding != nil {
+		data = reflect.ValueOf(binding)
+		if data.Kind() != reflect.Map || data.Type().Key().Kind() != reflect.String {
+			return binding
+		}
+	}
+	result := Merge(globals, tenantGlobals)
+	mergeValue(result, data)
+	return map[string]interface{}(result)
+}
+
+// errorType is the type of the optional second result of funcs
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// callFunc calls fn with args converted to its parameter types.
+func callFunc(fn interface{}, args []interface{}) (interface{}, error) {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%v is not a func", t)
+	}
+	n := t.NumIn()
+	if t.IsVariadic() && len(args) < n-1 || !t.IsVariadic() && len(args) != n {
+		return nil, fmt.Errorf("wrong number of args: got %d want %d", len(args), n)
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		pt := t.In(n - 1)
+		if t.IsVariadic() && i >= n-1 {
+			pt = pt.Elem()
+		} else {
+			pt = t.In(i)
+		}
+		if arg == nil {
+			in[i] = reflect.Zero(pt)
+			continue
+		}
+		av := reflect.ValueOf(arg)
+		switch {
+		case av.Type().AssignableTo(pt):
+			in[i] = av
+		case av.Type().ConvertibleTo(pt) && av.Kind() != reflect.String && pt.Kind() != reflect.String:
+			in[i] = av.Convert(pt)
+		default:
+			return nil, fmt.Errorf("arg %d: cannot use %v as %v", i, av.Type(), pt)
+		}
+	}
+	out := v.Call(in)
+	switch {
+	case len(out) == 1:
+		return out[0].Interface(), nil
+	case len(out) == 2 && t.Out(1) == errorType:
+		err, _ := out[1].Interface().(error)
+		return out[0].Interface(), err
+	}
+	return nil, fmt.Errorf("func must return a value and an optional error")
+}
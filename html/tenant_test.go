@@ -0,0 +1,74 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_Tenant(t *testing.T) {
+	engine := New("./testdata/tenant", ".html")
+	engine.Globals(map[string]interface{}{
+		"Site":  "Shared",
+		"Title": "Default",
+	})
+	engine.Tenant("acme").
+		AddFunc("brandColor", func() string { return "red" }).
+		Globals(map[string]interface{}{"Site": "Acme"})
+	engine.Tenant("globex")
+
+	var buf bytes.Buffer
+	ctx := WithTenant(context.Background(), "acme")
+	if err := engine.RenderContext(ctx, &buf, "index", map[string]interface{}{
+		"Title": "Home",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h1 style="color: red">Acme Home</h1>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Tenant funcs do not leak
+	buf.Reset()
+	ctx = WithTenant(context.Background(), "globex")
+	if err := engine.RenderContext(ctx, &buf, "index", nil); err == nil {
+		t.Fatalf("Expected error for func of another tenant\n")
+	}
+
+	// Clones get tenants of their own
+	clone := engine.Clone()
+	clone.Tenant("acme").
+		AddFunc("brandColor", func() string { return "blue" }).
+		Globals(map[string]interface{}{"Site": "Acme Clone"})
+	buf.Reset()
+	ctx = WithTenant(context.Background(), "acme")
+	if err := clone.RenderContext(ctx, &buf, "index", map[string]interface{}{"Title": "Home"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<h1 style="color: blue">Acme Clone Home</h1>`
+	if result := trim(buf.String()); expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	buf.Reset()
+	if err := engine.RenderContext(ctx, &buf, "index", map[string]interface{}{"Title": "Home"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<h1 style="color: red">Acme Home</h1>`
+	if result := trim(buf.String()); expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_CallFunc(t *testing.T) {
+	result, err := callFunc(func(a int64, rest ...string) string {
+		return string(rune('a'+a)) + rest[0]
+	}, []interface{}{1, "c"})
+	if err != nil || result != "bc" {
+		t.Fatalf("Expected:\nbc\nResult:\n%v %v\n", result, err)
+	}
+	if _, err := callFunc(func(s string) string { return s }, []interface{}{1}); err == nil {
+		t.Fatalf("Expected error for int as string\n")
+	}
+}
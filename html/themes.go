@@ -0,0 +1,49 @@
+package html
+
+import (
+	"context"
+	"html/template"
+	"io/fs"
+)
+
+// templateSet holds the parsed templates of a views tree
+type templateSet struct {
+	templates map[string]*template.Template
+	preloads  map[string][]Preload
+}
+
+// Themes sets the theme views by name. A theme resolves the same template
+// names as the views folder, each file missing from the theme falls back
+// to the views folder, including the layout.
+func (e *Engine) Themes(themes map[string]fs.FS) *Engine {
+	e.mutex.Lock()
+	e.themes = themes
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// DefaultTheme sets the theme used when the render context selects none,
+// the empty name renders the views folder.
+func (e *Engine) DefaultTheme(name string) *Engine {
+	e.mutex.Lock()
+	e.defaultTheme = name
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+type themeKey struct{}
+
+// WithTheme returns a copy of ctx selecting the theme name for the renders using it.
+func WithTheme(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, themeKey{}, name)
+}
+
+// theme returns the theme selected by ctx or the default theme.
+func (e *Engine) theme(ctx context.Context) string {
+	if name, ok := ctx.Value(themeKey{}).(string); ok {
+		return name
+	}
+	return e.published().defaultTheme
+}
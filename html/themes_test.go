@@ -0,0 +1,134 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_Themes(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.Themes(map[string]fs.FS{
+		"brand": fstest.MapFS{
+			"layouts/main.html": {Data: []byte(`<main class="brand">{{block "content" .}}{{end}}</main>`)},
+			"errors/404.html":   {Data: []byte(`{{define "content"}}<h1>Brand {{.Error}}</h1>{{end}}`)},
+		},
+	})
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	// Layout from the theme, page from the views folder
+	var buf bytes.Buffer
+	ctx := WithTheme(context.Background(), "brand")
+	if err := engine.RenderContext(ctx, &buf, "index", map[string]interface{}{
+		"Title": "Hello, World!",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<main class="brand"><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></main>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Both from the theme
+	buf.Reset()
+	if err := engine.RenderContext(ctx, &buf, "errors/404", map[string]interface{}{
+		"Error": "404 Not Found!",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<main class="brand"><h1>Brand 404 Not Found!</h1></main>`
+	result = trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// No theme
+	buf.Reset()
+	if err := engine.Render(&buf, "index", map[string]interface{}{
+		"Title": "Hello, World!",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<!DOCTYPE html><html><head><title>Main</title></head><body><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2></body></html>`
+	result = trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	// Unknown theme
+	if err := engine.RenderContext(WithTheme(context.Background(), "missing"), &buf, "index", nil); err == nil {
+		t.Fatalf("Expected error for missing theme\n")
+	}
+}
+
+func Test_ThemeLayouts(t *testing.T) {
+	engine := New("", ".html")
+	engine.SetFS(fstest.MapFS{
+		"layouts/main.html":  {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+		"layouts/print.html": {Data: []byte(`<article>{{block "content" .}}{{end}}</article>`)},
+		"index.html":         {Data: []byte(`{{define "content"}}Home{{end}}`)},
+	})
+	engine.Layout("layouts/main").Layouts("layouts/print")
+	engine.Themes(map[string]fs.FS{
+		"brand": fstest.MapFS{
+			"layouts/print.html": {Data: []byte(`<article class="brand">{{block "content" .}}{{end}}</article>`)},
+			"index.html":         {Data: []byte(`{{define "content"}}Brand home{{end}}`)},
+		},
+	})
+	for _, test := range []struct {
+		theme, layout, expect string
+	}{
+		{"", "layouts/print", `<article>Home</article>`},
+		{"brand", "layouts/print", `<article class="brand">Brand home</article>`},
+		// The theme falls back to the layout of the views folder
+		{"brand", "layouts/main", `<main>Brand home</main>`},
+	} {
+		var buf bytes.Buffer
+		ctx := UseLayout(WithTheme(context.Background(), test.theme), test.layout)
+		if err := engine.RenderContext(ctx, &buf, "index", nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		if result := trim(buf.String()); result != test.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", test.expect, result)
+		}
+	}
+	// Unknown themes fail with alternate layouts too
+	ctx := UseLayout(WithTheme(context.Background(), "missing"), "layouts/print")
+	if err := engine.RenderContext(ctx, &bytes.Buffer{}, "index", nil); err == nil || !strings.Contains(err.Error(), "theme missing") {
+		t.Fatalf("Expected error for missing theme, got %v\n", err)
+	}
+}
+
+// brokenFS fails with err to open the files missing from its MapFS
+type brokenFS struct {
+	fstest.MapFS
+	err error
+}
+
+func (b brokenFS) Open(name string) (fs.File, error) {
+	if name == "." || b.MapFS[name] != nil {
+		return b.MapFS.Open(name)
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: b.err}
+}
+
+func Test_OverlayFS(t *testing.T) {
+	lower := fstest.MapFS{"index.html": {Data: []byte("lower")}}
+	upper := brokenFS{MapFS: fstest.MapFS{}, err: fs.ErrNotExist}
+	if buf, err := fs.ReadFile(overlayFS{upper, lower}, "index.html"); err != nil || string(buf) != "lower" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s %v\n", "lower", buf, err)
+	}
+	// Only missing files fall back
+	upper.err = fs.ErrPermission
+	if _, err := fs.ReadFile(overlayFS{upper, lower}, "index.html"); !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("Expected:\n%v\nResult:\n%v\n", fs.ErrPermission, err)
+	}
+}
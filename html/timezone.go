@@ -0,0 +1,120 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type timezoneKey struct{}
+
+// WithTimezone returns a copy of ctx selecting the timezone of the viewer
+// for the renders using it, e.g. from a middleware reading a cookie.
+func WithTimezone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timezoneKey{}, loc)
+}
+
+// Timezone returns the timezone selected by ctx, nil if none.
+func Timezone(ctx context.Context) *time.Location {
+	loc, _ := ctx.Value(timezoneKey{}).(*time.Location)
+	return loc
+}
+
+// DefaultTimezone sets the timezone used when the render context selects
+// none, UTC unless set.
+func (e *Engine) DefaultTimezone(loc *time.Location) *Engine {
+	e.defaultTimezone = loc
+	return e
+}
+
+// timezone returns the timezone of the render.
+func (e *Engine) timezone(ctx context.Context) *time.Location {
+	if loc := Timezone(ctx); loc != nil {
+		return loc
+	}
+	if e.defaultTimezone != nil {
+		return e.defaultTimezone
+	}
+	return time.UTC
+}
+
+// TimeFuncs registers time helpers driven by the render context:
+// {{localtime .CreatedAt}} returns the time in the timezone of the viewer,
+// to be formatted with {{(localtime .CreatedAt).Format "Jan 2 15:04"}},
+// and {{timeago .CreatedAt}} returns the relative time, see TimeAgo.
+func (e *Engine) TimeFuncs() *Engine {
+	e.AddContextFunc("timeago", e.timeago)
+	return e.AddContextFunc("localtime", func(ctx context.Context, t time.Time) time.Time {
+		return t.In(e.timezone(ctx))
+	})
+}
+
+// now returns the current time, tests pin it
+var now = time.Now
+
+// TimeAgo sets the age from which timeago renders the date with layout in
+// the timezone of the viewer instead of 3 minutes ago, a week and
+// Jan 2, 2006 unless set.
+func (e *Engine) TimeAgo(threshold time.Duration, layout string) *Engine {
+	e.timeagoThreshold = threshold
+	e.timeagoLayout = layout
+	return e
+}
+
+// timeago returns how long ago or from now t is. The phrases are messages
+// of the translator or catalog, such as "%d minutes ago" and "in %d minute"
+// by count, "just now" and "in a moment", English without translation.
+func (e *Engine) timeago(ctx context.Context, t time.Time) string {
+	d := now().Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	threshold, layout := e.timeagoThreshold, e.timeagoLayout
+	if threshold == 0 {
+		threshold = 7 * 24 * time.Hour
+	}
+	if layout == "" {
+		layout = "Jan 2, 2006"
+	}
+	if d >= threshold {
+		return t.In(e.timezone(ctx)).Format(layout)
+	}
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		if future {
+			return e.message(ctx, "in a moment")
+		}
+		return e.message(ctx, "just now")
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	default:
+		n, unit = int(d/(24*time.Hour)), "day"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return e.message(ctx, "in %d "+unit, n)
+	}
+	return e.message(ctx, "%d "+unit+" ago", n)
+}
+
+// message formats the message key with args in the locale of ctx.
+func (e *Engine) message(ctx context.Context, key string, args ...interface{}) string {
+	if e.translator != nil {
+		for _, locale := range e.localeChain(ctx) {
+			if message, ok := e.translator.Translate(locale, key, args...); ok {
+				return message
+			}
+		}
+	}
+	if e.catalog != nil {
+		return e.catalog.printer(e.localeChain(ctx)).Sprintf(key, args...)
+	}
+	return fmt.Sprintf(key, args...)
+}
@@ -0,0 +1,86 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_LocalTime(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	paris := time.FixedZone("CET", 3600)
+	engine := New("./testdata/time", ".html")
+	engine.TimeFuncs()
+	binding := map[string]interface{}{"At": time.Date(2024, 1, 2, 20, 30, 0, 0, time.UTC)}
+	render := func(ctx context.Context) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.RenderContext(ctx, &buf, "localtime", binding); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		return trim(buf.String())
+	}
+
+	if result := render(context.Background()); result != "<time>2024-01-02 20:30 UTC</time>" {
+		t.Fatalf("Expected UTC\nResult:\n%s\n", result)
+	}
+	if result := render(WithTimezone(context.Background(), tokyo)); result != "<time>2024-01-03 05:30 JST</time>" {
+		t.Fatalf("Expected the viewer timezone\nResult:\n%s\n", result)
+	}
+	engine.DefaultTimezone(paris)
+	if result := render(context.Background()); result != "<time>2024-01-02 21:30 CET</time>" {
+		t.Fatalf("Expected the default timezone\nResult:\n%s\n", result)
+	}
+}
+
+func Test_TimeAgo(t *testing.T) {
+	pinned := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return pinned }
+	defer func() { now = time.Now }()
+
+	engine := New("./testdata/time", ".html")
+	engine.TimeFuncs()
+	render := func(ctx context.Context, at time.Time) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.RenderContext(ctx, &buf, "timeago", map[string]interface{}{"At": at}); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		return trim(buf.String())
+	}
+	cases := []struct {
+		at     time.Time
+		expect string
+	}{
+		{pinned.Add(-10 * time.Second), "<p>just now</p>"},
+		{pinned.Add(-time.Minute), "<p>1 minute ago</p>"},
+		{pinned.Add(-3 * time.Minute), "<p>3 minutes ago</p>"},
+		{pinned.Add(-5 * time.Hour), "<p>5 hours ago</p>"},
+		{pinned.Add(-2 * 24 * time.Hour), "<p>2 days ago</p>"},
+		{pinned.Add(2 * time.Hour), "<p>in 2 hours</p>"},
+		{pinned.Add(-8 * 24 * time.Hour), "<p>Jan 2, 2024</p>"},
+	}
+	for _, c := range cases {
+		if result := render(context.Background(), c.at); result != c.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", c.expect, result)
+		}
+	}
+
+	// Translated and with a custom threshold
+	engine.Translator(TranslatorFunc(func(locale, key string, args ...interface{}) (string, bool) {
+		if locale == "fr" && key == "%d minutes ago" {
+			return fmt.Sprintf("il y a %d minutes", args...), true
+		}
+		return "", false
+	}))
+	engine.TimeAgo(time.Hour, "02/01/2006")
+	ctx := WithLocale(context.Background(), "fr")
+	if result := render(ctx, pinned.Add(-3*time.Minute)); result != "<p>il y a 3 minutes</p>" {
+		t.Fatalf("Expected the translated phrase\nResult:\n%s\n", result)
+	}
+	if result := render(ctx, pinned.Add(-2*time.Hour)); result != "<p>10/01/2024</p>" {
+		t.Fatalf("Expected the date past the threshold\nResult:\n%s\n", result)
+	}
+}
@@ -0,0 +1,66 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// traceFunc is the func writing the comments around included templates
+const traceFunc = "_htmlTrace"
+
+// TraceComments if set to true wraps the output of each template included
+// with template or block in <!-- begin name --> and <!-- end name -->
+// comments, so page regions can be mapped back to files in view-source.
+// The begin comment carries the trace ID returned by traceID when it is
+// not nil. It is meant for development, includes in script or attribute
+// contexts get the comments escaped.
+func (e *Engine) TraceComments(enabled bool, traceID func(ctx context.Context) string) *Engine {
+	e.traceComments = enabled
+	e.traceID = traceID
+	return e
+}
+
+// traceComment returns the begin or end comment of the template name.
+func (e *Engine) traceComment(ctx context.Context, begin bool, name string) template.HTML {
+	// Comments cannot contain --
+	name = strings.ReplaceAll(name, "--", "")
+	if !begin {
+		return template.HTML("<!-- end " + name + " -->")
+	}
+	if e.traceID != nil {
+		if id := strings.ReplaceAll(e.traceID(ctx), "--", ""); id != "" {
+			return template.HTML("<!-- begin " + name + " trace=" + id + " -->")
+		}
+	}
+	return template.HTML("<!-- begin " + name + " -->")
+}
+
+// instrumentTrace surrounds the sub-template calls of the templates of
+// tmpl with trace comments.
+func instrumentTrace(tmpl *template.Template) error {
+	return surround(tmpl, func(tree *parse.Tree, node parse.Node) (begin, end parse.Node, err error) {
+		n, ok := node.(*parse.TemplateNode)
+		if !ok {
+			return nil, nil, nil
+		}
+		if begin, err = traceNode(true, n.Name); err != nil {
+			return nil, nil, err
+		}
+		end, err = traceNode(false, n.Name)
+		return begin, end, err
+	})
+}
+
+// traceNode returns an action writing the begin or end comment of name.
+func traceNode(begin bool, name string) (parse.Node, error) {
+	src := fmt.Sprintf("{{%s %t %s}}", traceFunc, begin, strconv.Quote(name))
+	parsed, err := parse.Parse("trace", src, "{{", "}}", map[string]interface{}{traceFunc: fmt.Sprint})
+	if err != nil {
+		return nil, err
+	}
+	return parsed["trace"].Root.Nodes[0], nil
+}
@@ -0,0 +1,28 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_TraceComments(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.TraceComments(true, func(ctx context.Context) string {
+		id, _ := Value(ctx, "trace").(string)
+		return id
+	})
+	var buf bytes.Buffer
+	ctx := WithValue(context.Background(), "trace", "4bf92f35")
+	if err := engine.RenderContext(ctx, &buf, "index", map[string]interface{}{"Title": "Hello, World!"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<!DOCTYPE html><html><head><title>Main</title></head><body><!-- begin content trace=4bf92f35 --><h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2><!-- end content --></body></html>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
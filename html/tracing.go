@@ -0,0 +1,33 @@
+package html
+
+import (
+	"context"
+	"io"
+)
+
+// Tracer starts spans around Load and Render, implementations are
+// expected to wrap a tracing system such as OpenTelemetry.
+type Tracer interface {
+	// Start starts a span named operation as a child of the span in ctx
+	Start(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	// SetAttribute records key with value, value is a string, int or bool
+	SetAttribute(key string, value interface{})
+	// End finishes the span, err is the error of the operation if any
+	End(err error)
+}
+
+// countWriter counts the bytes written to w
+type countWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
@@ -0,0 +1,82 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attrs[key] = value
+}
+
+func (s *testSpan) End(err error) {
+	s.ended = true
+}
+
+type testTracer struct {
+	mutex sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, operation string) (context.Context, Span) {
+	span := &testSpan{name: operation, attrs: make(map[string]interface{})}
+	t.mutex.Lock()
+	t.spans = append(t.spans, span)
+	t.mutex.Unlock()
+	return ctx, span
+}
+
+func Test_Tracer(t *testing.T) {
+	tracer := &testTracer{}
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.Tracer(tracer)
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	var buf bytes.Buffer
+	if err := engine.RenderContext(context.Background(), &buf, "index", map[string]interface{}{
+		"Title": "Hello, World!",
+	}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+
+	if len(tracer.spans) != 2 || tracer.spans[0].name != "html.Render" || tracer.spans[1].name != "html.Load" {
+		t.Fatalf("Expected:\nhtml.Render, html.Load\nResult:\n%v\n", tracer.spans)
+	}
+	render := tracer.spans[0]
+	if !render.ended || render.attrs["template"] != "index" || render.attrs["layout"] != "layouts/main" ||
+		render.attrs["bytes"] != buf.Len() || render.attrs["cache_hit"] != false {
+		t.Fatalf("Unexpected render span: %v\n", render.attrs)
+	}
+}
+
+func Test_TracerDuringReloads(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Tracer(&testTracer{})
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.Reload(true)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			if err := engine.Render(&buf, "home", nil); err != nil {
+				t.Errorf("render: %v\n", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,22 @@
+package html
+
+// Transform adds a func mapping the binding of each render before the
+// template runs, e.g. to turn domain entities into view models or redact
+// fields in one place. Transforms run in the order they were added, with
+// the requested template name, before the globals are merged. An error
+// fails the render.
+func (e *Engine) Transform(fn func(name string, binding interface{}) (interface{}, error)) *Engine {
+	e.transforms = append(e.transforms, fn)
+	return e
+}
+
+// transform runs the Transform chain on binding.
+func (e *Engine) transform(name string, binding interface{}) (interface{}, error) {
+	var err error
+	for _, fn := range e.transforms {
+		if binding, err = fn(name, binding); err != nil {
+			return nil, err
+		}
+	}
+	return binding, nil
+}
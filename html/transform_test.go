@@ -0,0 +1,43 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type transformUser struct {
+	Name     string
+	Password string
+}
+
+func Test_Transform(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.Transform(func(name string, binding interface{}) (interface{}, error) {
+		if user, ok := binding.(transformUser); ok {
+			return map[string]interface{}{"Error": user.Name + " " + strings.Repeat("*", len(user.Password))}, nil
+		}
+		return binding, nil
+	}).Transform(func(name string, binding interface{}) (interface{}, error) {
+		if name == "admin" {
+			return nil, errors.New("forbidden")
+		}
+		return binding, nil
+	})
+
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "errors/404", transformUser{Name: "ann", Password: "secret"}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h1>ann ******</h1>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if err := engine.Render(&buf, "admin", nil); err == nil || err.Error() != "forbidden" {
+		t.Fatalf("Expected transform error, got %v\n", err)
+	}
+}
@@ -0,0 +1,55 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// TurboStreamContentType is the content type of Turbo Stream responses
+const TurboStreamContentType = "text/vnd.turbo-stream.html"
+
+// TurboStream describes a single <turbo-stream> element
+type TurboStream struct {
+	// append, prepend, replace, update, remove, before, after or refresh
+	Action string
+	// id of the target element, omitted if empty as for refresh
+	Target string
+	// CSS selector of the target elements, used instead of Target
+	Targets string
+	// template rendered without layout into the stream, optional for remove
+	Template string
+	// values passed to the template
+	Binding interface{}
+}
+
+// RenderTurboStream renders each template wrapped in a <turbo-stream> envelope.
+func (e *Engine) RenderTurboStream(out io.Writer, streams ...TurboStream) error {
+	for _, stream := range streams {
+		if stream.Action == "" {
+			return fmt.Errorf("turbo: action is required")
+		}
+		attrs := fmt.Sprintf(` action="%s"`, template.HTMLEscapeString(stream.Action))
+		if stream.Targets != "" {
+			attrs += fmt.Sprintf(` targets="%s"`, template.HTMLEscapeString(stream.Targets))
+		} else if stream.Target != "" {
+			attrs += fmt.Sprintf(` target="%s"`, template.HTMLEscapeString(stream.Target))
+		}
+		if stream.Template == "" {
+			if _, err := fmt.Fprintf(out, "<turbo-stream%s></turbo-stream>", attrs); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "<turbo-stream%s><template>", attrs); err != nil {
+			return err
+		}
+		if err := e.RenderPartial(out, stream.Template, stream.Binding); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(out, "</template></turbo-stream>"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
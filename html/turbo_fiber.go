@@ -0,0 +1,13 @@
+//go:build !noos
+
+package html
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// TurboStream responds with the given streams and the Turbo Stream content type.
+func (e *Engine) TurboStream(c *fiber.Ctx, streams ...TurboStream) error {
+	c.Set(fiber.HeaderContentType, TurboStreamContentType)
+	return e.RenderTurboStream(c, streams...)
+}
@@ -0,0 +1,37 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_RenderTurboStream(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Layout("layouts/main")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+
+	var buf bytes.Buffer
+	err := engine.RenderTurboStream(&buf, TurboStream{
+		Action:   "replace",
+		Target:   "error",
+		Template: "errors/404",
+		Binding: map[string]interface{}{
+			"Error": "404 Not Found!",
+		},
+	}, TurboStream{
+		Action: "remove",
+		Target: "flash",
+	}, TurboStream{
+		Action: "refresh",
+	})
+	if err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<turbo-stream action="replace" target="error"><template><h1>404 Not Found!</h1></template></turbo-stream><turbo-stream action="remove" target="flash"></turbo-stream><turbo-stream action="refresh"></turbo-stream>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
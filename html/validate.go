@@ -0,0 +1,131 @@
+package html
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BindingError lists the data a template requires that its binding lacks
+type BindingError struct {
+	Template string
+	Missing  []string
+}
+
+func (e *BindingError) Error() string {
+	return fmt.Sprintf("render: %s: missing binding data: %s", e.Template, strings.Join(e.Missing, ", "))
+}
+
+// ValidateBindings if set to true checks the bindings before rendering,
+// the keys registered by Require and the struct fields tagged
+// view:"required" must be set to a value other than zero, otherwise the render fails with a
+// *BindingError instead of leaving blanks in the page.
+func (e *Engine) ValidateBindings(enabled bool) *Engine {
+	e.validateBindings = enabled
+	return e
+}
+
+// Require declares the binding data of the template name, each key is a
+// map key or field name, dotted for nested values such as User.Name. The
+// keys are checked if ValidateBindings is enabled.
+func (e *Engine) Require(name string, keys ...string) *Engine {
+	if e.required == nil {
+		e.required = make(map[string][]string)
+	}
+	e.required[name] = append(e.required[name], keys...)
+	return e
+}
+
+// validateBinding checks the binding of the template name.
+func (e *Engine) validateBinding(name string, binding interface{}) error {
+	if !e.validateBindings {
+		return nil
+	}
+	var missing []string
+	for _, key := range e.required[name] {
+		if v, ok := bindingPath(reflect.ValueOf(binding), strings.Split(key, ".")); !ok || isEmpty(v) {
+			missing = append(missing, key)
+		}
+	}
+	missing = requiredFields(reflect.ValueOf(binding), "", missing)
+	if len(missing) > 0 {
+		return &BindingError{Template: name, Missing: missing}
+	}
+	return nil
+}
+
+// bindingPath returns the value at path from v.
+func bindingPath(v reflect.Value, path []string) (reflect.Value, bool) {
+	for _, key := range path {
+		v = indirect(v)
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			v = v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		case reflect.Struct:
+			f, ok := v.Type().FieldByName(key)
+			if !ok || !f.IsExported() {
+				return reflect.Value{}, false
+			}
+			v = v.FieldByIndex(f.Index)
+		default:
+			return reflect.Value{}, false
+		}
+		if !v.IsValid() {
+			return v, false
+		}
+	}
+	return v, true
+}
+
+// requiredFields appends the fields tagged view:"required" of the struct
+// v that are zero, prefix is the path of v.
+func requiredFields(v reflect.Value, prefix string, missing []string) []string {
+	v = indirect(v)
+	if v.Kind() != reflect.Struct {
+		return missing
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		field := v.Field(i)
+		if hasTag(f.Tag.Get("view"), "required") && isEmpty(field) {
+			missing = append(missing, prefix+f.Name)
+			continue
+		}
+		// Pointers are not followed, they may form cycles
+		if field.Kind() == reflect.Struct {
+			missing = requiredFields(field, prefix+f.Name+".", missing)
+		}
+	}
+	return missing
+}
+
+// hasTag tells whether the comma separated tag has option.
+func hasTag(tag, option string) bool {
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// indirect follows the pointers and interfaces of v.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// isEmpty tells whether v is missing or the zero value.
+func isEmpty(v reflect.Value) bool {
+	v = indirect(v)
+	return !v.IsValid() || v.IsZero()
+}
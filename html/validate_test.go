@@ -0,0 +1,52 @@
+package html
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type validatedAuthor struct {
+	Name string `view:"required"`
+}
+
+type validatedPage struct {
+	Title  string `view:"required"`
+	Author validatedAuthor
+	Intro  string
+}
+
+func Test_ValidateBindings(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.ValidateBindings(true).Require("errors/404", "Error", "User.Name")
+
+	var buf bytes.Buffer
+	err := engine.Render(&buf, "errors/404", map[string]interface{}{
+		"Error": "404 Not Found!",
+		"User":  map[string]interface{}{},
+	})
+	var binding *BindingError
+	if !errors.As(err, &binding) {
+		t.Fatalf("Expected binding error, got %v\n", err)
+	}
+	expect := `render: errors/404: missing binding data: User.Name`
+	if result := err.Error(); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Expected nothing rendered, got %q\n", buf.String())
+	}
+
+	// Struct tags
+	err = engine.Render(&buf, "index", &validatedPage{Intro: "intro"})
+	expect = `render: index: missing binding data: Title, Author.Name`
+	if err == nil || err.Error() != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%v\n", expect, err)
+	}
+	if err = engine.Render(&buf, "index", &validatedPage{Title: "Title", Author: validatedAuthor{Name: "Ann"}}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+}
@@ -0,0 +1,32 @@
+package html
+
+import "context"
+
+// valuesKey is the context key of the request values
+type valuesKey struct{}
+
+// WithValue returns a copy of ctx carrying the request value key, e.g. the
+// current user or a CSP nonce stashed by a middleware. Unlike
+// context.WithValue the keys are plain strings shared by the helpers of
+// this package, context funcs and the value template func.
+func WithValue(ctx context.Context, key string, value interface{}) context.Context {
+	values, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	copied := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		copied[k] = v
+	}
+	copied[key] = value
+	return context.WithValue(ctx, valuesKey{}, copied)
+}
+
+// Value returns the request value key of ctx, nil if none was set.
+func Value(ctx context.Context, key string) interface{} {
+	values, _ := ctx.Value(valuesKey{}).(map[string]interface{})
+	return values[key]
+}
+
+// ValueFunc registers {{value "key"}} returning the request value key of
+// the render context.
+func (e *Engine) ValueFunc() *Engine {
+	return e.AddContextFunc("value", Value)
+}
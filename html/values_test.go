@@ -0,0 +1,48 @@
+//go:build !noos
+
+package html
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func Test_Value(t *testing.T) {
+	ctx := WithValue(context.Background(), "user", "john")
+	child := WithValue(ctx, "nonce", "abc")
+	if Value(ctx, "nonce") != nil || Value(child, "user") != "john" || Value(child, "nonce") != "abc" {
+		t.Fatalf("Expected values to be inherited and not leak to the parent\n")
+	}
+	if Value(context.Background(), "user") != nil {
+		t.Fatalf("Expected no value\n")
+	}
+
+	engine := New("./testdata/values", ".html")
+	engine.ValueFunc()
+	engine.AddContextFunc("greeting", func(ctx context.Context) string {
+		return "Hello " + Value(ctx, "user").(string)
+	})
+
+	app := fiber.New(fiber.Config{Views: engine})
+	app.Use(func(c *fiber.Ctx) error {
+		ctx := WithValue(c.UserContext(), "user", "john")
+		c.SetUserContext(WithValue(ctx, "nonce", "abc"))
+		return c.Next()
+	})
+	app.Get("/", func(c *fiber.Ctx) error {
+		return engine.RenderContext(c.UserContext(), c, "index", nil)
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("request: %v\n", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	expect := `<p data-nonce="abc">Hello john</p>`
+	if result := trim(string(body)); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
@@ -0,0 +1,72 @@
+package html
+
+import (
+	"context"
+	"time"
+)
+
+// RenderInfo describes a finished render
+type RenderInfo struct {
+	// requested template name
+	Template string
+	// template actually rendered, after variant and locale resolution
+	Page string
+	// variant key if the template is an experiment and the key is known
+	Variant string
+	// true if rendered without layout
+	Partial bool
+	// render duration
+	Duration time.Duration
+	// render error if any
+	Err error
+}
+
+// OnRender adds a hook called after each render, e.g. to record the
+// exposure of an experiment when info.Variant is set.
+func (e *Engine) OnRender(fn func(ctx context.Context, info RenderInfo)) *Engine {
+	e.onRender = append(e.onRender, fn)
+	return e
+}
+
+// Variant makes the template name an experiment: the render context
+// variant key selects the template rendered in its place, e.g.
+// Variant("home", map[string]string{"A": "home_a", "B": "home_b"}).
+// Unknown or missing keys render name itself.
+func (e *Engine) Variant(name string, variants map[string]string) *Engine {
+	e.mutex.Lock()
+	if e.variants == nil {
+		e.variants = make(map[string]map[string]string)
+	}
+	copied := make(map[string]string, len(variants))
+	for key, variant := range variants {
+		copied[key] = variant
+	}
+	e.variants[name] = copied
+	e.publish()
+	e.mutex.Unlock()
+	return e
+}
+
+type variantKeyType struct{}
+
+// WithVariant returns a copy of ctx selecting the variant key, usually the
+// user's experiment bucket, for the renders using it.
+func WithVariant(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, variantKeyType{}, key)
+}
+
+// variantKey returns the variant key selected by ctx.
+func variantKey(ctx context.Context) string {
+	key, _ := ctx.Value(variantKeyType{}).(string)
+	return key
+}
+
+// variant returns the template rendered for name in ctx.
+func (e *Engine) variant(ctx context.Context, name string) string {
+	if experiment, ok := e.published().variants[name]; ok {
+		if variant, ok := experiment[variantKey(ctx)]; ok {
+			return variant
+		}
+	}
+	return name
+}
@@ -0,0 +1,80 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func Test_Variant(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.Variant("home", map[string]string{"B": "errors/404"})
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	var exposures []RenderInfo
+	engine.OnRender(func(ctx context.Context, info RenderInfo) {
+		exposures = append(exposures, info)
+	})
+
+	var buf bytes.Buffer
+	binding := map[string]interface{}{
+		"Title": "Hello, World!",
+		"Error": "Variant B",
+	}
+	if err := engine.RenderContext(WithVariant(context.Background(), "B"), &buf, "home", binding); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect := `<h1>Variant B</h1>`
+	result := trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	buf.Reset()
+	if err := engine.RenderContext(WithVariant(context.Background(), "C"), &buf, "home", binding); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<h2>Header</h2><h1>Hello, World!</h1><h2>Footer</h2>`
+	result = trim(buf.String())
+	if expect != result {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+
+	if len(exposures) != 2 || exposures[0].Variant != "B" || exposures[0].Page != "errors/404" ||
+		exposures[1].Variant != "" || exposures[1].Page != "home" {
+		t.Fatalf("Unexpected exposures: %+v\n", exposures)
+	}
+}
+
+func Test_VariantDuringRenders(t *testing.T) {
+	engine := New("./views", ".html")
+	engine.AddFunc("isAdmin", func(user string) bool {
+		return user == "admin"
+	})
+	engine.OnRender(func(ctx context.Context, info RenderInfo) {})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	// Experiments change while renders read them, go test -race reports
+	// unsynchronized accesses
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := WithVariant(context.Background(), "B")
+			for j := 0; j < 20; j++ {
+				if err := engine.RenderContext(ctx, &bytes.Buffer{}, "home", map[string]interface{}{}); err != nil {
+					t.Errorf("render: %v\n", err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		engine.Variant(fmt.Sprintf("experiment%d", i), map[string]string{"B": "home"})
+	}
+	wg.Wait()
+}
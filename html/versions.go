@@ -0,0 +1,115 @@
+package html
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"sync"
+)
+
+// templateVersion is a loaded template set kept for Rollback
+type templateVersion struct {
+	version      string
+	templates    map[string]*template.Template
+	preloads     map[string][]Preload
+	themeSets    map[string]*templateSet
+	layoutSets   map[string]*templateSet
+	pools        map[*template.Template]*templatePool
+	contextFuncs map[string]interface{}
+	funcs        map[string]interface{}
+	// *lazyRefs of the templates by *template.Template
+	lazyRefs *sync.Map
+}
+
+// snapshot returns the current template set, the caller holds the lock.
+// The set is not modified once published, later changes replace it.
+func (e *Engine) snapshot() *templateVersion {
+	return &templateVersion{
+		version:      e.version,
+		templates:    e.Templates,
+		preloads:     e.preloads,
+		themeSets:    e.themeSets,
+		layoutSets:   e.layoutSets,
+		pools:        e.pools,
+		contextFuncs: e.contextFuncs,
+		funcs:        copyMap(e.funcmap),
+		lazyRefs:     &sync.Map{},
+	}
+}
+
+// versionKey is the context key of the template set of a render
+type versionKey struct{}
+
+// renderVersion returns the template set the render of ctx started with,
+// the current one outside of renders.
+func (e *Engine) renderVersion(ctx context.Context) *templateVersion {
+	if set, ok := ctx.Value(versionKey{}).(*templateVersion); ok {
+		return set
+	}
+	if set := e.current.Load(); set != nil {
+		return set
+	}
+	return &templateVersion{}
+}
+
+// themed returns the templates of theme, the base templates if empty.
+func (v *templateVersion) themed(theme string) (*templateSet, error) {
+	if theme == "" {
+		return &templateSet{v.templates, v.preloads}, nil
+	}
+	set := v.themeSets[theme]
+	if set == nil {
+		return nil, fmt.Errorf("render: theme %s does not exist", theme)
+	}
+	return set, nil
+}
+
+// KeepVersions keeps the last n template sets replaced by a load in
+// memory for Rollback, 0 keeps none.
+func (e *Engine) KeepVersions(n int) *Engine {
+	e.mutex.Lock()
+	e.keepVersions = n
+	if len(e.history) > n {
+		e.history = e.history[len(e.history)-n:]
+	}
+	e.mutex.Unlock()
+	return e
+}
+
+// Version returns the content hash of the loaded templates, empty until
+// they load successfully.
+func (e *Engine) Version() string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.version
+}
+
+// Rollback reverts to the template set replaced by the last load, e.g.
+// when a bad deploy breaks rendering, and returns its version. The
+// templates are loaded again by the next reload, in Reload mode on the
+// next render.
+func (e *Engine) Rollback() (string, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if len(e.history) == 0 {
+		return "", errors.New("rollback: no previous version")
+	}
+	// Cached output may come from the bad templates
+	bad := context.WithValue(context.Background(), versionKey{}, e.renderVersion(context.Background()))
+	if err := e.flushCaches(bad); err != nil {
+		return "", err
+	}
+	previous := e.history[len(e.history)-1]
+	e.history = e.history[:len(e.history)-1]
+	e.version = previous.version
+	e.Templates = previous.templates
+	e.preloads = previous.preloads
+	e.themeSets = previous.themeSets
+	e.layoutSets = previous.layoutSets
+	e.pools = previous.pools
+	e.contextFuncs = previous.contextFuncs
+	e.current.Store(previous)
+	e.loaded.Store(true)
+	return e.version, nil
+}
@@ -0,0 +1,164 @@
+package html
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Rollback(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "index.html")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+	render := func(engine *Engine) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, "index", nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		return buf.String()
+	}
+	write("v1")
+	engine := New(dir, ".html").KeepVersions(2)
+	if _, err := engine.Rollback(); err == nil {
+		t.Fatalf("Expected error without previous version\n")
+	}
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	v1 := engine.Version()
+
+	// A new version replaces the set
+	write("v2")
+	engine.Reload(true)
+	if result := render(engine); result != "v2" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "v2", result)
+	}
+	v2 := engine.Version()
+	if v1 == "" || v1 == v2 {
+		t.Fatalf("Expected distinct versions, got %q and %q\n", v1, v2)
+	}
+	// Same content, same version
+	render(engine)
+	engine.Reload(false)
+
+	// A broken deploy
+	write("{{.Broken")
+	engine.SetDirectory(dir)
+	if err := engine.Load(); err == nil {
+		t.Fatalf("Expected parse error\n")
+	}
+	// The failed load leaves the live set and the history alone
+	if version := engine.Version(); version != v2 {
+		t.Fatalf("Expected version %s after a failed load, got %s\n", v2, version)
+	}
+	if result := render(engine); result != "v2" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "v2", result)
+	}
+	version, err := engine.Rollback()
+	if err != nil || version != v1 {
+		t.Fatalf("Expected rollback to %s, got %s %v\n", v1, version, err)
+	}
+	if result := render(engine); result != "v1" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "v1", result)
+	}
+	if _, err = engine.Rollback(); err == nil {
+		t.Fatalf("Expected error without previous version\n")
+	}
+}
+
+func Test_ReloadSnapshot(t *testing.T) {
+	engine := New("./testdata/snapshot/v1", ".html")
+	engine.Layout("layouts/main")
+	engine.BreadcrumbsFunc()
+	started, release := make(chan bool, 2), make(chan bool)
+	engine.AddFunc("wait", func() string {
+		started <- true
+		<-release
+		return ""
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		if err := engine.Render(&buf, "page", map[string]interface{}{"Trail": []string{"Home", "/"}}); err != nil {
+			done <- err.Error()
+			return
+		}
+		done <- trim(buf.String())
+	}()
+	<-started
+	// Reload a new set while the render is in flight
+	engine.SetDirectory("./testdata/snapshot/v2")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	close(release)
+	expect := `<main class="v1">v1 Home</main>`
+	if result := <-done; result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "page", map[string]interface{}{"Trail": []string{"Home", "/"}}); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	expect = `<main class="v2">v2 Home</main>`
+	if result := trim(buf.String()); result != expect {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", expect, result)
+	}
+}
+
+func Test_FailedLoadKeepsSet(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v\n", err)
+		}
+	}
+	write("a.html", "a")
+	write("index.html", "v1")
+	engine := New(dir, ".html")
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	version := engine.Version()
+	// a parses before the broken index
+	write("index.html", "{{.Broken")
+	engine.SetDirectory(dir)
+	var buf bytes.Buffer
+	if err := engine.Render(&buf, "index", nil); err == nil {
+		t.Fatalf("Expected parse error\n")
+	}
+	// Renders keep the last published set
+	buf.Reset()
+	if err := engine.Render(&buf, "index", nil); err != nil {
+		t.Fatalf("render: %v\n", err)
+	}
+	if result := buf.String(); result != "v1" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "v1", result)
+	}
+	// and so does the engine
+	if result := engine.Version(); result != version {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", version, result)
+	}
+	if engine.Template("index") == nil || len(engine.Names()) != 2 {
+		t.Fatalf("Expected the loaded templates, got %v\n", engine.Names())
+	}
+	if err := engine.SetTemplate("extra", template.Must(template.New("extra").Parse("extra"))); err != nil {
+		t.Fatalf("set: %v\n", err)
+	}
+	buf.Reset()
+	if err := engine.Render(&buf, "index", nil); err != nil || buf.String() != "v1" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s %v\n", "v1", buf.String(), err)
+	}
+}
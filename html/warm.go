@@ -0,0 +1,50 @@
+package html
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// WarmTarget is a page rendered into the cache ahead of the first request
+type WarmTarget struct {
+	// template to render
+	Template string
+	// binding of the render
+	Data interface{}
+	// cache key of the page, see WithCacheKey
+	Key string
+	// cache tags of the page, optional
+	Tags []string
+}
+
+// Warm renders the targets into the page cache and renders them again
+// after each load, which flushes the cache, so the first request after a
+// deploy never waits for an expensive page. Loads in Reload mode skip the
+// warming. It returns the errors of the targets that failed to render.
+func (e *Engine) Warm(targets []WarmTarget) error {
+	if e.pages == nil {
+		return errors.New("warm: RenderCache is not enabled")
+	}
+	if err := e.Load(); err != nil {
+		return err
+	}
+	e.mutex.Lock()
+	e.warmTargets = append(e.warmTargets, targets...)
+	e.mutex.Unlock()
+	return e.warm(context.Background(), targets)
+}
+
+// warm renders the targets into the page cache.
+func (e *Engine) warm(ctx context.Context, targets []WarmTarget) error {
+	var errs []error
+	for _, target := range targets {
+		if err := e.RenderContext(WithCacheKey(ctx, target.Key, target.Tags...), io.Discard, target.Template, target.Data); err != nil {
+			e.event(ctx, slog.LevelError, "views: warming failed", slog.String("template", target.Template), slog.String("key", target.Key), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("warm: %s: %v", target.Key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,51 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Warm(t *testing.T) {
+	var renders atomic.Int64
+	engine := New("./testdata/pagecache", ".html")
+	engine.AddFunc("count", func() int64 {
+		return renders.Add(1)
+	})
+	if err := engine.Warm([]WarmTarget{{Template: "counter", Key: "/counter"}}); err == nil {
+		t.Fatalf("Expected error without RenderCache\n")
+	}
+	engine.RenderCache(0, 0)
+	err := engine.Warm([]WarmTarget{
+		{Template: "counter", Key: "/counter"},
+		{Template: "missing", Key: "/missing"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "/missing") {
+		t.Fatalf("Expected warming error of /missing, got %v\n", err)
+	}
+	render := func() string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.RenderContext(WithCacheKey(context.Background(), "/counter"), &buf, "counter", nil); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		return trim(buf.String())
+	}
+	if result := render(); result != "<p>1</p>" {
+		t.Fatalf("Expected the warmed page\nResult:\n%s\n", result)
+	}
+
+	// Loads warm the cache again
+	engine.AddFunc("unused", strings.ToUpper)
+	if err := engine.Load(); err != nil {
+		t.Fatalf("load: %v\n", err)
+	}
+	if renders.Load() != 2 {
+		t.Fatalf("Expected the page to be warmed after the load, got %d renders\n", renders.Load())
+	}
+	if result := render(); result != "<p>2</p>" {
+		t.Fatalf("Expected:\n%s\nResult:\n%s\n", "<p>2</p>", result)
+	}
+}
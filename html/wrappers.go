@@ -0,0 +1,162 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"strings"
+	"text/template/parse"
+)
+
+// names of the funcs the content block of the layout calls with wrappers
+const (
+	wrappingFunc = "_htmlWrapping"
+	wrapFunc     = "_htmlWrap"
+)
+
+// wrappersKey is the context key of the wrappers of a render
+type wrappersKey struct{}
+
+// wrapKey is the context key of the wrappers being rendered
+type wrapKey struct{}
+
+// wrapFrame is the state of the wrappers of a render
+type wrapFrame struct {
+	tmpl  *template.Template
+	names []string
+	// content the wrapper being rendered wraps
+	inner template.HTML
+}
+
+// Wrappers sets the folder of the wrapper templates, e.g. "wrappers",
+// which WithWrappers places around the page inside the layout. A wrapper
+// renders the content it wraps with {{wrapped}}, wrappers/sidebar.html
+// is named "sidebar". Wrappers replace the content block of the layout,
+// they do not apply to partial renders or engines without a layout.
+func (e *Engine) Wrappers(dir string) *Engine {
+	e.mutex.Lock()
+	e.wrappers = strings.Trim(dir, "/")
+	e.loaded.Store(false)
+	e.mutex.Unlock()
+	return e
+}
+
+// WithWrappers returns a copy of ctx rendering the wrappers names around
+// the page, added to the wrappers already in ctx. The first wrapper is the
+// outermost.
+func WithWrappers(ctx context.Context, names ...string) context.Context {
+	current := wrappersOf(ctx)
+	stack := make([]string, 0, len(current)+len(names))
+	stack = append(append(stack, current...), names...)
+	return context.WithValue(ctx, wrappersKey{}, stack)
+}
+
+// wrappersOf returns the wrappers of ctx.
+func wrappersOf(ctx context.Context) []string {
+	names, _ := ctx.Value(wrappersKey{}).([]string)
+	return names
+}
+
+// wrapping tells whether the render of ctx has wrappers.
+func wrapping(ctx context.Context) bool {
+	_, ok := ctx.Value(wrapKey{}).(*wrapFrame)
+	return ok
+}
+
+// wrap renders the content block inside the wrappers of ctx.
+func wrap(ctx context.Context, data ...interface{}) (template.HTML, error) {
+	frame, ok := ctx.Value(wrapKey{}).(*wrapFrame)
+	if !ok {
+		return "", fmt.Errorf("wrappers: rendered outside of the engine")
+	}
+	var binding interface{}
+	if len(data) > 0 {
+		binding = data[0]
+	}
+	var buf strings.Builder
+	if err := frame.tmpl.ExecuteTemplate(&buf, "content", binding); err != nil {
+		return "", err
+	}
+	inner := template.HTML(buf.String())
+	for i := len(frame.names) - 1; i >= 0; i-- {
+		name := frame.names[i]
+		if frame.tmpl.Lookup(name) == nil {
+			return "", fmt.Errorf("wrappers: wrapper %s does not exist", name)
+		}
+		frame.inner = inner
+		buf.Reset()
+		if err := frame.tmpl.ExecuteTemplate(&buf, name, binding); err != nil {
+			return "", err
+		}
+		inner = template.HTML(buf.String())
+	}
+	return inner, nil
+}
+
+// wrapped returns the content the wrapper being rendered wraps.
+func wrapped(ctx context.Context) template.HTML {
+	if frame, ok := ctx.Value(wrapKey{}).(*wrapFrame); ok {
+		return frame.inner
+	}
+	return ""
+}
+
+// instrumentWrap replaces the content includes of the layout parsed into
+// tmpl with {{if wrapping}}{{wrap pipe}}{{else}}include{{end}}.
+func instrumentWrap(tmpl *template.Template) error {
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if err := wrapList(t.Tree.Root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func wrapList(list *parse.ListNode) error {
+	if list == nil {
+		return nil
+	}
+	for i, node := range list.Nodes {
+		var err error
+		switch n := node.(type) {
+		case *parse.IfNode:
+			err = wrapBranch(&n.BranchNode)
+		case *parse.RangeNode:
+			err = wrapBranch(&n.BranchNode)
+		case *parse.WithNode:
+			err = wrapBranch(&n.BranchNode)
+		case *parse.ListNode:
+			err = wrapList(n)
+		case *parse.TemplateNode:
+			if n.Name != "content" {
+				continue
+			}
+			src := fmt.Sprintf("{{if %s}}{{%s}}{{else}}{{end}}", wrappingFunc, wrapFunc)
+			parsed, perr := parse.Parse("wrap", src, "{{", "}}", map[string]interface{}{wrappingFunc: wrapping, wrapFunc: wrap})
+			if perr != nil {
+				return perr
+			}
+			branch := parsed["wrap"].Root.Nodes[0].(*parse.IfNode)
+			if n.Pipe != nil {
+				call := branch.List.Nodes[0].(*parse.ActionNode).Pipe.Cmds[0]
+				call.Args = append(call.Args, n.Pipe)
+			}
+			branch.ElseList.Nodes = []parse.Node{n}
+			list.Nodes[i] = branch
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func wrapBranch(branch *parse.BranchNode) error {
+	if err := wrapList(branch.List); err != nil {
+		return err
+	}
+	return wrapList(branch.ElseList)
+}
@@ -0,0 +1,34 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_Wrappers(t *testing.T) {
+	engine := New("./testdata/wrappers", ".html")
+	engine.Layout("layouts/main").Wrappers("wrappers")
+	binding := map[string]interface{}{"Title": "Shop", "Menu": "menu"}
+	tests := []struct {
+		ctx    context.Context
+		expect string
+	}{
+		{context.Background(), `<body><h1>Shop</h1></body>`},
+		{WithWrappers(context.Background(), "sidebar"), `<body><div class="sidebar"><aside>menu</aside><h1>Shop</h1></div></body>`},
+		{WithWrappers(WithWrappers(context.Background(), "sidebar"), "narrow"), `<body><div class="sidebar"><aside>menu</aside><main><h1>Shop</h1></main></div></body>`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := engine.RenderContext(tt.ctx, &buf, "index", binding); err != nil {
+			t.Fatalf("render: %v\n", err)
+		}
+		if result := trim(buf.String()); result != tt.expect {
+			t.Fatalf("Expected:\n%s\nResult:\n%s\n", tt.expect, result)
+		}
+	}
+	var buf bytes.Buffer
+	if err := engine.RenderContext(WithWrappers(context.Background(), "missing"), &buf, "index", binding); err == nil {
+		t.Fatalf("Expected missing wrapper error\n")
+	}
+}